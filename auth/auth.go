@@ -1,16 +1,23 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"freestealer/database"
+	"freestealer/middleware"
 	"freestealer/models"
+	"freestealer/username"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/sessions"
@@ -24,6 +31,8 @@ import (
 var (
 	// Session store for managing user sessions
 	store *sessions.CookieStore
+	// Session store for the OAuth round trip through GitHub
+	oauthStore *sessions.CookieStore
 	// JWT secret key
 	jwtSecret []byte
 	// JWT token expiration duration
@@ -37,6 +46,7 @@ type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -68,6 +78,12 @@ type RegisterRequest struct {
 	Password string `json:"password"`
 }
 
+// ChangePasswordRequest represents a password change request
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
 // HashPassword creates a bcrypt hash of the password
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -88,8 +104,7 @@ func InitAuth() {
 		sessionSecret = "default-secret-change-in-production"
 		log.Warn("SESSION_SECRET not set, using default (not secure for production)")
 	}
-	store = sessions.NewCookieStore([]byte(sessionSecret))
-	gothic.Store = store
+	initSessionStores(sessionSecret)
 
 	// Initialize JWT secret
 	jwtSecretStr := os.Getenv("JWT_SECRET")
@@ -120,8 +135,47 @@ func InitAuth() {
 	log.Info("Authentication initialized with GitHub OAuth and JWT")
 }
 
+// oauthStateMaxAge bounds how long a GitHub login may take, in seconds
+const oauthStateMaxAge = 10 * 60
+
+// initSessionStores creates the cookie stores. Cookies are hidden from
+// JavaScript and sent only over HTTPS when TLS_ENABLED=true. The login session
+// is SameSite=Strict; the OAuth state and gothic's session use Lax because
+// browsers do not send Strict cookies on the redirect back from GitHub.
+func initSessionStores(secret string) {
+	secure := os.Getenv("TLS_ENABLED") == "true"
+
+	store = sessions.NewCookieStore([]byte(secret))
+	store.Options = &sessions.Options{
+		Path:     "/",
+		MaxAge:   86400 * 30,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	}
+
+	oauthStore = sessions.NewCookieStore([]byte(secret))
+	oauthStore.Options = &sessions.Options{
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	oauthStore.MaxAge(oauthStateMaxAge)
+	gothic.Store = oauthStore
+}
+
 // GenerateTokens creates new JWT access and refresh tokens for a user
 func GenerateTokens(user *models.User) (*TokenResponse, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return generateTokens(user, jti)
+}
+
+// generateTokens creates an access and refresh token pair sharing the given jti
+func generateTokens(user *models.User, jti string) (*TokenResponse, error) {
 	now := time.Now()
 
 	// Create access token claims
@@ -129,12 +183,14 @@ func GenerateTokens(user *models.User) (*TokenResponse, error) {
 		UserID:   user.ID,
 		Username: user.Username,
 		Email:    user.Email,
+		Role:     user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(jwtExpiration)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "freestealer",
 			Subject:   fmt.Sprintf("%d", user.ID),
+			ID:        jti,
 		},
 	}
 
@@ -154,7 +210,7 @@ func GenerateTokens(user *models.User) (*TokenResponse, error) {
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "freestealer",
 			Subject:   fmt.Sprintf("%d", user.ID),
-			ID:        fmt.Sprintf("%d-%d", user.ID, now.Unix()),
+			ID:        jti,
 		},
 	}
 
@@ -173,6 +229,19 @@ func GenerateTokens(user *models.User) (*TokenResponse, error) {
 	}, nil
 }
 
+// clockSkew returns the leeway allowed on the exp, nbf and iat claims for
+// clocks that drift between servers, from CLOCK_SKEW_SECONDS (default 0)
+func clockSkew() time.Duration {
+	if v := os.Getenv("CLOCK_SKEW_SECONDS"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		log.WithField("value", v).Warn("Invalid CLOCK_SKEW_SECONDS, allowing no clock skew")
+	}
+	return 0
+}
+
 // ValidateToken validates a JWT token and returns the claims
 func ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -180,7 +249,7 @@ func ValidateToken(tokenString string) (*Claims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return jwtSecret, nil
-	})
+	}, jwt.WithLeeway(clockSkew()))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -215,7 +284,7 @@ func ExtractTokenFromHeader(r *http.Request) (string, error) {
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body RefreshTokenRequest true "Refresh token"
+// @Param request body RefreshTokenRequest true "Refresh token returned by /auth/login, /auth/register or a previous refresh"
 // @Success 200 {object} TokenResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -234,6 +303,11 @@ func RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isTokenRevoked(claims.ID) {
+		http.Error(w, "Refresh token has been revoked", http.StatusUnauthorized)
+		return
+	}
+
 	// Get user from database
 	var user models.User
 	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
@@ -241,13 +315,26 @@ func RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate new tokens
-	tokens, err := GenerateTokens(&user)
+	// Generate new tokens, keeping the existing session when there is one
+	var tokens *TokenResponse
+	if claims.ID != "" {
+		tokens, err = generateTokens(&user, claims.ID)
+		if err == nil {
+			now := time.Now()
+			database.DB.Model(&models.Session{}).Where("jti = ?", claims.ID).Updates(map[string]interface{}{
+				"last_used_at": now,
+				"expires_at":   now.Add(refreshExpiration),
+			})
+		}
+	} else {
+		tokens, err = startSession(r, &user)
+	}
 	if err != nil {
 		log.WithError(err).Error("Failed to generate tokens")
 		http.Error(w, "Failed to generate tokens", http.StatusInternalServerError)
 		return
 	}
+	recordLogin(&user)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(tokens); err != nil {
@@ -316,14 +403,19 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate JWT tokens
-	tokens, err := GenerateTokens(&user)
+	tokens, err := startSession(r, &user)
 	if err != nil {
 		log.WithError(err).Error("Failed to generate tokens")
 		http.Error(w, "Failed to generate tokens", http.StatusInternalServerError)
 		return
 	}
 
-	log.WithField("user_id", user.ID).Info("User logged in via direct login")
+	recordLogin(&user)
+
+	log.WithFields(log.Fields{
+		"user_id":    user.ID,
+		"ip_address": middleware.GetClientIP(r),
+	}).Info("User logged in via direct login")
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
@@ -349,6 +441,7 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 // @Param request body RegisterRequest true "Registration details"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Router /auth/register [post]
 func RegisterHandler(w http.ResponseWriter, r *http.Request) {
@@ -370,6 +463,17 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := username.ValidateUsername(req.Username); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Apply the configured email domain allowlist and blocklist
+	if status, message := CheckEmailDomain(req.Email); status != 0 {
+		http.Error(w, message, status)
+		return
+	}
+
 	// Check if user already exists
 	var existingUser models.User
 	if err := database.DB.Where("email = ? OR username = ?", req.Email, req.Username).First(&existingUser).Error; err == nil {
@@ -399,7 +503,7 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate JWT tokens
-	tokens, err := GenerateTokens(&user)
+	tokens, err := startSession(r, &user)
 	if err != nil {
 		log.WithError(err).Error("Failed to generate tokens")
 		http.Error(w, "Failed to generate tokens", http.StatusInternalServerError)
@@ -426,6 +530,100 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ChangePasswordHandler handles password changes for the authenticated user
+// @Summary Change password
+// @Description Change the password after verifying the current one. All existing sessions are revoked and new tokens are returned
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/change-password [post]
+func ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	// OAuth-only accounts have no password to change
+	if user.Password == "" {
+		http.Error(w, "Password change is not available for accounts without a password", http.StatusBadRequest)
+		return
+	}
+
+	if !CheckPasswordHash(req.CurrentPassword, user.Password) {
+		log.WithField("user_id", user.ID).Warn("Password change failed: invalid current password")
+		http.Error(w, "Current password is incorrect", http.StatusUnauthorized)
+		return
+	}
+
+	if len(req.NewPassword) < 8 {
+		http.Error(w, "New password must be at least 8 characters", http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := HashPassword(req.NewPassword)
+	if err != nil {
+		log.WithError(err).Error("Failed to hash password")
+		http.Error(w, "Failed to change password", http.StatusInternalServerError)
+		return
+	}
+
+	if err := database.DB.Model(&user).Update("password", hashedPassword).Error; err != nil {
+		log.WithError(err).Error("Failed to update password")
+		http.Error(w, "Failed to change password", http.StatusInternalServerError)
+		return
+	}
+
+	// Invalidate every token issued with the old password
+	if err := RevokeAllSessions(user.ID); err != nil {
+		log.WithError(err).Error("Failed to revoke sessions")
+		http.Error(w, "Failed to change password", http.StatusInternalServerError)
+		return
+	}
+	if claims.ID != "" {
+		if err := revokeToken(claims.ID, user.ID, time.Now().Add(refreshExpiration)); err != nil {
+			log.WithError(err).Error("Failed to revoke current token")
+		}
+	}
+
+	tokens, err := startSession(r, &user)
+	if err != nil {
+		log.WithError(err).Error("Failed to generate tokens")
+		http.Error(w, "Failed to generate tokens", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithField("user_id", user.ID).Info("Password changed")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		log.WithError(err).Error("Failed to encode token response")
+	}
+}
+
 // BeginAuthHandler initiates GitHub OAuth flow
 // @Summary Start GitHub OAuth login
 // @Description Redirects user to GitHub for authentication
@@ -435,14 +633,59 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 // @Success 302 {string} string "Redirect to GitHub"
 // @Router /auth/github [get]
 func BeginAuthHandler(w http.ResponseWriter, r *http.Request) {
-	// Set provider name in query params for gothic
+	// Generate an OAuth state token and remember it for the callback
+	state, err := newOAuthState()
+	if err != nil {
+		log.WithError(err).Error("Failed to generate OAuth state")
+		http.Error(w, "Failed to start authentication", http.StatusInternalServerError)
+		return
+	}
+
+	session, err := oauthStore.Get(r, "oauth-session")
+	if err != nil {
+		log.WithError(err).Error("Failed to get session")
+		http.Error(w, "Session error", http.StatusInternalServerError)
+		return
+	}
+	session.Values["oauth_state"] = state
+	if err := session.Save(r, w); err != nil {
+		log.WithError(err).Error("Failed to save session")
+		http.Error(w, "Session error", http.StatusInternalServerError)
+		return
+	}
+
+	// Set provider name and state in query params for gothic
 	q := r.URL.Query()
 	q.Add("provider", "github")
+	q.Set("state", state)
 	r.URL.RawQuery = q.Encode()
 
 	gothic.BeginAuthHandler(w, r)
 }
 
+// newOAuthState generates a random OAuth state token
+func newOAuthState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validOAuthState reports whether the callback state matches the one stored in the session
+func validOAuthState(r *http.Request) bool {
+	session, err := oauthStore.Get(r, "oauth-session")
+	if err != nil {
+		return false
+	}
+	expected, ok := session.Values["oauth_state"].(string)
+	state := r.URL.Query().Get("state")
+	if !ok || expected == "" || state == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(state), []byte(expected)) == 1
+}
+
 // CallbackHandler handles GitHub OAuth callback
 // @Summary GitHub OAuth callback
 // @Description Handles the callback from GitHub after authentication
@@ -453,6 +696,7 @@ func BeginAuthHandler(w http.ResponseWriter, r *http.Request) {
 // @Param state query string true "OAuth state"
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /auth/github/callback [get]
 func CallbackHandler(w http.ResponseWriter, r *http.Request) {
@@ -463,6 +707,13 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 		r.URL.RawQuery = q.Encode()
 	}
 
+	// Reject callbacks whose state does not match the one issued by BeginAuthHandler
+	if !validOAuthState(r) {
+		log.Warn("OAuth callback state mismatch")
+		http.Error(w, "Invalid OAuth state", http.StatusForbidden)
+		return
+	}
+
 	// Complete authentication
 	user, err := gothic.CompleteUserAuth(w, r)
 	if err != nil {
@@ -472,14 +723,22 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.WithFields(log.Fields{
-		"github_id": user.UserID,
-		"email":     user.Email,
-		"name":      user.Name,
+		"github_id":  user.UserID,
+		"email":      user.Email,
+		"name":       user.Name,
+		"ip_address": middleware.GetClientIP(r),
 	}).Info("User authenticated via GitHub")
 
+	// Extra profile fields come from the GitHub API; a failure here only
+	// leaves them as they were
+	profile, err := fetchGitHubProfile(user.AccessToken)
+	if err != nil {
+		log.WithError(err).WithField("github_id", user.UserID).Warn("Failed to fetch GitHub profile")
+	}
+
 	// Find or create user in database
 	var dbUser models.User
-	result := database.DB.Where("github_id = ?", user.UserID).First(&dbUser)
+	result := database.DB.Where("git_hub_id = ?", user.UserID).First(&dbUser)
 
 	if result.Error != nil {
 		// User doesn't exist, create new user
@@ -497,6 +756,9 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 			AccessToken:  user.AccessToken,
 			RefreshToken: user.RefreshToken,
 		}
+		if profile != nil {
+			applyGitHubProfile(&dbUser, profile)
+		}
 
 		if err := database.DB.Create(&dbUser).Error; err != nil {
 			log.WithError(err).Error("Failed to create user")
@@ -510,6 +772,9 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 		dbUser.AccessToken = user.AccessToken
 		dbUser.RefreshToken = user.RefreshToken
 		dbUser.AvatarURL = user.AvatarURL
+		if profile != nil {
+			applyGitHubProfile(&dbUser, profile)
+		}
 		database.DB.Save(&dbUser)
 
 		log.WithField("user_id", dbUser.ID).Info("Existing user logged in")
@@ -528,13 +793,22 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 		log.WithError(err).Error("Failed to save session")
 	}
 
+	// The OAuth state is single use
+	if oauthSession, err := oauthStore.Get(r, "oauth-session"); err == nil {
+		oauthSession.Options.MaxAge = -1
+		if err := oauthSession.Save(r, w); err != nil {
+			log.WithError(err).Error("Failed to clear OAuth session")
+		}
+	}
+
 	// Generate JWT tokens
-	tokens, err := GenerateTokens(&dbUser)
+	tokens, err := startSession(r, &dbUser)
 	if err != nil {
 		log.WithError(err).Error("Failed to generate JWT tokens")
 		http.Error(w, "Failed to generate tokens", http.StatusInternalServerError)
 		return
 	}
+	recordLogin(&dbUser)
 
 	// Return user info and JWT tokens
 	w.Header().Set("Content-Type", "application/json")
@@ -552,15 +826,45 @@ func CallbackHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// revokeBearerToken blacklists the JWT in the Authorization header, if any,
+// together with its session. Missing, expired and otherwise invalid tokens
+// cannot be used anyway and are ignored.
+func revokeBearerToken(r *http.Request) error {
+	claims, err := currentClaims(r)
+	if err != nil || claims.ID == "" {
+		return nil
+	}
+
+	var session models.Session
+	if err := database.DB.Where("jti = ?", claims.ID).First(&session).Error; err == nil {
+		return revokeSession(&session)
+	}
+
+	expiresAt := time.Now().Add(refreshExpiration)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return revokeToken(claims.ID, claims.UserID, expiresAt)
+}
+
 // LogoutHandler handles user logout
 // @Summary Logout user
-// @Description Destroys the user session
+// @Description Destroys the user session and, when a bearer token is sent, blacklists it and its refresh token. Succeeds without a token or with an expired one
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /auth/logout [get]
+// @Router /auth/logout [post]
 func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if err := revokeBearerToken(r); err != nil {
+		log.WithError(err).Error("Failed to revoke token on logout")
+		http.Error(w, "Failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+
 	session, err := store.Get(r, "auth-session")
 	if err != nil {
 		log.WithError(err).Error("Failed to get session")
@@ -580,15 +884,34 @@ func LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// UserMeResponse is the current user with their contribution counts
+type UserMeResponse struct {
+	models.User
+	TiersCount     int `json:"tiers_count"`
+	VotesCast      int `json:"votes_cast"`
+	CommentsCount  int `json:"comments_count"`
+	BookmarksCount int `json:"bookmarks_count"`
+}
+
+// userCountsQuery counts a user's tiers, votes, comments and bookmarks in one round trip
+const userCountsQuery = `
+SELECT
+	(SELECT COUNT(*) FROM tiers WHERE user_id = @id AND deleted_at IS NULL) AS tiers_count,
+	(SELECT COUNT(*) FROM votes WHERE user_id = @id AND deleted_at IS NULL) AS votes_cast,
+	(SELECT COUNT(*) FROM comments WHERE user_id = @id AND deleted_at IS NULL) AS comments_count,
+	(SELECT COUNT(*) FROM bookmarks WHERE user_id = @id) AS bookmarks_count`
+
 // GetCurrentUser returns the currently authenticated user
 // @Summary Get current user
-// @Description Get the currently authenticated user's information (supports both session and JWT)
+// @Description Get the currently authenticated user's information with the number of tiers, votes, comments and bookmarks they have made (supports both session and JWT)
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param Authorization header string false "Bearer JWT token"
-// @Success 200 {object} models.User
+// @Success 200 {object} UserMeResponse
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /auth/me [get]
 func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	var userID uint
@@ -597,7 +920,7 @@ func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	tokenString, err := ExtractTokenFromHeader(r)
 	if err == nil {
 		claims, err := ValidateToken(tokenString)
-		if err == nil {
+		if err == nil && !isTokenRevoked(claims.ID) {
 			userID = claims.UserID
 		}
 	}
@@ -621,14 +944,30 @@ func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
+	response := UserMeResponse{}
+	if err := database.DB.First(&response.User, userID).Error; err != nil {
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
 	}
 
+	var counts struct {
+		TiersCount     int
+		VotesCast      int
+		CommentsCount  int
+		BookmarksCount int
+	}
+	if err := database.DB.Raw(userCountsQuery, sql.Named("id", userID)).Scan(&counts).Error; err != nil {
+		log.WithError(err).Error("Failed to count user contributions")
+		http.Error(w, "Failed to fetch user", http.StatusInternalServerError)
+		return
+	}
+	response.TiersCount = counts.TiersCount
+	response.VotesCast = counts.VotesCast
+	response.CommentsCount = counts.CommentsCount
+	response.BookmarksCount = counts.BookmarksCount
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(user); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.WithError(err).Error("Failed to encode user response")
 	}
 }
@@ -642,7 +981,7 @@ func RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 		tokenString, err := ExtractTokenFromHeader(r)
 		if err == nil {
 			claims, err := ValidateToken(tokenString)
-			if err == nil {
+			if err == nil && !isTokenRevoked(claims.ID) {
 				userID = claims.UserID
 			}
 		}
@@ -666,9 +1005,8 @@ func RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Add user_id to request context or header for handlers to use
-		r.Header.Set("X-User-ID", fmt.Sprintf("%d", userID))
-		next(w, r)
+		// Pass the user on to handlers in the request context
+		next(w, r.WithContext(WithUserID(r.Context(), userID)))
 	}
 }
 
@@ -687,8 +1025,27 @@ func RequireJWTAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Add user_id to request header for handlers to use
-		r.Header.Set("X-User-ID", fmt.Sprintf("%d", claims.UserID))
+		if isTokenRevoked(claims.ID) {
+			http.Error(w, "Token has been revoked", http.StatusUnauthorized)
+			return
+		}
+
+		// Pass the user and role on to handlers in the request context
+		next(w, r.WithContext(withClaims(r.Context(), claims)))
+	}
+}
+
+// OptionalJWTAuth middleware for public routes. A valid token, if any, puts
+// the user in the request context as RequireJWTAuth does; otherwise the
+// request stays anonymous.
+func OptionalJWTAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if tokenString, err := ExtractTokenFromHeader(r); err == nil {
+			claims, err := ValidateToken(tokenString)
+			if err == nil && !isTokenRevoked(claims.ID) {
+				r = r.WithContext(withClaims(r.Context(), claims))
+			}
+		}
 		next(w, r)
 	}
 }
@@ -1,19 +1,26 @@
 package auth
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"freestealer/database"
 	"freestealer/models"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/gorilla/sessions"
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/faux"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -61,7 +68,7 @@ func setupTestDB(t *testing.T) {
 	database.DB.Exec("CREATE SCHEMA public")
 
 	// Auto-migrate the schema
-	err = database.DB.AutoMigrate(&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{})
+	err = database.DB.AutoMigrate(&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{}, &models.CommentReaction{}, &models.Notification{}, &models.Session{}, &models.RevokedToken{}, &models.Collection{}, &models.CollectionTier{}, &models.Platform{}, &models.VoteSnapshot{}, &models.Bookmark{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -75,7 +82,7 @@ func setupTestAuth() {
 	os.Setenv("GITHUB_CLIENT_SECRET", "test-client-secret")
 	os.Setenv("GITHUB_CALLBACK_URL", "http://localhost:5050/auth/github/callback")
 
-	store = sessions.NewCookieStore([]byte("test-secret"))
+	initSessionStores("test-secret")
 	SetJWTSecret("test-jwt-secret")
 }
 
@@ -111,6 +118,134 @@ func TestBeginAuthHandler(t *testing.T) {
 	assert.Contains(t, req.URL.RawQuery, "provider=github")
 }
 
+func TestBeginAuthHandler_SetsState(t *testing.T) {
+	setupTestAuth()
+
+	req := httptest.NewRequest("GET", "/auth/github", nil)
+	w := httptest.NewRecorder()
+
+	BeginAuthHandler(w, req)
+
+	state := req.URL.Query().Get("state")
+	assert.NotEmpty(t, state)
+
+	// The state is stored in the session cookie for the callback
+	callbackReq := httptest.NewRequest("GET", "/auth/github/callback?state="+state, nil)
+	for _, cookie := range w.Result().Cookies() {
+		callbackReq.AddCookie(cookie)
+	}
+	assert.True(t, validOAuthState(callbackReq))
+}
+
+func TestCallbackHandler_StateMismatch(t *testing.T) {
+	setupTestAuth()
+
+	beginReq := httptest.NewRequest("GET", "/auth/github", nil)
+	beginW := httptest.NewRecorder()
+	BeginAuthHandler(beginW, beginReq)
+
+	req := httptest.NewRequest("GET", "/auth/github/callback?code=abc&state=forged", nil)
+	for _, cookie := range beginW.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+
+	CallbackHandler(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCallbackHandler_MissingState(t *testing.T) {
+	setupTestAuth()
+
+	req := httptest.NewRequest("GET", "/auth/github/callback?code=abc", nil)
+	w := httptest.NewRecorder()
+
+	CallbackHandler(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCallbackHandler_ExistingUser(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	// The faux provider completes the OAuth exchange without calling GitHub
+	// and reports the user with GitHub ID "id"
+	goth.UseProviders(&faux.Provider{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"company": "@github", "location": "San Francisco", "public_repos": 8}`)
+	}))
+	defer srv.Close()
+	original := githubUserURL
+	githubUserURL = srv.URL
+	defer func() { githubUserURL = original }()
+
+	existing := models.User{Username: "octocat", Email: "octocat@example.com", GitHubID: "id", Company: "Old Co"}
+	assert.NoError(t, database.DB.Create(&existing).Error)
+
+	beginReq := httptest.NewRequest("GET", "/auth/github?provider=faux", nil)
+	beginW := httptest.NewRecorder()
+	BeginAuthHandler(beginW, beginReq)
+
+	state := beginReq.URL.Query().Get("state")
+	req := httptest.NewRequest("GET", "/auth/github/callback?provider=faux&code=abc&state="+state, nil)
+	for _, cookie := range beginW.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+
+	CallbackHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The existing account is found by git_hub_id and updated in place
+	var users []models.User
+	database.DB.Where("git_hub_id = ?", "id").Find(&users)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, existing.ID, users[0].ID)
+		assert.Equal(t, "@github", users[0].Company)
+		assert.Equal(t, "San Francisco", users[0].Location)
+		assert.Equal(t, 8, users[0].PublicRepos)
+		assert.Equal(t, "access", users[0].AccessToken)
+	}
+}
+
+func TestSessionCookieAttributes(t *testing.T) {
+	setupTestAuth()
+
+	// The login session cookie, as written by the GitHub callback
+	loginCookie := func() string {
+		req := httptest.NewRequest("GET", "/auth/github/callback", nil)
+		w := httptest.NewRecorder()
+		session, _ := store.Get(req, "auth-session")
+		session.Values["user_id"] = uint(1)
+		assert.NoError(t, session.Save(req, w))
+		return w.Header().Get("Set-Cookie")
+	}
+
+	cookie := loginCookie()
+	assert.Contains(t, cookie, "HttpOnly")
+	assert.Contains(t, cookie, "SameSite=Strict")
+	assert.Contains(t, cookie, "Path=/")
+	assert.NotContains(t, cookie, "Secure")
+
+	// The OAuth state must survive the cross-site redirect from GitHub
+	req := httptest.NewRequest("GET", "/auth/github", nil)
+	w := httptest.NewRecorder()
+	BeginAuthHandler(w, req)
+	assert.NotEmpty(t, w.Header().Values("Set-Cookie"))
+	for _, c := range w.Header().Values("Set-Cookie") {
+		assert.Contains(t, c, "HttpOnly")
+		assert.Contains(t, c, "SameSite=Lax")
+	}
+
+	t.Setenv("TLS_ENABLED", "true")
+	setupTestAuth()
+	assert.Contains(t, loginCookie(), "Secure")
+}
+
 func TestLogoutHandler(t *testing.T) {
 	setupTestAuth()
 
@@ -140,6 +275,53 @@ func TestLogoutHandler(t *testing.T) {
 	assert.Equal(t, "Logged out successfully", response["message"])
 }
 
+func TestLogoutHandler_ExpiredToken(t *testing.T) {
+	setupTestAuth()
+
+	claims := &Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-1 * time.Hour)),
+			ID:        "expired-jti",
+		},
+	}
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-jwt-secret"))
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	w := httptest.NewRecorder()
+
+	LogoutHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLogoutHandler_RevokesToken(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "logoutuser", Email: "logout@example.com"}
+	database.DB.Create(&user)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	tokens, err := startSession(req, &user)
+	assert.NoError(t, err)
+	claims := mustClaims(t, tokens.AccessToken)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+
+	LogoutHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, isTokenRevoked(claims.ID))
+
+	var sessions int64
+	database.DB.Model(&models.Session{}).Where("jti = ?", claims.ID).Count(&sessions)
+	assert.Equal(t, int64(0), sessions)
+}
+
 func TestGetCurrentUser_NotAuthenticated(t *testing.T) {
 	setupTestDB(t)
 	setupTestAuth()
@@ -166,6 +348,17 @@ func TestGetCurrentUser_Authenticated(t *testing.T) {
 	}
 	database.DB.Create(&user)
 
+	// Contributions counted in the response; deleted ones are left out
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Mine"}
+	database.DB.Create(&tier)
+	deleted := models.Tier{UserID: user.ID, Platform: "Render", Name: "Deleted"}
+	database.DB.Create(&deleted)
+	database.DB.Delete(&deleted)
+	database.DB.Create(&models.Vote{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+	database.DB.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "First"})
+	database.DB.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Second"})
+	database.DB.Create(&models.Bookmark{UserID: user.ID, TierID: tier.ID})
+
 	req := httptest.NewRequest("GET", "/auth/me", nil)
 	w := httptest.NewRecorder()
 
@@ -185,11 +378,15 @@ func TestGetCurrentUser_Authenticated(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response models.User
+	var response UserMeResponse
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Equal(t, "testuser", response.Username)
 	assert.Equal(t, "test@example.com", response.Email)
+	assert.Equal(t, 1, response.TiersCount)
+	assert.Equal(t, 1, response.VotesCast)
+	assert.Equal(t, 2, response.CommentsCount)
+	assert.Equal(t, 1, response.BookmarksCount)
 }
 
 func TestGetCurrentUser_UserNotFound(t *testing.T) {
@@ -250,7 +447,9 @@ func TestRequireAuth_Authenticated(t *testing.T) {
 	}
 	w = httptest.NewRecorder()
 
+	var userID uint
 	handler := RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = UserIDFromContext(r.Context())
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Protected content"))
 	})
@@ -259,7 +458,7 @@ func TestRequireAuth_Authenticated(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "Protected content", w.Body.String())
-	assert.Equal(t, "1", req.Header.Get("X-User-ID"))
+	assert.Equal(t, uint(1), userID)
 }
 
 func TestRequireAuth_InvalidUserID(t *testing.T) {
@@ -359,6 +558,42 @@ func TestValidateToken_Expired(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestValidateToken_ClockSkew(t *testing.T) {
+	setupTestAuth()
+
+	// Issued by a server whose clock runs 2 seconds ahead
+	now := time.Now()
+	claims := &Claims{
+		UserID:   1,
+		Username: "testuser",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now.Add(2 * time.Second)),
+			NotBefore: jwt.NewNumericDate(now.Add(2 * time.Second)),
+		},
+	}
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-jwt-secret"))
+
+	t.Run("Rejected without leeway", func(t *testing.T) {
+		t.Setenv("CLOCK_SKEW_SECONDS", "")
+		_, err := ValidateToken(tokenString)
+		assert.ErrorIs(t, err, jwt.ErrTokenNotValidYet)
+	})
+
+	t.Run("Accepted with 5 seconds of leeway", func(t *testing.T) {
+		t.Setenv("CLOCK_SKEW_SECONDS", "5")
+		validated, err := ValidateToken(tokenString)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), validated.UserID)
+	})
+
+	t.Run("Invalid setting allows no skew", func(t *testing.T) {
+		t.Setenv("CLOCK_SKEW_SECONDS", "-5")
+		_, err := ValidateToken(tokenString)
+		assert.ErrorIs(t, err, jwt.ErrTokenNotValidYet)
+	})
+}
+
 func TestExtractTokenFromHeader_Valid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
@@ -412,7 +647,9 @@ func TestRequireJWTAuth_Valid(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
 	w := httptest.NewRecorder()
 
+	var userID uint
 	handler := RequireJWTAuth(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = UserIDFromContext(r.Context())
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Protected content"))
 	})
@@ -421,7 +658,7 @@ func TestRequireJWTAuth_Valid(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "Protected content", w.Body.String())
-	assert.Equal(t, "1", req.Header.Get("X-User-ID"))
+	assert.Equal(t, uint(1), userID)
 }
 
 func TestRequireJWTAuth_NoToken(t *testing.T) {
@@ -501,7 +738,9 @@ func TestRequireAuth_WithJWT(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
 	w := httptest.NewRecorder()
 
+	var userID uint
 	handler := RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = UserIDFromContext(r.Context())
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Protected content"))
 	})
@@ -509,7 +748,7 @@ func TestRequireAuth_WithJWT(t *testing.T) {
 	handler(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "1", req.Header.Get("X-User-ID"))
+	assert.Equal(t, uint(1), userID)
 }
 
 func TestRefreshTokenHandler_Valid(t *testing.T) {
@@ -627,6 +866,28 @@ func TestLoginHandler_WithEmail(t *testing.T) {
 	assert.NotNil(t, response["user"])
 }
 
+func TestLoginHandler_RecordsLastLogin(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "lastlogin", Email: "lastlogin@example.com"}
+	database.DB.Create(&user)
+	assert.Nil(t, user.LastLoginAt)
+
+	reqBody, _ := json.Marshal(LoginRequest{Email: "lastlogin@example.com"})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	LoginHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.User
+	database.DB.First(&updated, user.ID)
+	assert.NotNil(t, updated.LastLoginAt)
+}
+
 func TestLoginHandler_WithUsername(t *testing.T) {
 	setupTestDB(t)
 	setupTestAuth()
@@ -954,3 +1215,528 @@ func TestRegisterHandler_InvalidBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestRequireJWTAuth_SetsRole(t *testing.T) {
+	setupTestAuth()
+
+	user := &models.User{
+		Username: "admin",
+		Email:    "admin@example.com",
+		Role:     models.RoleAdmin,
+	}
+	user.ID = 1
+
+	tokens, _ := GenerateTokens(user)
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+
+	var role string
+	handler := RequireJWTAuth(func(w http.ResponseWriter, r *http.Request) {
+		role = UserRoleFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.RoleAdmin, role)
+}
+
+// Session Tests
+
+func TestNewTokenID_Unique(t *testing.T) {
+	first, err := newTokenID()
+	assert.NoError(t, err)
+	second, err := newTokenID()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+}
+
+func TestGenerateTokens_SharedJTI(t *testing.T) {
+	setupTestAuth()
+
+	user := &models.User{Username: "testuser", Email: "test@example.com"}
+	user.ID = 1
+
+	tokens, err := GenerateTokens(user)
+	assert.NoError(t, err)
+
+	accessClaims, err := ValidateToken(tokens.AccessToken)
+	assert.NoError(t, err)
+	refreshClaims, err := ValidateToken(tokens.RefreshToken)
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, accessClaims.ID)
+	assert.Equal(t, accessClaims.ID, refreshClaims.ID)
+}
+
+// loginForSession logs a user in and returns the issued tokens
+func loginForSession(t *testing.T, email string) *TokenResponse {
+	reqBody, _ := json.Marshal(LoginRequest{Email: email})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(reqBody))
+	req.Header.Set("User-Agent", "test-agent")
+	w := httptest.NewRecorder()
+
+	LoginHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Tokens TokenResponse `json:"tokens"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+	return &response.Tokens
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "sessionuser", Email: "session@example.com"}
+	database.DB.Create(&user)
+
+	current := loginForSession(t, "session@example.com")
+	other := loginForSession(t, "session@example.com")
+
+	// List sessions
+	req := httptest.NewRequest("GET", "/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+current.AccessToken)
+	w := httptest.NewRecorder()
+	SessionsHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var sessions []models.Session
+	json.NewDecoder(w.Body).Decode(&sessions)
+	assert.Len(t, sessions, 2)
+
+	// Revoke all other sessions
+	req = httptest.NewRequest("DELETE", "/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+current.AccessToken)
+	w = httptest.NewRecorder()
+	SessionsHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The other session's token is now rejected
+	handler := RequireJWTAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req = httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+other.AccessToken)
+	w = httptest.NewRecorder()
+	handler(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// The current session still works
+	req = httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+current.AccessToken)
+	w = httptest.NewRecorder()
+	handler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSessions_RevokeByID(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "revokeuser", Email: "revoke@example.com"}
+	database.DB.Create(&user)
+
+	tokens := loginForSession(t, "revoke@example.com")
+
+	var session models.Session
+	database.DB.Where("user_id = ?", user.ID).First(&session)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/auth/sessions/%d", session.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	SessionsHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Refreshing a revoked session fails
+	reqBody, _ := json.Marshal(RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	req = httptest.NewRequest("POST", "/auth/refresh", bytes.NewReader(reqBody))
+	w = httptest.NewRecorder()
+	RefreshTokenHandler(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestChangePasswordHandler(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	hashed, _ := HashPassword("oldpassword")
+	user := models.User{Username: "changer", Email: "changer@example.com", Password: hashed}
+	database.DB.Create(&user)
+
+	reqBody, _ := json.Marshal(LoginRequest{Email: "changer@example.com", Password: "oldpassword"})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	LoginHandler(w, req)
+
+	var login struct {
+		Tokens TokenResponse `json:"tokens"`
+	}
+	json.NewDecoder(w.Body).Decode(&login)
+
+	changePassword := func(current, next string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(ChangePasswordRequest{CurrentPassword: current, NewPassword: next})
+		req := httptest.NewRequest("POST", "/auth/change-password", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+login.Tokens.AccessToken)
+		w := httptest.NewRecorder()
+		ChangePasswordHandler(w, req)
+		return w
+	}
+
+	t.Run("Wrong current password", func(t *testing.T) {
+		w := changePassword("wrongpassword", "newpassword123")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("New password too short", func(t *testing.T) {
+		w := changePassword("oldpassword", "short")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Successful change", func(t *testing.T) {
+		w := changePassword("oldpassword", "newpassword123")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var tokens TokenResponse
+		json.NewDecoder(w.Body).Decode(&tokens)
+		assert.NotEmpty(t, tokens.AccessToken)
+
+		var updated models.User
+		database.DB.First(&updated, user.ID)
+		assert.True(t, CheckPasswordHash("newpassword123", updated.Password))
+
+		// The old access token is revoked
+		_, err := ValidateToken(login.Tokens.AccessToken)
+		assert.NoError(t, err)
+		claims, _ := ValidateToken(login.Tokens.AccessToken)
+		assert.True(t, isTokenRevoked(claims.ID))
+	})
+}
+
+func TestChangePasswordHandler_OAuthOnly(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "oauthonly", Email: "oauth@example.com", GitHubID: "oauth123"}
+	database.DB.Create(&user)
+	tokens, _ := GenerateTokens(&user)
+
+	body, _ := json.Marshal(ChangePasswordRequest{CurrentPassword: "", NewPassword: "newpassword123"})
+	req := httptest.NewRequest("POST", "/auth/change-password", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+
+	ChangePasswordHandler(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// Registration Email Domain Tests
+
+func TestCheckEmailDomain(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    string
+		blocked    string
+		email      string
+		wantStatus int
+	}{
+		{"Neither set", "", "", "user@mailinator.com", 0},
+		{"Allowed domain", "mycompany.com", "", "user@mycompany.com", 0},
+		{"Allowed subdomain", "mycompany.com", "", "user@eng.MyCompany.com", 0},
+		{"Not in allowlist", "mycompany.com", "", "user@gmail.com", http.StatusForbidden},
+		{"Lookalike domain not allowed", "mycompany.com", "", "user@notmycompany.com", http.StatusForbidden},
+		{"Blocked domain", "", "mailinator.com,guerrillamail.com", "user@guerrillamail.com", http.StatusBadRequest},
+		{"Blocked subdomain", "", "mailinator.com", "user@x.mailinator.com", http.StatusBadRequest},
+		{"Not blocked", "", "mailinator.com", "user@example.com", 0},
+		{"Both set, allowed", "mycompany.com", "mailinator.com", "user@mycompany.com", 0},
+		{"Both set, blocked", "mycompany.com", "mailinator.com", "user@mailinator.com", http.StatusBadRequest},
+		{"Both set, blocklist wins", "mycompany.com", "temp.mycompany.com", "user@temp.mycompany.com", http.StatusBadRequest},
+		{"Both set, not allowed", "mycompany.com", "mailinator.com", "user@gmail.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALLOWED_EMAIL_DOMAINS", tt.allowed)
+			t.Setenv("BLOCKED_EMAIL_DOMAINS", tt.blocked)
+
+			status, _ := CheckEmailDomain(tt.email)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestRegisterHandler_EmailDomains(t *testing.T) {
+	setupTestAuth()
+	t.Setenv("ALLOWED_EMAIL_DOMAINS", "mycompany.com")
+	t.Setenv("BLOCKED_EMAIL_DOMAINS", "mailinator.com")
+
+	register := func(email string) int {
+		reqBody, _ := json.Marshal(RegisterRequest{Username: "newuser", Email: email, Password: "secret123"})
+		req := httptest.NewRequest("POST", "/auth/register", bytes.NewReader(reqBody))
+		w := httptest.NewRecorder()
+		RegisterHandler(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, register("user@gmail.com"))
+	assert.Equal(t, http.StatusBadRequest, register("user@mailinator.com"))
+
+	setupTestDB(t)
+	assert.Equal(t, http.StatusCreated, register("user@mycompany.com"))
+}
+
+func TestRegisterHandler_InvalidUsername(t *testing.T) {
+	setupTestAuth()
+
+	for _, name := range []string{"root", "ab", "jane doe"} {
+		reqBody, _ := json.Marshal(RegisterRequest{Username: name, Email: "user@example.com", Password: "secret123"})
+		req := httptest.NewRequest("POST", "/auth/register", bytes.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		RegisterHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "username %q", name)
+	}
+}
+
+func TestExportDataHandler(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "exporter", Email: "exporter@example.com"}
+	database.DB.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Starter"}
+	database.DB.Create(&tier)
+	database.DB.Create(&models.Vote{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+	database.DB.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Mine"})
+	tokens, _ := GenerateTokens(&user)
+
+	export := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/auth/me/export", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		ExportDataHandler(w, req)
+		return w
+	}
+
+	w := export()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, fmt.Sprintf("attachment; filename=export-%d-%s.zip", user.ID, time.Now().Format("2006-01-02")),
+		w.Header().Get("Content-Disposition"))
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	assert.Contains(t, files["profile.json"], "exporter@example.com")
+	assert.Contains(t, files["tiers.json"], "Starter")
+	assert.Contains(t, files["votes.json"], `"vote_type": 1`)
+	assert.Contains(t, files["comments.json"], "Mine")
+	assert.Contains(t, files, "sessions.json")
+
+	// A second export within 24 hours is refused
+	w = export()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestDeleteAccountHandler(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+
+	user := models.User{Username: "leaver", Email: "leaver@example.com"}
+	database.DB.Create(&user)
+	tokens := loginForSession(t, "leaver@example.com")
+
+	deleteAccount := func(confirm string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(DeleteAccountRequest{Confirm: confirm})
+		req := httptest.NewRequest("POST", "/auth/me/delete", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		DeleteAccountHandler(w, req)
+		return w
+	}
+
+	t.Run("Wrong confirmation", func(t *testing.T) {
+		w := deleteAccount("delete")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Scheduled", func(t *testing.T) {
+		w := deleteAccount(DeleteAccountConfirmation)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var updated models.User
+		database.DB.First(&updated, user.ID)
+		if assert.NotNil(t, updated.DeletionScheduledAt) {
+			assert.WithinDuration(t, time.Now().Add(AccountDeletionGracePeriod), *updated.DeletionScheduledAt, time.Minute)
+		}
+
+		// All sessions are logged out
+		assert.True(t, isTokenRevoked(mustClaims(t, tokens.AccessToken).ID))
+	})
+
+	t.Run("Login cancels deletion", func(t *testing.T) {
+		loginForSession(t, "leaver@example.com")
+
+		var updated models.User
+		database.DB.First(&updated, user.ID)
+		assert.Nil(t, updated.DeletionScheduledAt)
+	})
+}
+
+// mustClaims validates a token that the test expects to be well-formed
+func mustClaims(t *testing.T, token string) *Claims {
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	return claims
+}
+
+// Introspection Tests
+
+func introspectRequest(token, password string) *http.Request {
+	reqBody, _ := json.Marshal(IntrospectRequest{Token: token})
+	req := httptest.NewRequest("POST", "/auth/introspect", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	if password != "" {
+		req.SetBasicAuth("partner", password)
+	}
+	return req
+}
+
+func TestIntrospectHandler_NotConfigured(t *testing.T) {
+	setupTestAuth()
+	t.Setenv("INTROSPECTION_SECRET", "")
+
+	w := httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest("anything", "secret"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestIntrospectHandler_WrongSecret(t *testing.T) {
+	setupTestAuth()
+	t.Setenv("INTROSPECTION_SECRET", "introspection-secret")
+
+	w := httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest("anything", "guess"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest("anything", ""))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIntrospectHandler_InvalidToken(t *testing.T) {
+	setupTestAuth()
+	t.Setenv("INTROSPECTION_SECRET", "introspection-secret")
+
+	w := httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest("invalid-token", "introspection-secret"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"active": false}`, w.Body.String())
+}
+
+func TestIntrospectHandler_ActiveToken(t *testing.T) {
+	setupTestDB(t)
+	setupTestAuth()
+	t.Setenv("INTROSPECTION_SECRET", "introspection-secret")
+
+	user := models.User{Username: "alice", Email: "alice@example.com", Role: models.RoleUser}
+	database.DB.Create(&user)
+	tokens, _ := GenerateTokens(&user)
+	claims := mustClaims(t, tokens.AccessToken)
+
+	w := httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest(tokens.AccessToken, "introspection-secret"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response IntrospectResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.True(t, response.Active)
+	assert.Equal(t, user.ID, response.UserID)
+	assert.Equal(t, "alice", response.Username)
+	assert.Equal(t, "user", response.Scope)
+	assert.Equal(t, claims.ExpiresAt.Unix(), response.Exp)
+	assert.Equal(t, claims.IssuedAt.Unix(), response.Iat)
+
+	// Revoked tokens are no longer active
+	assert.NoError(t, revokeToken(claims.ID, user.ID, claims.ExpiresAt.Time))
+	w = httptest.NewRecorder()
+	IntrospectHandler(w, introspectRequest(tokens.AccessToken, "introspection-secret"))
+	assert.JSONEq(t, `{"active": false}`, w.Body.String())
+}
+
+func TestFetchGitHubProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"login": "octocat", "company": "@github", "location": "San Francisco", "blog": "https://github.blog", "public_repos": 8}`)
+	}))
+	defer srv.Close()
+
+	original := githubUserURL
+	githubUserURL = srv.URL
+	defer func() { githubUserURL = original }()
+
+	profile, err := fetchGitHubProfile("gh-token")
+	assert.NoError(t, err)
+	assert.Equal(t, &githubProfile{Company: "@github", Location: "San Francisco", Blog: "https://github.blog", PublicRepos: 8}, profile)
+
+	_, err = fetchGitHubProfile("wrong-token")
+	assert.Error(t, err)
+}
+
+func TestApplyGitHubProfile(t *testing.T) {
+	user := models.User{Company: "Old Co", PublicRepos: 1}
+	applyGitHubProfile(&user, &githubProfile{Company: strings.Repeat("é", 60), Location: "Jakarta", PublicRepos: 12})
+
+	assert.Equal(t, 100, len(user.Company))
+	assert.True(t, utf8.ValidString(user.Company))
+	assert.Equal(t, "Jakarta", user.Location)
+	assert.Equal(t, "", user.Blog)
+	assert.Equal(t, 12, user.PublicRepos)
+}
+
+func TestUserFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	_, ok := UserIDFromContext(ctx)
+	assert.False(t, ok, "anonymous context has no user")
+	assert.Equal(t, "", UserRoleFromContext(ctx))
+
+	_, ok = UserIDFromContext(WithUserID(ctx, 0))
+	assert.False(t, ok, "user ID 0 is not a user")
+
+	ctx = WithUserRole(WithUserID(ctx, 7), models.RoleModerator)
+	userID, ok := UserIDFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, uint(7), userID)
+	assert.Equal(t, models.RoleModerator, UserRoleFromContext(ctx))
+
+	// Plain string keys set elsewhere do not collide with UserIDKey
+	_, ok = UserIDFromContext(context.WithValue(context.Background(), "user_id", uint(7)))
+	assert.False(t, ok)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import "context"
+
+// contextKey keys the authenticated user's details in a request context
+type contextKey string
+
+const (
+	// UserIDKey holds the authenticated user's ID as a uint
+	UserIDKey contextKey = "user_id"
+	// UserRoleKey holds the authenticated user's role as a string
+	UserRoleKey contextKey = "user_role"
+)
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// WithUserRole returns a copy of ctx carrying the authenticated user's role
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, UserRoleKey, role)
+}
+
+// UserIDFromContext returns the authenticated user's ID set by the auth
+// middleware, or false for anonymous requests
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(UserIDKey).(uint)
+	return userID, ok && userID != 0
+}
+
+// UserRoleFromContext returns the authenticated user's role, or "" when the
+// request is anonymous or authenticated without a role (session auth)
+func UserRoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(UserRoleKey).(string)
+	return role
+}
+
+// withClaims returns a copy of ctx carrying the user and role of a token
+func withClaims(ctx context.Context, claims *Claims) context.Context {
+	return WithUserRole(WithUserID(ctx, claims.UserID), claims.Role)
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DeleteAccountConfirmation must be sent verbatim to delete an account
+const DeleteAccountConfirmation = "DELETE MY ACCOUNT"
+
+// AccountDeletionGracePeriod is how long a deletion can be cancelled by logging in again
+const AccountDeletionGracePeriod = 7 * 24 * time.Hour
+
+// DeleteAccountRequest confirms an account deletion
+type DeleteAccountRequest struct {
+	Confirm string `json:"confirm"`
+}
+
+// DeleteAccountResponse reports when the account will be erased
+type DeleteAccountResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// DeleteAccountHandler handles POST /auth/me/delete - schedule erasure of the caller's account
+// @Summary Delete my account
+// @Description Schedule permanent deletion of the caller's account and log out all sessions. After a 7 day grace period the user, their votes and comments are hard-deleted and their tiers are kept as private, authorless tiers. Logging in again during the grace period cancels the deletion
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body DeleteAccountRequest true "Confirmation, must be \"DELETE MY ACCOUNT\""
+// @Success 200 {object} DeleteAccountResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/me/delete [post]
+func DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Confirm != DeleteAccountConfirmation {
+		http.Error(w, `Confirmation must be "`+DeleteAccountConfirmation+`"`, http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	scheduledAt := time.Now().Add(AccountDeletionGracePeriod)
+	if err := database.DB.Model(&user).Update("deletion_scheduled_at", scheduledAt).Error; err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("Failed to schedule account deletion")
+		http.Error(w, "Failed to delete account", http.StatusInternalServerError)
+		return
+	}
+
+	// Log out everywhere, including the session used for this request
+	if err := RevokeAllSessions(user.ID); err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("Failed to revoke sessions")
+		http.Error(w, "Failed to delete account", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":      user.ID,
+		"scheduled_at": scheduledAt,
+	}).Info("Account deletion scheduled")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(DeleteAccountResponse{
+		Message:             "Account scheduled for deletion; log in again before then to cancel",
+		DeletionScheduledAt: scheduledAt,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
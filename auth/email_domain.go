@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// emailDomain returns the lowercase domain part of an email address
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+}
+
+// domainInList reports whether domain, or any parent domain of it, is in the
+// comma-separated list, so "mail.mycompany.com" matches "mycompany.com"
+func domainInList(domain, list string) bool {
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if domain == entry || strings.HasSuffix(domain, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEmailDomain applies BLOCKED_EMAIL_DOMAINS and ALLOWED_EMAIL_DOMAINS to a
+// registration email or a changed account email. It returns the HTTP status and
+// message to reject with, or 0 if the email is accepted. The blocklist wins when
+// a domain is in both.
+func CheckEmailDomain(email string) (int, string) {
+	domain := emailDomain(email)
+
+	if blocked := os.Getenv("BLOCKED_EMAIL_DOMAINS"); blocked != "" && domainInList(domain, blocked) {
+		return http.StatusBadRequest, "Email domain is not accepted"
+	}
+
+	if allowed := os.Getenv("ALLOWED_EMAIL_DOMAINS"); allowed != "" && !domainInList(domain, allowed) {
+		return http.StatusForbidden, "Registration is restricted to approved email domains"
+	}
+
+	return 0, ""
+}
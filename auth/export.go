@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// exportInterval is the minimum time between two data exports of one user
+const exportInterval = 24 * time.Hour
+
+// ExportDataHandler handles GET /auth/me/export - download all data stored about the caller
+// @Summary Export my data
+// @Description Download the caller's profile, tiers, votes, comments and sessions as JSON files in a ZIP archive. Limited to one export per 24 hours
+// @Tags auth
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/me/export [get]
+func ExportDataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	if user.LastExportAt != nil && now.Sub(*user.LastExportAt) < exportInterval {
+		retryAfter := user.LastExportAt.Add(exportInterval).Sub(now)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		http.Error(w, "Only one data export is allowed per 24 hours", http.StatusTooManyRequests)
+		return
+	}
+
+	archive, err := buildExport(&user)
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("Failed to build data export")
+		http.Error(w, "Failed to export data", http.StatusInternalServerError)
+		return
+	}
+
+	// Claim the export slot only once the archive is ready; the condition
+	// stops concurrent requests from both getting through
+	result := database.DB.Model(&models.User{}).
+		Where("id = ? AND (last_export_at IS NULL OR last_export_at <= ?)", user.ID, now.Add(-exportInterval)).
+		Update("last_export_at", now)
+	if result.Error != nil {
+		log.WithError(result.Error).WithField("user_id", user.ID).Error("Failed to record data export")
+		http.Error(w, "Failed to export data", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Only one data export is allowed per 24 hours", http.StatusTooManyRequests)
+		return
+	}
+
+	log.WithField("user_id", user.ID).Info("User data exported")
+
+	filename := fmt.Sprintf("export-%d-%s.zip", user.ID, now.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+	if _, err := archive.WriteTo(w); err != nil {
+		log.WithError(err).Error("Failed to write data export")
+	}
+}
+
+// buildExport collects everything stored about user into a ZIP archive with
+// one JSON file per kind of record
+func buildExport(user *models.User) (*bytes.Buffer, error) {
+	tiers := []models.Tier{}
+	if err := database.DB.Where("user_id = ?", user.ID).Order("id").Find(&tiers).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tiers: %w", err)
+	}
+
+	votes := []models.Vote{}
+	if err := database.DB.Where("user_id = ?", user.ID).Order("id").Find(&votes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load votes: %w", err)
+	}
+
+	comments := []models.Comment{}
+	if err := database.DB.Where("user_id = ?", user.ID).Order("id").Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to load comments: %w", err)
+	}
+
+	sessions := []models.Session{}
+	if err := database.DB.Where("user_id = ?", user.ID).Order("id").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", user},
+		{"tiers.json", tiers},
+		{"votes.json", votes},
+		{"comments.json", comments},
+		{"sessions.json", sessions},
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, file := range files {
+		f, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"freestealer/models"
+)
+
+// githubUserURL is the GitHub API endpoint for the authenticated user's
+// profile; tests point it at a local server
+var githubUserURL = "https://api.github.com/user"
+
+// githubClient calls the GitHub API during the OAuth callback
+var githubClient = &http.Client{Timeout: 5 * time.Second}
+
+// githubProfile is the part of GET https://api.github.com/user stored on users
+type githubProfile struct {
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	Blog        string `json:"blog"`
+	PublicRepos int    `json:"public_repos"`
+}
+
+// fetchGitHubProfile reads the profile of the user owning accessToken
+func fetchGitHubProfile(accessToken string) (*githubProfile, error) {
+	req, err := http.NewRequest(http.MethodGet, githubUserURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var profile githubProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// applyGitHubProfile copies a GitHub profile onto user, cut to the column sizes
+func applyGitHubProfile(user *models.User, profile *githubProfile) {
+	user.Company = truncate(profile.Company, 100)
+	user.Location = truncate(profile.Location, 100)
+	user.Blog = truncate(profile.Blog, 255)
+	user.PublicRepos = profile.PublicRepos
+}
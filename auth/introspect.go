@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// IntrospectRequest is the body of POST /auth/introspect
+type IntrospectRequest struct {
+	Token string `json:"token"`
+}
+
+// IntrospectResponse is an OAuth 2.0 (RFC 7662) style token introspection
+// result. Only Active is set for tokens that are not active.
+type IntrospectResponse struct {
+	Active   bool   `json:"active"`
+	UserID   uint   `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Exp      int64  `json:"exp,omitempty"`
+	Iat      int64  `json:"iat,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// validIntrospectionSecret reports whether the request carries INTROSPECTION_SECRET
+// as the password of its Basic credentials; the username is ignored
+func validIntrospectionSecret(r *http.Request, secret string) bool {
+	_, password, ok := r.BasicAuth()
+	return ok && subtle.ConstantTimeCompare([]byte(password), []byte(secret)) == 1
+}
+
+// introspect returns the introspection result for a token. Tokens are inactive
+// when invalid, expired, revoked or belonging to a deleted user.
+func introspect(token string) IntrospectResponse {
+	claims, err := ValidateToken(token)
+	if err != nil || isTokenRevoked(claims.ID) {
+		return IntrospectResponse{}
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		return IntrospectResponse{}
+	}
+
+	response := IntrospectResponse{
+		Active:   true,
+		UserID:   user.ID,
+		Username: user.Username,
+		Scope:    user.Role,
+	}
+	if claims.ExpiresAt != nil {
+		response.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		response.Iat = claims.IssuedAt.Unix()
+	}
+	return response
+}
+
+// IntrospectHandler handles POST /auth/introspect - check a token for another service
+// @Summary Introspect a token
+// @Description Report whether a JWT is active and whom it belongs to, for services that do not hold the signing secret. Requires Basic auth with INTROSPECTION_SECRET as the password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body IntrospectRequest true "Token to introspect"
+// @Success 200 {object} IntrospectResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /auth/introspect [post]
+func IntrospectHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret := os.Getenv("INTROSPECTION_SECRET")
+	if secret == "" {
+		http.Error(w, "Token introspection is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !validIntrospectionSecret(r, secret) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+		http.Error(w, "Invalid introspection credentials", http.StatusUnauthorized)
+		return
+	}
+
+	var req IntrospectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response := introspect(req.Token)
+	log.WithFields(log.Fields{
+		"active":  response.Active,
+		"user_id": response.UserID,
+	}).Debug("Token introspected")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("Failed to encode introspection response")
+	}
+}
@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"freestealer/database"
+	"freestealer/middleware"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+// newTokenID generates a random JWT ID (jti)
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// startSession issues new tokens for a user and records the login session
+func startSession(r *http.Request, user *models.User) (*TokenResponse, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := generateTokens(user, jti)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserID:     user.ID,
+		JTI:        jti,
+		DeviceInfo: truncate(r.UserAgent(), 255),
+		IPAddress:  middleware.GetClientIP(r),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(refreshExpiration),
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// recordLogin stamps the user's last successful authentication time. Logging
+// in during the deletion grace period cancels the scheduled account deletion.
+func recordLogin(user *models.User) {
+	cancelsDeletion := user.DeletionScheduledAt != nil
+
+	updates := map[string]interface{}{
+		"last_login_at":         time.Now(),
+		"deletion_scheduled_at": nil,
+	}
+	if err := database.DB.Model(user).Updates(updates).Error; err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("Failed to record last login")
+		return
+	}
+
+	if cancelsDeletion {
+		log.WithField("user_id", user.ID).Info("Account deletion cancelled by login")
+	}
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// isTokenRevoked reports whether a token's jti has been blacklisted
+func isTokenRevoked(jti string) bool {
+	if jti == "" || database.DB == nil {
+		return false
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		// Fail open so a database hiccup does not log every user out
+		log.WithError(err).Error("Failed to check token revocation")
+		return false
+	}
+	return count > 0
+}
+
+// revokeToken blacklists a jti until the given expiry time
+func revokeToken(jti string, userID uint, expiresAt time.Time) error {
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+// revokeSession blacklists a session's jti and removes the session record
+func revokeSession(session *models.Session) error {
+	if err := revokeToken(session.JTI, session.UserID, session.ExpiresAt); err != nil {
+		return err
+	}
+	return database.DB.Delete(session).Error
+}
+
+// currentClaims returns the validated JWT claims of the caller
+func currentClaims(r *http.Request) (*Claims, error) {
+	tokenString, err := ExtractTokenFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateToken(tokenString)
+}
+
+// SessionsHandler handles /auth/sessions and /auth/sessions/{id}
+func SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/auth/sessions"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		ListSessionsHandler(w, r)
+	case r.Method == http.MethodDelete && id == "":
+		RevokeOtherSessionsHandler(w, r)
+	case r.Method == http.MethodDelete:
+		RevokeSessionHandler(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ListSessionsHandler returns the caller's active sessions
+// @Summary List active sessions
+// @Description List all active login sessions of the authenticated user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Session
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	sessions := []models.Session{}
+	if err := database.DB.Where("user_id = ? AND expires_at > ?", claims.UserID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch sessions")
+		http.Error(w, "Failed to fetch sessions", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].JTI == claims.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(sessions); err != nil {
+		log.WithError(err).Error("Failed to encode sessions response")
+	}
+}
+
+// RevokeSessionHandler revokes one of the caller's sessions
+// @Summary Revoke a session
+// @Description Revoke a specific session of the authenticated user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
+func RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 4 {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var session models.Session
+	if err := database.DB.Where("id = ? AND user_id = ?", id, claims.UserID).First(&session).Error; err != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if err := revokeSession(&session); err != nil {
+		log.WithError(err).Error("Failed to revoke session")
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":    claims.UserID,
+		"session_id": session.ID,
+	}).Info("Session revoked")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Session revoked"}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// RevokeOtherSessionsHandler revokes all of the caller's sessions except the current one
+// @Summary Revoke other sessions
+// @Description Revoke every session of the authenticated user except the one making the request
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/sessions [delete]
+func RevokeOtherSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := currentClaims(r)
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var sessions []models.Session
+	if err := database.DB.Where("user_id = ? AND jti <> ?", claims.UserID, claims.ID).Find(&sessions).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch sessions")
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range sessions {
+		if err := revokeSession(&sessions[i]); err != nil {
+			log.WithError(err).Error("Failed to revoke session")
+			http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"user_id": claims.UserID,
+		"count":   len(sessions),
+	}).Info("Other sessions revoked")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Other sessions revoked",
+		"revoked": len(sessions),
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// RevokeAllSessions blacklists and removes every session of a user, so none of
+// the access or refresh tokens issued to them are accepted any more
+func RevokeAllSessions(userID uint) error {
+	var sessions []models.Session
+	if err := database.DB.Where("user_id = ?", userID).Find(&sessions).Error; err != nil {
+		return err
+	}
+	for i := range sessions {
+		if err := revokeSession(&sessions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTierTTL is how long a tier stays cached when TIER_CACHE_TTL_SECONDS is not set
+const DefaultTierTTL = 60 * time.Second
+
+// Tiers is the shared cache for single tier reads
+var Tiers = NewTierCache(DefaultTierTTL)
+
+// CachedEntry is a cached tier with its expiry time
+type CachedEntry struct {
+	Tier      *models.Tier
+	ExpiresAt time.Time
+}
+
+// TierCache is an in-memory cache of tiers keyed by ID
+type TierCache struct {
+	entries sync.Map
+	ttl     time.Duration
+}
+
+// NewTierCache creates a tier cache whose entries live for ttl
+func NewTierCache(ttl time.Duration) *TierCache {
+	return &TierCache{ttl: ttl}
+}
+
+// InitTierCache configures the shared tier cache from the environment and starts its reaper
+func InitTierCache() {
+	ttl := DefaultTierTTL
+	if v := os.Getenv("TIER_CACHE_TTL_SECONDS"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			log.WithField("value", v).Warn("Invalid TIER_CACHE_TTL_SECONDS, using default")
+		} else {
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	Tiers = NewTierCache(ttl)
+	Tiers.StartReaper(ttl)
+
+	log.WithField("ttl", ttl).Info("Tier cache initialized")
+}
+
+// Get returns the cached tier for id if it exists and has not expired
+func (c *TierCache) Get(id uint) (*models.Tier, bool) {
+	v, ok := c.entries.Load(id)
+	if !ok {
+		return nil, false
+	}
+	entry := v.(CachedEntry)
+	if time.Now().After(entry.ExpiresAt) {
+		c.entries.Delete(id)
+		return nil, false
+	}
+	return entry.Tier, true
+}
+
+// Set stores a tier in the cache for the configured TTL
+func (c *TierCache) Set(tier *models.Tier) {
+	c.entries.Store(tier.ID, CachedEntry{Tier: tier, ExpiresAt: time.Now().Add(c.ttl)})
+}
+
+// Invalidate removes a tier from the cache
+func (c *TierCache) Invalidate(id uint) {
+	c.entries.Delete(id)
+}
+
+// evictExpired removes all entries that have expired
+func (c *TierCache) evictExpired() {
+	now := time.Now()
+	c.entries.Range(func(key, value interface{}) bool {
+		if now.After(value.(CachedEntry).ExpiresAt) {
+			c.entries.Delete(key)
+		}
+		return true
+	})
+}
+
+// StartReaper evicts expired entries every interval until the returned stop function is called
+func (c *TierCache) StartReaper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				c.evictExpired()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
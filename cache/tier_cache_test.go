@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"freestealer/models"
+)
+
+func TestTierCache(t *testing.T) {
+	c := NewTierCache(time.Minute)
+	tier := &models.Tier{ID: 1, Name: "Railway Free"}
+
+	t.Run("Miss before set", func(t *testing.T) {
+		if _, ok := c.Get(1); ok {
+			t.Error("Expected cache miss")
+		}
+	})
+
+	t.Run("Hit after set", func(t *testing.T) {
+		c.Set(tier)
+		cached, ok := c.Get(1)
+		if !ok {
+			t.Fatal("Expected cache hit")
+		}
+		if cached.Name != "Railway Free" {
+			t.Errorf("Expected name 'Railway Free', got '%s'", cached.Name)
+		}
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		c.Invalidate(1)
+		if _, ok := c.Get(1); ok {
+			t.Error("Expected cache miss after invalidation")
+		}
+	})
+}
+
+func TestTierCacheExpiry(t *testing.T) {
+	c := NewTierCache(20 * time.Millisecond)
+	c.Set(&models.Tier{ID: 1})
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.Get(1); ok {
+		t.Error("Expected entry to expire after TTL")
+	}
+}
+
+func TestTierCacheReaper(t *testing.T) {
+	c := NewTierCache(10 * time.Millisecond)
+	c.Set(&models.Tier{ID: 1})
+
+	stop := c.StartReaper(10 * time.Millisecond)
+	defer stop()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if _, ok := c.entries.Load(uint(1)); ok {
+		t.Error("Expected reaper to evict expired entry")
+	}
+}
+
+func TestInitTierCache(t *testing.T) {
+	t.Setenv("TIER_CACHE_TTL_SECONDS", "5")
+	InitTierCache()
+
+	if Tiers.ttl != 5*time.Second {
+		t.Errorf("Expected TTL of 5s, got %v", Tiers.ttl)
+	}
+}
@@ -0,0 +1,161 @@
+// Command admin runs operational tasks against the application database.
+//
+// Usage:
+//
+//	go run ./cmd/admin seed-platforms
+//	go run ./cmd/admin set-role --user-id=1 --role=admin
+//	go run ./cmd/admin repair-counts
+//	go run ./cmd/admin list-users --role=admin
+//
+// Database settings are read from the same DB_* environment variables (and
+// .env file) as the server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"freestealer/database"
+	"freestealer/jobs"
+	"freestealer/models"
+	"freestealer/platforms"
+	"freestealer/seed"
+
+	"github.com/joho/godotenv"
+	log "github.com/sirupsen/logrus"
+)
+
+// command is an admin subcommand
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"seed-platforms": {"insert the well-known platforms", seedPlatforms},
+	"set-role":       {"--user-id=N --role=user|moderator|admin  change a user's role", setRole},
+	"repair-counts":  {"recompute tier vote and comment counts", repairCounts},
+	"list-users":     {"[--role=user|moderator|admin]  list users", listUsers},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Debug("No .env file found")
+	}
+
+	if err := database.InitDatabase(); err != nil {
+		log.WithError(err).Fatal("Failed to initialize database")
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		log.WithError(err).Fatalf("%s failed", os.Args[1])
+	}
+}
+
+// printUsage lists the available subcommands
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: admin <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range []string{"seed-platforms", "set-role", "repair-counts", "list-users"} {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", name, commands[name].usage)
+	}
+}
+
+// seedPlatforms inserts the known platforms that are not in the database yet
+func seedPlatforms(args []string) error {
+	if err := flag.NewFlagSet("seed-platforms", flag.ExitOnError).Parse(args); err != nil {
+		return err
+	}
+
+	inserted, err := seed.SeedPlatforms(database.DB)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Inserted %d of %d platforms\n", inserted, len(platforms.Known()))
+	return nil
+}
+
+// setRole changes the role of one user
+func setRole(args []string) error {
+	fs := flag.NewFlagSet("set-role", flag.ExitOnError)
+	userID := fs.Uint("user-id", 0, "ID of the user to update")
+	role := fs.String("role", "", "new role: user, moderator or admin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *userID == 0 {
+		return fmt.Errorf("--user-id is required")
+	}
+	if !models.ValidRole(*role) {
+		return fmt.Errorf("--role must be %q, %q or %q", models.RoleUser, models.RoleModerator, models.RoleAdmin)
+	}
+
+	result := database.DB.Model(&models.User{}).Where("id = ?", *userID).Update("role", *role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %d not found", *userID)
+	}
+
+	fmt.Printf("User %d is now %s\n", *userID, *role)
+	return nil
+}
+
+// repairCounts runs the count repair job once
+func repairCounts(args []string) error {
+	if err := flag.NewFlagSet("repair-counts", flag.ExitOnError).Parse(args); err != nil {
+		return err
+	}
+
+	if err := jobs.RepairCounts(context.Background()); err != nil {
+		return err
+	}
+
+	fmt.Println("Tier counts repaired")
+	return nil
+}
+
+// listUsers prints users, optionally filtered by role
+func listUsers(args []string) error {
+	fs := flag.NewFlagSet("list-users", flag.ExitOnError)
+	role := fs.String("role", "", "only list users with this role")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := database.DB.Order("id")
+	if *role != "" {
+		query = query.Where("role = ?", *role)
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tROLE\tCREATED")
+	for _, user := range users {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", user.ID, user.Username, user.Email, user.Role,
+			user.CreatedAt.Format("2006-01-02"))
+	}
+	return w.Flush()
+}
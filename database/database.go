@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"freestealer/models"
-
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -24,8 +22,23 @@ const (
 
 var DB *gorm.DB
 
-// InitDatabase initializes the PostgreSQL database connection and runs migrations
+// InitDatabase connects to PostgreSQL and applies pending schema migrations
 func InitDatabase() error {
+	if err := Connect(); err != nil {
+		return err
+	}
+
+	if err := Migrate(DB); err != nil {
+		return err
+	}
+
+	log.Info("Database migrations completed")
+
+	return nil
+}
+
+// Connect opens the PostgreSQL database connection without changing the schema
+func Connect() error {
 	var err error
 
 	// Get database configuration from environment variables with defaults
@@ -64,8 +77,6 @@ func InitDatabase() error {
 		sslmode = defaultSSLMode
 	}
 
-	// Build connection string
-
 	// Build connection string
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
 		host, port, user, password, dbname, sslmode, schema)
@@ -86,41 +97,9 @@ func InitDatabase() error {
 		"schema": schema,
 	}).Info("Database connection established")
 
-	// Run auto migrations
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Tier{},
-		&models.Vote{},
-		&models.Comment{},
-	)
-
-	if err != nil {
-		return err
-	}
-
-	log.Info("Database migrations completed")
-
-	// Create indexes for better performance
-	createIndexes()
-
 	return nil
 }
 
-// createIndexes creates additional composite indexes for query optimization
-func createIndexes() {
-	// Partial unique index for GitHubID (only when not empty)
-	// PostgreSQL syntax
-	DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_git_hub_id ON users(git_hub_id) WHERE git_hub_id != ''")
-
-	// Index for querying public tiers sorted by votes
-	DB.Exec("CREATE INDEX IF NOT EXISTS idx_tiers_public_votes ON tiers(is_public, upvote_count DESC) WHERE deleted_at IS NULL")
-
-	// Index for querying tiers by platform
-	DB.Exec("CREATE INDEX IF NOT EXISTS idx_tiers_platform_public ON tiers(platform, is_public) WHERE deleted_at IS NULL")
-
-	log.Info("Custom indexes created")
-}
-
 // GetDB returns the database instance
 func GetDB() *gorm.DB {
 	return DB
@@ -3,6 +3,7 @@ package database
 import (
 	"freestealer/models"
 	"os"
+	"strings"
 	"testing"
 
 	"gorm.io/driver/postgres"
@@ -55,6 +56,15 @@ func setupTestDB(t *testing.T) {
 		&models.Tier{},
 		&models.Vote{},
 		&models.Comment{},
+		&models.CommentReaction{},
+		&models.Notification{},
+		&models.Session{},
+		&models.RevokedToken{},
+		&models.Collection{},
+		&models.CollectionTier{},
+		&models.Platform{},
+		&models.VoteSnapshot{},
+		&models.Bookmark{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
@@ -107,6 +117,9 @@ func TestInitDatabase(t *testing.T) {
 		if !DB.Migrator().HasTable(&models.Comment{}) {
 			t.Error("Comments table should exist")
 		}
+		if !DB.Migrator().HasTable(&models.Notification{}) {
+			t.Error("Notifications table should exist")
+		}
 	})
 
 	t.Run("Indexes are created", func(t *testing.T) {
@@ -115,7 +128,17 @@ func TestInitDatabase(t *testing.T) {
 			t.Skip("Database not available")
 		}
 
-		createIndexes()
+		// Migrations adopt the AutoMigrate schema and add the custom indexes
+		if err := Migrate(DB); err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+		if !DB.Migrator().HasIndex(&models.Tier{}, "idx_tiers_public_votes") {
+			t.Error("Public votes index should exist")
+		}
+		// Built with CREATE INDEX CONCURRENTLY outside a transaction
+		if !DB.Migrator().HasIndex(&models.User{}, "idx_users_github_login_lower") {
+			t.Error("GitHub login index should exist")
+		}
 
 		// Check if columns have indexes
 		if !DB.Migrator().HasIndex(&models.User{}, "idx_users_username") {
@@ -362,3 +385,91 @@ func TestDatabaseConstraints(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.Version)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Errorf("Migration %03d_%s needs both up and down SQL", m.Version, m.Name)
+		}
+		// CONCURRENTLY fails inside the transaction migrations normally run in
+		if strings.Contains(m.Up, "CONCURRENTLY") && !m.UpNoTransaction {
+			t.Errorf("Migration %03d_%s uses CONCURRENTLY without %s", m.Version, m.Name, noTransactionMarker)
+		}
+		if strings.Contains(m.Down, "CONCURRENTLY") && !m.DownNoTransaction {
+			t.Errorf("Migration %03d_%s down uses CONCURRENTLY without %s", m.Version, m.Name, noTransactionMarker)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := noTransactionMarker + `
+-- Comment; with a semicolon
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_a ON a (x);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_b
+    ON b (y);
+`
+	got := splitStatements(sql)
+	want := []string{
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_a ON a (x)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_b\n    ON b (y)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d statements, got %q", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Statement %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMigrateAndRollback(t *testing.T) {
+	setupTestDB(t)
+	if DB == nil {
+		t.Skip("Database not available")
+	}
+
+	DB.Exec("DROP SCHEMA IF EXISTS public CASCADE")
+	DB.Exec("CREATE SCHEMA public")
+
+	migrations, _ := loadMigrations()
+
+	if err := Migrate(DB); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	// A second run has nothing left to apply
+	if err := Migrate(DB); err != nil {
+		t.Fatalf("Second Migrate() error = %v", err)
+	}
+
+	var applied int64
+	DB.Model(&schemaMigration{}).Count(&applied)
+	if int(applied) != len(migrations) {
+		t.Errorf("Expected %d applied migrations, got %d", len(migrations), applied)
+	}
+	for _, table := range []interface{}{&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{}, &models.Session{}} {
+		if !DB.Migrator().HasTable(table) {
+			t.Errorf("Expected table for %T to exist", table)
+		}
+	}
+
+	if err := Rollback(DB, len(migrations)); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if DB.Migrator().HasTable(&models.User{}) {
+		t.Error("Expected users table to be dropped by rollback")
+	}
+	DB.Model(&schemaMigration{}).Count(&applied)
+	if applied != 0 {
+		t.Errorf("Expected no applied migrations after rollback, got %d", applied)
+	}
+}
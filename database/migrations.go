@@ -0,0 +1,236 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds the numbered schema migrations. Each NNN_name.sql is
+// applied once, in order; the matching NNN_name_down.sql undoes it.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// noTransactionMarker as the first line of a migration file runs it outside a
+// transaction, one statement at a time. PostgreSQL refuses CREATE INDEX
+// CONCURRENTLY inside a transaction block, and that includes the implicit one
+// around a multi-statement query. Such files must not contain semicolons
+// other than those ending statements. A concurrent index build that fails
+// leaves an INVALID index behind, which IF NOT EXISTS then skips; drop it
+// before migrating again.
+const noTransactionMarker = "-- migrate:no-transaction"
+
+// migration is one numbered schema change
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+
+	UpNoTransaction   bool // Up starts with noTransactionMarker
+	DownNoTransaction bool // Down starts with noTransactionMarker
+}
+
+// schemaMigration records an applied migration in the schema_migrations table
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName keeps the conventional schema_migrations table name
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// loadMigrations reads the embedded migration files sorted by version
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		base := strings.TrimSuffix(file, ".sql")
+		down := strings.HasSuffix(base, "_down")
+		base = strings.TrimSuffix(base, "_down")
+
+		number, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s: name must look like 001_description.sql", file)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %03d: conflicting names %q and %q", version, m.Name, name)
+		}
+		noTransaction := strings.HasPrefix(string(body), noTransactionMarker)
+		if down {
+			m.Down = string(body)
+			m.DownNoTransaction = noTransaction
+		} else {
+			m.Up = string(body)
+			m.UpNoTransaction = noTransaction
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// appliedMigrations returns the applied versions, creating the tracking table if needed
+func appliedMigrations(db *gorm.DB) (map[int]bool, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = true
+	}
+	return applied, nil
+}
+
+// splitStatements splits a migration into its statements, dropping comment lines
+func splitStatements(sql string) []string {
+	var lines []string
+	for _, line := range strings.Split(sql, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
+
+// runMigration executes sql and then record in one transaction, or, for
+// noTransactionMarker files, statement by statement on the plain connection
+// with record last
+func runMigration(db *gorm.DB, sql string, noTransaction bool, record func(tx *gorm.DB) error) error {
+	if !noTransaction {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+			return record(tx)
+		})
+	}
+
+	for _, stmt := range splitStatements(sql) {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return record(db)
+}
+
+// Migrate applies all migrations that have not been applied yet, each in its
+// own transaction unless it opts out with noTransactionMarker
+func Migrate(db *gorm.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+
+		err := runMigration(db, m.Up, m.UpNoTransaction, func(tx *gorm.DB) error {
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %03d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.WithFields(log.Fields{
+			"version": m.Version,
+			"name":    m.Name,
+		}).Info("Applied migration")
+	}
+
+	return nil
+}
+
+// Rollback reverts the most recent steps applied migrations using their _down files
+func Rollback(db *gorm.DB, steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if !applied[m.Version] {
+			continue
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %03d_%s has no down file", m.Version, m.Name)
+		}
+
+		err := runMigration(db, m.Down, m.DownNoTransaction, func(tx *gorm.DB) error {
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("rollback %03d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.WithFields(log.Fields{
+			"version": m.Version,
+			"name":    m.Name,
+		}).Info("Rolled back migration")
+		steps--
+	}
+
+	return nil
+}
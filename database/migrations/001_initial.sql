@@ -0,0 +1,143 @@
+-- Initial schema. Tables and indexes use IF NOT EXISTS so databases created
+-- earlier by GORM AutoMigrate are adopted as they are.
+
+CREATE TABLE IF NOT EXISTS users (
+    id            bigserial PRIMARY KEY,
+    username      varchar(50)  NOT NULL,
+    email         varchar(100) NOT NULL,
+    password      varchar(255),
+    role          varchar(20)  DEFAULT 'user',
+    git_hub_id    varchar(50),
+    git_hub_login varchar(100),
+    avatar_url    varchar(500),
+    access_token  varchar(500),
+    refresh_token varchar(500),
+    last_login_at timestamptz,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tiers (
+    id              bigserial PRIMARY KEY,
+    user_id         bigint       NOT NULL,
+    platform        varchar(100) NOT NULL,
+    name            varchar(200) NOT NULL,
+    description     text,
+    is_public       boolean      DEFAULT true,
+    status          varchar(20)  DEFAULT 'approved',
+    cpu_limit       varchar(50),
+    memory_limit    varchar(50),
+    storage_limit   varchar(50),
+    bandwidth_limit varchar(50),
+    monthly_hours   varchar(50),
+    pricing_model   varchar(20)  DEFAULT 'free-forever',
+    url             varchar(500),
+    screenshot_url  varchar(500),
+    upvote_count    bigint       DEFAULT 0,
+    downvote_count  bigint       DEFAULT 0,
+    comment_count   bigint       DEFAULT 0,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    CONSTRAINT fk_users_tiers FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tiers_user_id ON tiers (user_id);
+CREATE INDEX IF NOT EXISTS idx_tiers_platform ON tiers (platform);
+CREATE INDEX IF NOT EXISTS idx_tiers_is_public ON tiers (is_public);
+CREATE INDEX IF NOT EXISTS idx_tiers_status ON tiers (status);
+CREATE INDEX IF NOT EXISTS idx_tiers_pricing_model ON tiers (pricing_model);
+CREATE INDEX IF NOT EXISTS idx_tiers_upvote_count ON tiers (upvote_count);
+CREATE INDEX IF NOT EXISTS idx_tiers_deleted_at ON tiers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS votes (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint   NOT NULL,
+    tier_id    bigint   NOT NULL,
+    vote_type  smallint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_users_votes FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_tiers_votes FOREIGN KEY (tier_id) REFERENCES tiers (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_tier ON votes (user_id, tier_id);
+CREATE INDEX IF NOT EXISTS idx_votes_tier_id ON votes (tier_id);
+CREATE INDEX IF NOT EXISTS idx_votes_deleted_at ON votes (deleted_at);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint       NOT NULL,
+    tier_id    bigint       NOT NULL,
+    content    varchar(100) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_users_comments FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_tiers_comments FOREIGN KEY (tier_id) REFERENCES tiers (id)
+);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
+CREATE INDEX IF NOT EXISTS idx_comments_tier_id ON comments (tier_id);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id            bigserial PRIMARY KEY,
+    user_id       bigint      NOT NULL,
+    type          varchar(50) NOT NULL,
+    resource_id   bigint      NOT NULL,
+    resource_type varchar(50) NOT NULL,
+    is_read       boolean     DEFAULT false,
+    created_at    timestamptz,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_read ON notifications (user_id, is_read);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint      NOT NULL,
+    jti          varchar(64) NOT NULL,
+    device_info  varchar(255),
+    ip_address   varchar(45),
+    created_at   timestamptz,
+    last_used_at timestamptz,
+    expires_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_jti ON sessions (jti);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id         bigserial PRIMARY KEY,
+    jti        varchar(64) NOT NULL,
+    user_id    bigint      NOT NULL,
+    expires_at timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_revoked_tokens_jti ON revoked_tokens (jti);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_user_id ON revoked_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id          bigserial PRIMARY KEY,
+    user_id     bigint       NOT NULL,
+    name        varchar(200) NOT NULL,
+    description text,
+    is_public   boolean      DEFAULT true,
+    created_at  timestamptz,
+    CONSTRAINT fk_users_collections FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_collections_user_id ON collections (user_id);
+
+CREATE TABLE IF NOT EXISTS collection_tiers (
+    collection_id bigint NOT NULL,
+    tier_id       bigint NOT NULL,
+    position      bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, tier_id),
+    CONSTRAINT fk_collections_entries FOREIGN KEY (collection_id) REFERENCES collections (id),
+    CONSTRAINT fk_collection_tiers_tier FOREIGN KEY (tier_id) REFERENCES tiers (id)
+);
+CREATE INDEX IF NOT EXISTS idx_collection_tiers_tier_id ON collection_tiers (tier_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS collection_tiers;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS votes;
+DROP TABLE IF EXISTS tiers;
+DROP TABLE IF EXISTS users;
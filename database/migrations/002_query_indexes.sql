@@ -0,0 +1,8 @@
+-- Partial unique index for GitHub IDs, which are empty for password accounts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_git_hub_id ON users (git_hub_id) WHERE git_hub_id != '';
+
+-- Public tiers sorted by votes
+CREATE INDEX IF NOT EXISTS idx_tiers_public_votes ON tiers (is_public, upvote_count DESC) WHERE deleted_at IS NULL;
+
+-- Tiers by platform
+CREATE INDEX IF NOT EXISTS idx_tiers_platform_public ON tiers (platform, is_public) WHERE deleted_at IS NULL;
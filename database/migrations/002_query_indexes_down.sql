@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tiers_platform_public;
+DROP INDEX IF EXISTS idx_tiers_public_votes;
+DROP INDEX IF EXISTS idx_users_git_hub_id;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS platforms (
+    id         bigserial PRIMARY KEY,
+    name       varchar(100) NOT NULL,
+    url        varchar(500),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_platforms_name ON platforms (name);
+CREATE INDEX IF NOT EXISTS idx_platforms_deleted_at ON platforms (deleted_at);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
+
+-- Tiers outlive deleted accounts with their author set to NULL
+ALTER TABLE tiers ALTER COLUMN user_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+-- Fails while tiers of deleted accounts exist; reassign or delete them first
+ALTER TABLE tiers ALTER COLUMN user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
@@ -0,0 +1,2 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS view_count bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tiers_view_count ON tiers (view_count);
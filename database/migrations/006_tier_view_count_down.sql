@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tiers_view_count;
+ALTER TABLE tiers DROP COLUMN IF EXISTS view_count;
@@ -0,0 +1,3 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS egress_free boolean DEFAULT false;
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS egress_limit_gb numeric DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tiers_egress_free ON tiers (egress_free);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tiers_egress_free;
+ALTER TABLE tiers DROP COLUMN IF EXISTS egress_limit_gb;
+ALTER TABLE tiers DROP COLUMN IF EXISTS egress_free;
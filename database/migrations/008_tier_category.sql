@@ -0,0 +1,3 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS category varchar(50);
+UPDATE tiers SET category = 'other' WHERE category IS NULL OR category = '';
+CREATE INDEX IF NOT EXISTS idx_tiers_category ON tiers (category);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tiers_category;
+ALTER TABLE tiers DROP COLUMN IF EXISTS category;
@@ -0,0 +1 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS geo_restrictions text;
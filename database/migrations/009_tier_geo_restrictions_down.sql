@@ -0,0 +1 @@
+ALTER TABLE tiers DROP COLUMN IF EXISTS geo_restrictions;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint      NOT NULL,
+    comment_id bigint      NOT NULL,
+    emoji      varchar(10) NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_users_comment_reactions FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_comments_reactions FOREIGN KEY (comment_id) REFERENCES comments (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_comment_emoji ON comment_reactions (user_id, comment_id, emoji);
+CREATE INDEX IF NOT EXISTS idx_comment_reactions_comment_id ON comment_reactions (comment_id);
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reactions_summary jsonb DEFAULT '{}';
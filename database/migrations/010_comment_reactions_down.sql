@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS reactions_summary;
+DROP TABLE IF EXISTS comment_reactions;
@@ -0,0 +1 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS click_count bigint DEFAULT 0;
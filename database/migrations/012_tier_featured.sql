@@ -0,0 +1,3 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS is_featured boolean DEFAULT false;
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS featured_until timestamptz;
+CREATE INDEX IF NOT EXISTS idx_tiers_is_featured ON tiers (is_featured);
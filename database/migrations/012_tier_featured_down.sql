@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tiers_is_featured;
+ALTER TABLE tiers DROP COLUMN IF EXISTS featured_until;
+ALTER TABLE tiers DROP COLUMN IF EXISTS is_featured;
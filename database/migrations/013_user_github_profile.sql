@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS company varchar(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS location varchar(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blog varchar(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS public_repos bigint;
@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS public_repos;
+ALTER TABLE users DROP COLUMN IF EXISTS blog;
+ALTER TABLE users DROP COLUMN IF EXISTS location;
+ALTER TABLE users DROP COLUMN IF EXISTS company;
@@ -0,0 +1,13 @@
+-- Keep the oldest of any existing duplicates; the rest are soft-deleted so
+-- they can still be restored by an admin after being renamed
+UPDATE tiers SET deleted_at = NOW()
+WHERE deleted_at IS NULL AND id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, platform, name ORDER BY id) AS n
+        FROM tiers
+        WHERE deleted_at IS NULL AND user_id IS NOT NULL
+    ) ranked
+    WHERE n > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_user_platform_name ON tiers (user_id, platform, name) WHERE deleted_at IS NULL;
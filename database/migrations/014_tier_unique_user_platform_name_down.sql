@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_unique_user_platform_name;
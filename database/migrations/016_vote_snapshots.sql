@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS vote_snapshots (
+    id             bigserial PRIMARY KEY,
+    tier_id        bigint      NOT NULL,
+    upvote_count   bigint      NOT NULL,
+    downvote_count bigint      NOT NULL,
+    snapshotted_at timestamptz NOT NULL,
+    CONSTRAINT fk_tiers_vote_snapshots FOREIGN KEY (tier_id) REFERENCES tiers (id)
+);
+CREATE INDEX IF NOT EXISTS idx_vote_snapshots_tier_time ON vote_snapshots (tier_id, snapshotted_at);
@@ -0,0 +1 @@
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS rejection_reason varchar(255);
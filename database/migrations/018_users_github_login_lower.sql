@@ -0,0 +1,4 @@
+-- migrate:no-transaction
+-- Case-insensitive lookups by GitHub login (GET /tiers?github_login=), built
+-- concurrently so users stays writable while the index is created
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_github_login_lower ON users (LOWER(git_hub_login));
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    tier_id    bigint NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_users_bookmarks FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_tiers_bookmarks FOREIGN KEY (tier_id) REFERENCES tiers (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_tier_bookmark ON bookmarks (user_id, tier_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_tier_id ON bookmarks (tier_id);
+
+ALTER TABLE tiers ADD COLUMN IF NOT EXISTS bookmarks_count bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tiers_bookmarks_count ON tiers (bookmarks_count);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tiers_bookmarks_count;
+ALTER TABLE tiers DROP COLUMN IF EXISTS bookmarks_count;
+DROP TABLE IF EXISTS bookmarks;
@@ -0,0 +1,81 @@
+// Package features holds the feature flags that switch optional endpoints on.
+// Flags are read from the environment at startup, so a feature can ship
+// disabled and be turned on with a restart.
+package features
+
+import (
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Feature names as reported by GET /features
+const (
+	GraphQL    = "graphql"
+	WebSockets = "websockets"
+	Export     = "export"
+	Webhooks   = "webhooks"
+)
+
+// FeatureFlags says which optional features are enabled. Every flag is off
+// unless its environment variable is set to a true value.
+type FeatureFlags struct {
+	EnableGraphQL    bool // ENABLE_GRAPHQL: POST /graphql
+	EnableWebSockets bool // ENABLE_WEBSOCKETS: /ws/tiers/{id}
+	EnableExport     bool // ENABLE_EXPORT: bulk export, no endpoint yet
+	EnableWebhooks   bool // ENABLE_WEBHOOKS: webhooks, no endpoint yet
+}
+
+// Current is the set of flags the server runs with, set by Init
+var Current FeatureFlags
+
+// Load reads the flags from the environment
+func Load() FeatureFlags {
+	return FeatureFlags{
+		EnableGraphQL:    envFlag("ENABLE_GRAPHQL"),
+		EnableWebSockets: envFlag("ENABLE_WEBSOCKETS"),
+		EnableExport:     envFlag("ENABLE_EXPORT"),
+		EnableWebhooks:   envFlag("ENABLE_WEBHOOKS"),
+	}
+}
+
+// Init loads the flags into Current
+func Init() {
+	Current = Load()
+	log.WithField("enabled", Current.Enabled()).Info("Feature flags loaded")
+}
+
+// Enabled returns the names of the enabled features
+func (f FeatureFlags) Enabled() []string {
+	enabled := []string{}
+	for _, feature := range []struct {
+		name string
+		on   bool
+	}{
+		{GraphQL, f.EnableGraphQL},
+		{WebSockets, f.EnableWebSockets},
+		{Export, f.EnableExport},
+		{Webhooks, f.EnableWebhooks},
+	} {
+		if feature.on {
+			enabled = append(enabled, feature.name)
+		}
+	}
+	return enabled
+}
+
+// envFlag reports whether the environment variable name holds a true value
+// such as "true" or "1"; unset and invalid values are false
+func envFlag(name string) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		log.WithField("value", v).Warn("Invalid " + name + ", feature disabled")
+		return false
+	}
+	return on
+}
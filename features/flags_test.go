@@ -0,0 +1,41 @@
+package features
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("Disabled by default", func(t *testing.T) {
+		for _, name := range []string{"ENABLE_GRAPHQL", "ENABLE_WEBSOCKETS", "ENABLE_EXPORT", "ENABLE_WEBHOOKS"} {
+			t.Setenv(name, "")
+		}
+		if flags := Load(); flags != (FeatureFlags{}) {
+			t.Errorf("Expected every feature disabled, got %+v", flags)
+		}
+	})
+
+	t.Run("Reads each variable", func(t *testing.T) {
+		t.Setenv("ENABLE_GRAPHQL", "true")
+		t.Setenv("ENABLE_WEBSOCKETS", "1")
+		t.Setenv("ENABLE_EXPORT", "false")
+		t.Setenv("ENABLE_WEBHOOKS", "maybe")
+
+		want := FeatureFlags{EnableGraphQL: true, EnableWebSockets: true}
+		if flags := Load(); flags != want {
+			t.Errorf("Expected %+v, got %+v", want, flags)
+		}
+	})
+}
+
+func TestEnabled(t *testing.T) {
+	if got := (FeatureFlags{}).Enabled(); len(got) != 0 || got == nil {
+		t.Errorf("Expected an empty, non-nil list, got %#v", got)
+	}
+
+	flags := FeatureFlags{EnableGraphQL: true, EnableExport: true, EnableWebhooks: true}
+	want := []string{GraphQL, Export, Webhooks}
+	if got := flags.Enabled(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,833 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"freestealer/cache"
+	"freestealer/database"
+	"freestealer/models"
+	"freestealer/normalize"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetAdminTiers handles GET /admin/tiers - list tiers by review status
+// @Summary List tiers for review
+// @Description List tiers filtered by review status (admin only). Defaults to pending submissions
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Review status: draft, pending, approved or rejected (default pending)"
+// @Success 200 {array} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers [get]
+func GetAdminTiers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.TierStatusPending
+	}
+
+	switch status {
+	case models.TierStatusDraft, models.TierStatusPending, models.TierStatusApproved, models.TierStatusRejected:
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	var tiers []models.Tier
+	if err := database.DB.Where("status = ?", status).Preload("User").Order("created_at ASC").Find(&tiers).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch tiers for review")
+		http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponses(tiers)); err != nil {
+		log.WithError(err).Error("Failed to encode tiers response")
+	}
+}
+
+// ApproveTier handles POST /admin/tiers/{id}/approve - approve a submitted tier
+// @Summary Approve a tier
+// @Description Mark a tier submission as approved so it becomes visible (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/{id}/approve [post]
+func ApproveTier(w http.ResponseWriter, r *http.Request) {
+	setTierStatus(w, r, models.TierStatusApproved)
+}
+
+// RejectTier handles POST /admin/tiers/{id}/reject - reject a submitted tier
+// @Summary Reject a tier
+// @Description Mark a tier submission as rejected (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/{id}/reject [post]
+func RejectTier(w http.ResponseWriter, r *http.Request) {
+	setTierStatus(w, r, models.TierStatusRejected)
+}
+
+// auditLog writes an audit log entry for an admin action
+func auditLog(r *http.Request, action string, fields log.Fields) {
+	adminID, _ := currentUserID(r)
+	entry := log.WithFields(fields).WithFields(log.Fields{
+		"audit":    true,
+		"action":   action,
+		"admin_id": adminID,
+	})
+	entry.Info("Admin action")
+}
+
+// adminTierID extracts the tier ID from an /admin/tiers/{id}/... path
+func adminTierID(r *http.Request) (uint64, bool) {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 4 {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// RestoreTier handles POST /admin/tiers/{id}/restore - restore a soft-deleted tier
+// @Summary Restore a deleted tier
+// @Description Undo a soft delete and mark the tier as approved, bringing back the votes and comments deleted with it (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/{id}/restore [post]
+func RestoreTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	id, ok := adminTierID(r)
+	if !ok {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.Unscoped().First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	if !tier.DeletedAt.Valid {
+		http.Error(w, "Tier is not deleted", http.StatusBadRequest)
+		return
+	}
+
+	// Votes and comments removed along with the tier carry its deleted_at;
+	// rows deleted earlier, such as votes toggled off, stay deleted
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&tier).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"status":     models.TierStatusApproved,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Vote{}).
+			Where("tier_id = ? AND deleted_at = ?", tier.ID, tier.DeletedAt.Time).
+			UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Comment{}).
+			Where("tier_id = ? AND deleted_at = ?", tier.ID, tier.DeletedAt.Time).
+			UpdateColumn("deleted_at", nil).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err, "idx_unique_user_platform_name") {
+			http.Error(w, "The owner already has a live tier with this name for this platform", http.StatusConflict)
+			return
+		}
+		log.WithError(err).Error("Failed to restore tier")
+		http.Error(w, "Failed to restore tier", http.StatusInternalServerError)
+		return
+	}
+	tier.DeletedAt = gorm.DeletedAt{}
+	tier.Status = models.TierStatusApproved
+	cache.Tiers.Invalidate(tier.ID)
+
+	auditLog(r, "tier_restore", log.Fields{"tier_id": tier.ID})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+	}
+}
+
+// PurgeTier handles DELETE /admin/tiers/{id}/purge - permanently delete a tier
+// @Summary Permanently delete a tier
+// @Description Hard-delete a tier along with its votes and comments (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/{id}/purge [delete]
+func PurgeTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	id, ok := adminTierID(r)
+	if !ok {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.Unscoped().First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tier_id = ?", tier.ID).Delete(&models.VoteSnapshot{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tier_id = ?", tier.ID).Delete(&models.Bookmark{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("tier_id = ?", tier.ID).Delete(&models.Vote{}).Error; err != nil {
+			return err
+		}
+		comments := tx.Unscoped().Model(&models.Comment{}).Select("id").Where("tier_id = ?", tier.ID)
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&models.CommentReaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("tier_id = ?", tier.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&tier).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to purge tier")
+		http.Error(w, "Failed to purge tier", http.StatusInternalServerError)
+		return
+	}
+	cache.Tiers.Invalidate(tier.ID)
+
+	auditLog(r, "tier_purge", log.Fields{
+		"tier_id":  tier.ID,
+		"platform": tier.Platform,
+		"name":     tier.Name,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Tier permanently deleted"}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// maxFeatureDays is the longest a tier can be featured in one go
+const maxFeatureDays = 365
+
+// FeatureTierRequest is the body of POST /admin/tiers/{id}/feature
+type FeatureTierRequest struct {
+	DurationDays int `json:"duration_days"`
+}
+
+// FeatureTier handles POST /admin/tiers/{id}/feature - feature a tier for a while
+// @Summary Feature a tier
+// @Description List a sponsored or partner tier first in GET /tiers for duration_days (1-365) days from now. The feature-expiry job clears the flag afterwards (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Param request body FeatureTierRequest true "Duration"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/{id}/feature [post]
+func FeatureTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	id, ok := adminTierID(r)
+	if !ok {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var req FeatureTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.DurationDays < 1 || req.DurationDays > maxFeatureDays {
+		http.Error(w, "duration_days must be between 1 and 365", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	until := time.Now().AddDate(0, 0, req.DurationDays)
+	if err := database.DB.Model(&tier).UpdateColumns(map[string]interface{}{
+		"is_featured":    true,
+		"featured_until": until,
+	}).Error; err != nil {
+		log.WithError(err).Error("Failed to feature tier")
+		http.Error(w, "Failed to feature tier", http.StatusInternalServerError)
+		return
+	}
+	tier.IsFeatured = true
+	tier.FeaturedUntil = &until
+	cache.Tiers.Invalidate(tier.ID)
+
+	auditLog(r, "tier_feature", log.Fields{
+		"tier_id":        tier.ID,
+		"platform":       tier.Platform,
+		"featured_until": until,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+	}
+}
+
+// setTierStatus transitions the tier in the /admin/tiers/{id}/... path to the given status
+func setTierStatus(w http.ResponseWriter, r *http.Request, status string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	id, ok := adminTierID(r)
+	if !ok {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	oldStatus := tier.Status
+	if err := database.DB.Model(&tier).UpdateColumn("status", status).Error; err != nil {
+		log.WithError(err).Error("Failed to update tier status")
+		http.Error(w, "Failed to update tier status", http.StatusInternalServerError)
+		return
+	}
+	tier.Status = status
+	cache.Tiers.Invalidate(tier.ID)
+
+	log.WithFields(log.Fields{
+		"tier_id":    tier.ID,
+		"user_id":    tier.UserID,
+		"old_status": oldStatus,
+		"new_status": status,
+	}).Info("Tier status changed")
+
+	if status == models.TierStatusApproved && oldStatus != status {
+		notifyTierOwner(tier.ID, 0, models.NotificationTierVerified, "tier", tier.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+	}
+}
+
+// maxBulkStatusTiers is the most tiers POST /admin/tiers/bulk-status accepts at once
+const maxBulkStatusTiers = 500
+
+// BulkStatusRequest is the body of POST /admin/tiers/bulk-status
+type BulkStatusRequest struct {
+	IDs             []uint `json:"ids"`
+	Status          string `json:"status"`                     // approved or rejected
+	RejectionReason string `json:"rejection_reason,omitempty"` // only kept for rejected tiers
+}
+
+// BulkSetTierStatus handles POST /admin/tiers/bulk-status - approve or reject many pending tiers
+// @Summary Approve or reject tiers in bulk
+// @Description Move up to 500 pending tiers to approved or rejected in one query. Tiers that are not pending are skipped. Admins and moderators only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body BulkStatusRequest true "Tier IDs and new status"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/tiers/bulk-status [post]
+func BulkSetTierStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !canModerate(r) {
+		http.Error(w, "Moderator access required", http.StatusForbidden)
+		return
+	}
+
+	var req BulkStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > maxBulkStatusTiers {
+		http.Error(w, "ids must list between 1 and 500 tiers", http.StatusBadRequest)
+		return
+	}
+	switch req.Status {
+	case models.TierStatusApproved:
+		req.RejectionReason = ""
+	case models.TierStatusRejected:
+		req.RejectionReason = strings.TrimSpace(req.RejectionReason)
+		if utf8.RuneCountInString(req.RejectionReason) > 255 {
+			http.Error(w, "rejection_reason must be at most 255 characters", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "status must be approved or rejected", http.StatusBadRequest)
+		return
+	}
+
+	// One UPDATE, returning the tiers it changed for the cache and notifications
+	var updated []models.Tier
+	if err := database.DB.Model(&updated).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN ? AND status = ?", req.IDs, models.TierStatusPending).
+		UpdateColumns(map[string]interface{}{
+			"status":           req.Status,
+			"rejection_reason": req.RejectionReason,
+		}).Error; err != nil {
+		log.WithError(err).Error("Failed to update tier statuses")
+		http.Error(w, "Failed to update tier statuses", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]uint, len(updated))
+	for i, tier := range updated {
+		ids[i] = tier.ID
+		cache.Tiers.Invalidate(tier.ID)
+		if req.Status == models.TierStatusApproved {
+			notifyTierOwner(tier.ID, 0, models.NotificationTierVerified, "tier", tier.ID)
+		}
+	}
+
+	auditLog(r, "tier_bulk_status", log.Fields{
+		"status":    req.Status,
+		"requested": len(req.IDs),
+		"updated":   len(ids),
+		"tier_ids":  ids,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  req.Status,
+		"updated": len(ids),
+		"ids":     ids,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// AdminUser is a user account as listed in the admin user search
+type AdminUser struct {
+	ID          uint       `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	TierCount   int64      `json:"tier_count"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+// GetAdminUsers handles GET /admin/users - search all user accounts
+// @Summary Search users
+// @Description List and search user accounts, optionally including soft-deleted ones (admin only). 50 users per page
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param q query string false "Match against username or email"
+// @Param include_deleted query bool false "Include soft-deleted users"
+// @Param role query string false "Filter by role: user or admin"
+// @Param page query int false "Page number for pagination"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/users [get]
+func GetAdminUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	query := database.DB.Model(&models.User{})
+	if r.URL.Query().Get("include_deleted") == "true" {
+		query = query.Unscoped()
+	}
+
+	if q := r.URL.Query().Get("q"); q != "" {
+		pattern := "%" + q + "%"
+		query = query.Where("username LIKE ? OR email LIKE ?", pattern, pattern)
+	}
+
+	if role := r.URL.Query().Get("role"); role != "" {
+		if !models.ValidRole(role) {
+			http.Error(w, "Invalid role", http.StatusBadRequest)
+			return
+		}
+		query = query.Where("role = ?", role)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		log.WithError(err).Error("Failed to count users")
+		http.Error(w, "Failed to fetch users", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize := 50
+	offset := (page - 1) * pageSize
+
+	users := []AdminUser{}
+	if err := query.
+		Select("users.id, users.username, users.email, users.role, users.last_login_at, users.created_at, users.deleted_at, " +
+			"(SELECT COUNT(*) FROM tiers WHERE tiers.user_id = users.id AND tiers.deleted_at IS NULL) AS tier_count").
+		Order("users.id ASC").
+		Limit(pageSize).Offset(offset).
+		Scan(&users).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch users")
+		http.Error(w, "Failed to fetch users", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": users,
+		"page": page,
+		"meta": map[string]interface{}{
+			"total": total,
+		},
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode users response")
+	}
+}
+
+// MergePlatformsRequest is the body of POST /admin/platforms/merge
+type MergePlatformsRequest struct {
+	SourcePlatform string `json:"source_platform"`
+	TargetPlatform string `json:"target_platform"`
+}
+
+// PlatformMergeCollision is a user's tier name that would exist twice on the
+// target platform after a merge
+type PlatformMergeCollision struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// errMergeCollision aborts a merge that would break idx_unique_user_platform_name
+var errMergeCollision = errors.New("platform merge would duplicate tier names")
+
+// MergePlatforms handles POST /admin/platforms/merge - merge duplicate platform names
+// @Summary Merge duplicate platforms
+// @Description Move every tier whose platform matches source_platform (case-insensitive) to the normalized target_platform and soft-delete the source platform records (admin only). Nothing is merged if a user would end up with two live tiers of the same name on the target; the colliding names are listed in the 409 response
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body MergePlatformsRequest true "Platforms to merge"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/platforms/merge [post]
+func MergePlatforms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	var req MergePlatformsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	source := strings.TrimSpace(req.SourcePlatform)
+	target := normalize.NormalizePlatform(req.TargetPlatform)
+	if source == "" || target == "" {
+		http.Error(w, "source_platform and target_platform are required", http.StatusBadRequest)
+		return
+	}
+
+	// Deleted tiers are merged too so a restore brings them back under the target
+	var tierIDs []uint
+	var updated int64
+	var collisions []PlatformMergeCollision
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Tier{}).
+			Where("LOWER(platform) = LOWER(?) AND platform <> ?", source, target).
+			Pluck("id", &tierIDs).Error; err != nil {
+			return err
+		}
+		if len(tierIDs) == 0 {
+			return nil
+		}
+
+		// Live tiers must stay unique per user and name once they share the
+		// target platform, whether they come from the source or already use it
+		if err := tx.Model(&models.Tier{}).
+			Select("user_id, name").
+			Where("id IN ? OR platform = ?", tierIDs, target).
+			Group("user_id, name").
+			Having("COUNT(*) > 1").
+			Order("user_id, name").
+			Scan(&collisions).Error; err != nil {
+			return err
+		}
+		if len(collisions) > 0 {
+			return errMergeCollision
+		}
+
+		result := tx.Unscoped().Model(&models.Tier{}).Where("id IN ?", tierIDs).Update("platform", target)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected
+
+		// The target's own record survives when only the case differs
+		return tx.Where("LOWER(name) = LOWER(?) AND name <> ?", source, target).Delete(&models.Platform{}).Error
+	})
+	if errors.Is(err, errMergeCollision) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      ErrCodeConflict,
+			"message":    "Merging would give these users two tiers with the same name on " + target + "; rename or delete one of each first",
+			"collisions": collisions,
+		}); err != nil {
+			log.WithError(err).Error("Failed to encode merge conflict response")
+		}
+		return
+	}
+	if isUniqueViolation(err, "idx_unique_user_platform_name") {
+		// A tier was renamed or created between the check and the update
+		writeError(w, http.StatusConflict, ErrCodeConflict, "Merging would give a user two tiers with the same name on "+target+"; retry to see which")
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to merge platforms")
+		http.Error(w, "Failed to merge platforms", http.StatusInternalServerError)
+		return
+	}
+
+	for _, id := range tierIDs {
+		cache.Tiers.Invalidate(id)
+	}
+
+	auditLog(r, "platform_merge", log.Fields{
+		"source":  source,
+		"target":  target,
+		"updated": updated,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"platform": target,
+		"updated":  updated,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// PlatformDuplicateGroup is a set of platform spellings that differ only in case
+type PlatformDuplicateGroup struct {
+	Platform     string   `json:"platform"`
+	VariantCount int      `json:"variant_count"`
+	Variants     []string `json:"variants"`
+	TierCount    int64    `json:"tier_count"`
+	TotalVotes   int64    `json:"total_votes"`
+}
+
+// GetPlatformDuplicates handles GET /admin/platforms/duplicates - list platforms spelled several ways
+// @Summary List duplicate platforms
+// @Description List groups of platform names that differ only in case, most variants first, to pick candidates for POST /admin/platforms/merge (admin only)
+// @Tags admin
+// @Produce json
+// @Param threshold query int false "Only groups with more than this many tiers in total"
+// @Success 200 {array} PlatformDuplicateGroup
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /admin/platforms/duplicates [get]
+func GetPlatformDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdmin(r) {
+		http.Error(w, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	threshold := 0
+	if raw := r.URL.Query().Get("threshold"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			http.Error(w, "threshold must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		threshold = n
+	}
+
+	var rows []struct {
+		Platform     string
+		VariantCount int
+		Variants     string
+		TierCount    int64
+		TotalVotes   int64
+	}
+	if err := database.DB.Raw(`
+		SELECT LOWER(platform) AS platform,
+			COUNT(DISTINCT platform) AS variant_count,
+			json_agg(DISTINCT platform) AS variants,
+			COUNT(*) AS tier_count,
+			COALESCE(SUM(upvote_count), 0) AS total_votes
+		FROM tiers
+		WHERE deleted_at IS NULL
+		GROUP BY LOWER(platform)
+		HAVING COUNT(DISTINCT platform) > 1 AND COUNT(*) > ?
+		ORDER BY variant_count DESC, total_votes DESC, platform`, threshold).
+		Scan(&rows).Error; err != nil {
+		log.WithError(err).Error("Failed to find duplicate platforms")
+		http.Error(w, "Failed to find duplicate platforms", http.StatusInternalServerError)
+		return
+	}
+
+	groups := make([]PlatformDuplicateGroup, 0, len(rows))
+	for _, row := range rows {
+		group := PlatformDuplicateGroup{
+			Platform:     row.Platform,
+			VariantCount: row.VariantCount,
+			TierCount:    row.TierCount,
+			TotalVotes:   row.TotalVotes,
+		}
+		if err := json.Unmarshal([]byte(row.Variants), &group.Variants); err != nil {
+			log.WithError(err).Error("Failed to decode platform variants")
+			http.Error(w, "Failed to find duplicate platforms", http.StatusInternalServerError)
+			return
+		}
+		sort.Strings(group.Variants)
+		groups = append(groups, group)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(groups); err != nil {
+		log.WithError(err).Error("Failed to encode duplicate platforms response")
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"freestealer/database"
+	"freestealer/models"
+)
+
+// The benchmarks run against the same PostgreSQL database as the tests rather
+// than an in-memory SQLite store behind an injected interface. Handlers use
+// database.DB directly, and the paths measured here depend on PostgreSQL:
+// VoteTier takes SELECT ... FOR UPDATE row locks and isUniqueViolation reads
+// pgconn error codes, neither of which SQLite reproduces. Numbers from a
+// SQLite store would also say little about production. Without a database
+// the benchmarks are skipped like the tests.
+
+func BenchmarkGetTiers(b *testing.B) {
+	db := setupTestDB(b)
+	database.DB = db
+
+	user := models.User{Username: "benchuser", Email: "bench@example.com"}
+	db.Create(&user)
+
+	tiers := make([]models.Tier, 1000)
+	for i := range tiers {
+		tiers[i] = models.Tier{
+			UserID:      user.ID,
+			Platform:    fmt.Sprintf("Platform %d", i%10),
+			Name:        fmt.Sprintf("Tier %d", i),
+			IsPublic:    true,
+			UpvoteCount: i % 50,
+		}
+	}
+	db.CreateInBatches(&tiers, 200)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/tiers", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		if w.Code != http.StatusOK {
+			b.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+}
+
+func BenchmarkVoteTier(b *testing.B) {
+	db := setupTestDB(b)
+	database.DB = db
+
+	const userCount, tierCount = 100, 10
+
+	users := make([]models.User, userCount)
+	for i := range users {
+		users[i] = models.User{
+			Username: fmt.Sprintf("voter%d", i),
+			Email:    fmt.Sprintf("voter%d@example.com", i),
+			GitHubID: fmt.Sprintf("voter%d_gh", i),
+		}
+	}
+	db.Create(&users)
+
+	// Tiers belong to someone who never votes, since self votes are refused
+	owner := models.User{Username: "owner", Email: "owner@example.com", GitHubID: "owner_gh"}
+	db.Create(&owner)
+
+	tiers := make([]models.Tier, tierCount)
+	for i := range tiers {
+		tiers[i] = models.Tier{UserID: owner.ID, Platform: "Railway", Name: fmt.Sprintf("Tier %d", i)}
+	}
+	db.Create(&tiers)
+
+	var counter uint64
+
+	b.ReportAllocs()
+	b.SetParallelism(10)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := atomic.AddUint64(&counter, 1)
+			voteReq := VoteRequest{
+				TierID:   tiers[(n/userCount)%tierCount].ID,
+				VoteType: 1,
+			}
+			body, _ := json.Marshal(voteReq)
+
+			req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+			req = asUser(req, users[n%userCount].ID)
+			w := httptest.NewRecorder()
+
+			VoteTier(w, req)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"freestealer/cache"
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookmarkResponse is the caller's bookmark state of a tier after a toggle
+type BookmarkResponse struct {
+	TierID         uint `json:"tier_id"`
+	Bookmarked     bool `json:"bookmarked"`
+	BookmarksCount int  `json:"bookmarks_count"`
+}
+
+// ToggleBookmark handles POST /tiers/{id}/bookmark - bookmark a tier or remove the bookmark
+// @Summary Toggle a tier bookmark
+// @Description Bookmark a tier for the caller, or remove the bookmark if it is already there. The tier's bookmarks_count is updated in the same transaction
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 200 {object} BookmarkResponse "Bookmark removed"
+// @Success 201 {object} BookmarkResponse "Bookmark added"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id}/bookmark [post]
+func ToggleBookmark(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 || parts[3] != "bookmark" {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	// The tier row is locked so concurrent toggles and their count updates
+	// apply one after the other, like votes
+	response := BookmarkResponse{TierID: uint(id)}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var tier models.Tier
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "user_id", "is_public").First(&tier, id).Error; err != nil {
+			return err
+		}
+		// Private tiers are hidden from everyone but their owner
+		if !tier.IsPublic && tier.UserID != userID {
+			return gorm.ErrRecordNotFound
+		}
+
+		result := tx.Where("user_id = ? AND tier_id = ?", userID, id).Delete(&models.Bookmark{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		count := gorm.Expr("bookmarks_count - 1")
+		if result.RowsAffected == 0 {
+			if err := tx.Create(&models.Bookmark{UserID: userID, TierID: uint(id)}).Error; err != nil {
+				return err
+			}
+			count = gorm.Expr("bookmarks_count + 1")
+			response.Bookmarked = true
+		}
+
+		if err := tx.Model(&models.Tier{}).Where("id = ?", id).UpdateColumn("bookmarks_count", count).Error; err != nil {
+			return err
+		}
+		if err := tx.Select("bookmarks_count").First(&tier, id).Error; err != nil {
+			return err
+		}
+		response.BookmarksCount = tier.BookmarksCount
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to toggle bookmark")
+		http.Error(w, "Failed to update bookmark", http.StatusInternalServerError)
+		return
+	}
+
+	cache.Tiers.Invalidate(uint(id))
+
+	log.WithFields(log.Fields{
+		"user_id":    userID,
+		"tier_id":    id,
+		"bookmarked": response.Bookmarked,
+	}).Info("Tier bookmark toggled")
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Bookmarked {
+		w.WriteHeader(http.StatusCreated)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("Failed to encode bookmark response")
+	}
+}
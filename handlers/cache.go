@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached value with its expiry time
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// ttlCache is a minimal in-memory cache whose entries expire after a fixed TTL
+type ttlCache struct {
+	entries sync.Map
+	ttl     time.Duration
+}
+
+// newTTLCache creates a cache whose entries live for ttl
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{ttl: ttl}
+}
+
+// Get returns the cached value for key if it exists and has not expired
+func (c *ttlCache) Get(key string) (interface{}, bool) {
+	v, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry := v.(cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.entries.Delete(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key for the cache TTL
+func (c *ttlCache) Set(key string, value interface{}) {
+	c.entries.Store(key, cacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)})
+}
+
+// Add stores value under key unless an unexpired entry is already there and
+// reports whether it stored it. Concurrent calls for one key store at most once.
+func (c *ttlCache) Add(key string, value interface{}) bool {
+	entry := cacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	for {
+		v, loaded := c.entries.LoadOrStore(key, entry)
+		if !loaded {
+			return true
+		}
+		if time.Now().Before(v.(cacheEntry).expiresAt) {
+			return false
+		}
+		if c.entries.CompareAndSwap(key, v, entry) {
+			return true
+		}
+	}
+}
+
+// Delete removes key from the cache
+func (c *ttlCache) Delete(key string) {
+	c.entries.Delete(key)
+}
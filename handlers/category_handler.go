@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CategoryListing is one category in GET /categories
+type CategoryListing struct {
+	Name      string `json:"name"`
+	TierCount int64  `json:"tier_count"`
+}
+
+// GetCategories handles GET /categories - list tier categories
+// @Summary List categories
+// @Description List every tier category with the number of public, approved tiers in it
+// @Tags tiers
+// @Produce json
+// @Success 200 {array} CategoryListing
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /categories [get]
+func GetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var counts []CategoryListing
+	if err := visibleTiers().
+		Select("category AS name, COUNT(*) AS tier_count").
+		Group("category").
+		Scan(&counts).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch category tier counts")
+		http.Error(w, "Failed to fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	byName := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byName[count.Name] = count.TierCount
+	}
+
+	// Every category is listed, including empty ones
+	listings := make([]CategoryListing, len(models.TierCategories))
+	for i, category := range models.TierCategories {
+		listings[i] = CategoryListing{Name: category, TierCount: byName[category]}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(listings); err != nil {
+		log.WithError(err).Error("Failed to encode categories response")
+	}
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"freestealer/database"
+	"freestealer/models"
+	"freestealer/sanitize"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CollectionTierRequest represents a request to add a tier to a collection
+type CollectionTierRequest struct {
+	TierID   uint `json:"tier_id"`
+	Position *int `json:"position,omitempty"` // appended to the end when omitted
+}
+
+// CollectionDetail is a collection with its tiers in response form
+type CollectionDetail struct {
+	*models.Collection
+	Tiers []TierResponse `json:"tiers"`
+}
+
+// parseCollectionID extracts the collection ID from /collections/{id}[/...]
+func parseCollectionID(path string) (uint, bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// canViewCollection reports whether the collection is public or belongs to the caller
+func canViewCollection(r *http.Request, collection *models.Collection) bool {
+	return collection.IsPublic || canModify(r, collection.UserID)
+}
+
+// CreateCollection handles POST /collections - create a named list of tiers
+// @Summary Create a collection
+// @Description Create a named, curated list of tiers owned by the authenticated user
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection body models.Collection true "Collection object"
+// @Success 201 {object} models.Collection
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /collections [post]
+func CreateCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var collection models.Collection
+	if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	for _, field := range []*string{&collection.Name, &collection.Description} {
+		cleaned, err := sanitize.Text(*field)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*field = cleaned
+	}
+
+	if strings.TrimSpace(collection.Name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	collection.ID = 0
+	collection.UserID = userID
+	collection.Tiers = nil
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&collection).Error; err != nil {
+			return err
+		}
+		// is_public defaults to true in the database, so a false value must be written explicitly
+		if !collection.IsPublic {
+			return tx.Model(&collection).UpdateColumn("is_public", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to create collection")
+		http.Error(w, "Failed to create collection", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"collection_id": collection.ID,
+		"user_id":       userID,
+	}).Info("Collection created")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(collection); err != nil {
+		log.WithError(err).Error("Failed to encode collection response")
+	}
+}
+
+// GetCollection handles GET /collections/{id} - get a collection with its tiers
+// @Summary Get a collection by ID
+// @Description Get a collection with its tiers ordered by position. Private collections are only visible to their owner, and private or unapproved tiers are only listed for their owner and admins
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} CollectionDetail
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /collections/{id} [get]
+func GetCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := parseCollectionID(r.URL.Path)
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+
+	var collection models.Collection
+	if err := database.DB.First(&collection, id).Error; err != nil || !canViewCollection(r, &collection) {
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	query := database.DB.Model(&models.Tier{}).
+		Joins("JOIN collection_tiers ON collection_tiers.tier_id = tiers.id").
+		Where("collection_tiers.collection_id = ?", collection.ID)
+
+	// Private and unreviewed tiers are listed for their owner and admins only
+	if !isAdmin(r) {
+		visible := database.DB.Where("tiers.is_public = ? AND tiers.status = ?", true, models.TierStatusApproved)
+		if userID, ok := currentUserID(r); ok {
+			visible = visible.Or("tiers.user_id = ?", userID)
+		}
+		query = query.Where(visible)
+	}
+
+	collection.Tiers = []models.Tier{}
+	if err := query.
+		Order("collection_tiers.position ASC, tiers.id ASC").
+		Find(&collection.Tiers).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch collection tiers")
+		http.Error(w, "Failed to fetch collection", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(CollectionDetail{
+		Collection: &collection,
+		Tiers:      newTierResponses(collection.Tiers),
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode collection response")
+	}
+}
+
+// AddCollectionTier handles POST /collections/{id}/tiers - add a tier to a collection
+// @Summary Add a tier to a collection
+// @Description Add a tier to one of the authenticated user's collections, at the end unless a position is given. Private and unapproved tiers can only be added by their owner
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param entry body CollectionTierRequest true "Tier to add"
+// @Success 201 {object} models.CollectionTier
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /collections/{id}/tiers [post]
+func AddCollectionTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := parseCollectionID(r.URL.Path)
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+
+	var collection models.Collection
+	if err := database.DB.First(&collection, id).Error; err != nil {
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	if !canModify(r, collection.UserID) {
+		http.Error(w, "Only the collection owner can change this collection", http.StatusForbidden)
+		return
+	}
+
+	var req CollectionTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.First(&tier, req.TierID).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	// Private and unreviewed tiers are hidden from everyone but their owner
+	if !canViewTier(r, &tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	var existing int64
+	if err := database.DB.Model(&models.CollectionTier{}).
+		Where("collection_id = ? AND tier_id = ?", collection.ID, tier.ID).
+		Count(&existing).Error; err != nil {
+		log.WithError(err).Error("Failed to check collection entry")
+		http.Error(w, "Failed to add tier to collection", http.StatusInternalServerError)
+		return
+	}
+	if existing > 0 {
+		http.Error(w, "Tier is already in this collection", http.StatusConflict)
+		return
+	}
+
+	entry := models.CollectionTier{CollectionID: collection.ID, TierID: tier.ID}
+	if req.Position != nil {
+		entry.Position = *req.Position
+	} else {
+		var last int
+		if err := database.DB.Model(&models.CollectionTier{}).
+			Where("collection_id = ?", collection.ID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&last).Error; err != nil {
+			log.WithError(err).Error("Failed to determine collection position")
+			http.Error(w, "Failed to add tier to collection", http.StatusInternalServerError)
+			return
+		}
+		entry.Position = last + 1
+	}
+
+	if err := database.DB.Create(&entry).Error; err != nil {
+		// A concurrent request added the same tier after the check above
+		if isUniqueViolation(err, "collection_tiers_pkey") {
+			http.Error(w, "Tier is already in this collection", http.StatusConflict)
+			return
+		}
+		log.WithError(err).Error("Failed to add tier to collection")
+		http.Error(w, "Failed to add tier to collection", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"collection_id": collection.ID,
+		"tier_id":       tier.ID,
+		"position":      entry.Position,
+	}).Info("Tier added to collection")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		log.WithError(err).Error("Failed to encode collection entry response")
+	}
+}
+
+// RemoveCollectionTier handles DELETE /collections/{id}/tiers/{tier_id} - remove a tier from a collection
+// @Summary Remove a tier from a collection
+// @Description Remove a tier from one of the authenticated user's collections
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param tier_id path int true "Tier ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /collections/{id}/tiers/{tier_id} [delete]
+func RemoveCollectionTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path: /collections/{id}/tiers/{tier_id}
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 5 {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+	id, ok := parseCollectionID(r.URL.Path)
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+	tierID, err := strconv.ParseUint(parts[4], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var collection models.Collection
+	if err := database.DB.First(&collection, id).Error; err != nil {
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	if !canModify(r, collection.UserID) {
+		http.Error(w, "Only the collection owner can change this collection", http.StatusForbidden)
+		return
+	}
+
+	result := database.DB.Where("collection_id = ? AND tier_id = ?", collection.ID, tierID).
+		Delete(&models.CollectionTier{})
+	if result.Error != nil {
+		log.WithError(result.Error).Error("Failed to remove tier from collection")
+		http.Error(w, "Failed to remove tier from collection", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Tier is not in this collection", http.StatusNotFound)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"collection_id": collection.ID,
+		"tier_id":       tierID,
+	}).Info("Tier removed from collection")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Tier removed from collection"}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"time"
+
+	"freestealer/models"
+)
+
+// UserSummary is the public part of a user shown with the tiers they submitted
+type UserSummary struct {
+	ID        uint   `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Bio       string `json:"bio,omitempty"`
+}
+
+// UserProfile is the public profile of a user. Email, role, GitHub ID and
+// account timestamps other than the join date are left out.
+type UserProfile struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	GitHubLogin string    `json:"github_login,omitempty"`
+	Company     string    `json:"company,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Blog        string    `json:"blog,omitempty"`
+	PublicRepos int       `json:"public_repos,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TierResponse is a tier as returned by the API. Its user is reduced to a
+// UserSummary so fields added to models.User are not exposed by accident.
+type TierResponse struct {
+	*models.Tier
+	User *UserSummary `json:"user,omitempty"` // nil when the author was not loaded or deleted
+}
+
+// TierListItem is a tier as listed by GET /tiers, with only what a list
+// shows. GET /tiers/{id} returns the full tier.
+type TierListItem struct {
+	ID             uint          `json:"id"`
+	Platform       string        `json:"platform"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"` // first tierListDescriptionLength characters
+	UpvoteCount    int           `json:"upvote_count"`
+	DownvoteCount  int           `json:"downvote_count"`
+	CommentCount   int           `json:"comment_count"`
+	BookmarksCount int           `json:"bookmarks_count"`
+	IsPublic       bool          `json:"is_public"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UserID         uint          `json:"-"`
+	User           *TierListUser `json:"user,omitempty" gorm:"-"` // nil when the author was deleted
+}
+
+// TierListUser is the author shown with a TierListItem
+type TierListUser struct {
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// newUserSummary returns the public summary of user, or nil for an unloaded user
+func newUserSummary(user *models.User) *UserSummary {
+	if user.ID == 0 {
+		return nil
+	}
+	return &UserSummary{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+		Bio:       user.Bio,
+	}
+}
+
+// newUserProfile returns the public profile of user
+func newUserProfile(user *models.User) UserProfile {
+	return UserProfile{
+		ID:          user.ID,
+		Username:    user.Username,
+		AvatarURL:   user.AvatarURL,
+		Bio:         user.Bio,
+		GitHubLogin: user.GitHubLogin,
+		Company:     user.Company,
+		Location:    user.Location,
+		Blog:        user.Blog,
+		PublicRepos: user.PublicRepos,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
+// newTierResponse wraps tier for a response
+func newTierResponse(tier *models.Tier) TierResponse {
+	return TierResponse{Tier: tier, User: newUserSummary(&tier.User)}
+}
+
+// newTierResponses wraps a list of tiers for a response
+func newTierResponses(tiers []models.Tier) []TierResponse {
+	responses := make([]TierResponse, len(tiers))
+	for i := range tiers {
+		responses[i] = newTierResponse(&tiers[i])
+	}
+	return responses
+}
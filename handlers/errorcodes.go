@@ -0,0 +1,20 @@
+package handlers
+
+// Error codes sent in the "error" field of JSON error responses, next to a
+// human-readable "message". Clients should branch on the code, never on the
+// message. Most endpoints still answer errors with a plain-text message; the
+// codes cover the responses that have a JSON body.
+const (
+	ErrCodeValidation     = "validation_error"
+	ErrCodeNotFound       = "not_found"
+	ErrCodeUnauthorized   = "unauthorized"
+	ErrCodeForbidden      = "forbidden"
+	ErrCodeConflict       = "conflict"
+	ErrCodeInternalServer = "internal_server_error" // also sent by middleware.Recovery
+	ErrCodeRateLimit      = "rate_limit_exceeded"
+
+	// ErrCodeUnsupportedMediaType is sent by middleware.RequireJSON
+	ErrCodeUnsupportedMediaType = "unsupported_media_type"
+	// ErrCodeSelfVote is a forbidden vote on one's own tier
+	ErrCodeSelfVote = "self_vote_not_allowed"
+)
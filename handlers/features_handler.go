@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"freestealer/features"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FeaturesResponse lists the optional features enabled on this server
+type FeaturesResponse struct {
+	Enabled []string `json:"enabled" example:"graphql,websockets"`
+}
+
+// GetFeatures handles GET /features - list enabled optional features
+// @Summary List enabled features
+// @Description List the optional features switched on by the server's feature flags (graphql, websockets, export, webhooks), so clients can hide what is unavailable
+// @Tags features
+// @Produce json
+// @Success 200 {object} FeaturesResponse
+// @Router /features [get]
+func GetFeatures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(FeaturesResponse{Enabled: features.Current.Enabled()}); err != nil {
+		log.WithError(err).Error("Failed to encode features response")
+	}
+}
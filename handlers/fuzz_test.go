@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"freestealer/database"
+	"freestealer/models"
+)
+
+func FuzzCreateComment(f *testing.F) {
+	db := setupTestDB(f)
+	database.DB = db
+
+	user := models.User{Username: "fuzzer", Email: "fuzzer@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Fuzz Tier"}
+	db.Create(&tier)
+
+	f.Add("")
+	f.Add(strings.Repeat("a", 100))
+	f.Add(strings.Repeat("a", 101))
+	f.Add(strings.Repeat("é", 100))
+	f.Add(strings.Repeat("é", 101))
+	f.Add(strings.Repeat("🚀", 100))
+	f.Add("日本語のコメント")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		body, _ := json.Marshal(models.Comment{
+			UserID:  user.ID,
+			TierID:  tier.ID,
+			Content: content,
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusCreated && w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 201 or 400 for content %q, got %d", content, w.Code)
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"freestealer/database"
+	"freestealer/models"
+	"freestealer/normalize"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// graphqlSchema is the read-only GraphQL view of tiers and users.
+// Only public, approved tiers are reachable through it.
+const graphqlSchema = `
+	scalar Time
+
+	type Query {
+		tier(id: ID!): Tier
+		tiers(platform: String, page: Int): TierPage!
+		user(id: ID!): User
+	}
+
+	type Tier {
+		id: ID!
+		# Null once the author's account is deleted
+		userId: ID
+		platform: String!
+		name: String!
+		description: String!
+		isPublic: Boolean!
+		status: String!
+		cpuLimit: String!
+		memoryLimit: String!
+		storageLimit: String!
+		bandwidthLimit: String!
+		monthlyHours: String!
+		pricingModel: String!
+		category: String!
+		# ISO 3166-1 alpha-2 codes; empty if available everywhere
+		geoRestrictions: [String!]!
+		egressFree: Boolean!
+		egressLimitGb: Float!
+		url: String!
+		screenshotUrl: String!
+		upvoteCount: Int!
+		downvoteCount: Int!
+		commentCount: Int!
+		# Upvotes plus downvotes
+		voteCount: Int!
+		createdAt: Time!
+		updatedAt: Time!
+		user: User
+		# The 10 most recent comments
+		comments: [Comment!]!
+	}
+
+	type TierPage {
+		data: [Tier!]!
+		page: Int!
+		total: Int!
+	}
+
+	type User {
+		id: ID!
+		username: String!
+		githubLogin: String!
+		avatarUrl: String!
+		role: String!
+		createdAt: Time!
+	}
+
+	type Comment {
+		id: ID!
+		content: String!
+		createdAt: Time!
+		user: User
+	}
+`
+
+// graphqlCommentLimit is the number of comments returned per tier
+const graphqlCommentLimit = 10
+
+// graphqlHandler serves queries against graphqlSchema
+var graphqlHandler = &relay.Handler{
+	Schema: graphql.MustParseSchema(graphqlSchema, &graphqlResolver{}),
+}
+
+// GraphQL handles POST /graphql - read-only GraphQL queries
+// @Summary GraphQL query endpoint
+// @Description Read-only GraphQL access to public tiers and users. Accepts {"query": "...", "variables": {...}}
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 405 {object} map[string]string
+// @Security BearerAuth
+// @Router /graphql [post]
+func GraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	graphqlHandler.ServeHTTP(w, r)
+}
+
+// parseGraphQLID converts a GraphQL ID argument to a database ID
+func parseGraphQLID(id graphql.ID) (uint, bool) {
+	n, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(n), true
+}
+
+// graphqlID formats a database ID as a GraphQL ID
+func graphqlID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// graphqlResolver resolves the root Query type
+type graphqlResolver struct{}
+
+// Tier resolves Query.tier
+func (*graphqlResolver) Tier(ctx context.Context, args struct{ ID graphql.ID }) (*tierResolver, error) {
+	id, ok := parseGraphQLID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+
+	var tier models.Tier
+	if err := visibleTiers().WithContext(ctx).Preload("User").First(&tier, id).Error; err != nil {
+		return nil, nil
+	}
+	return &tierResolver{tier: &tier}, nil
+}
+
+// Tiers resolves Query.tiers with the same filtering and ordering as GET /tiers
+func (*graphqlResolver) Tiers(ctx context.Context, args struct {
+	Platform *string
+	Page     *int32
+}) (*tierPageResolver, error) {
+	query := visibleTiers().WithContext(ctx)
+	if args.Platform != nil {
+		if platform := normalize.NormalizePlatform(*args.Platform); platform != "" {
+			query = query.Where("platform = ?", platform)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := int32(1)
+	if args.Page != nil && *args.Page > 1 {
+		page = *args.Page
+	}
+	pageSize := 20
+
+	var tiers []models.Tier
+	if err := query.Preload("User").
+		Order("upvote_count DESC, created_at DESC").
+		Limit(pageSize).
+		Offset(int(page-1) * pageSize).
+		Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+
+	return &tierPageResolver{tiers: tiers, page: page, total: int32(total)}, nil
+}
+
+// User resolves Query.user
+func (*graphqlResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, ok := parseGraphQLID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, nil
+	}
+	return &userResolver{user: &user}, nil
+}
+
+// tierPageResolver resolves the TierPage type
+type tierPageResolver struct {
+	tiers []models.Tier
+	page  int32
+	total int32
+}
+
+func (p *tierPageResolver) Data() []*tierResolver {
+	resolvers := make([]*tierResolver, len(p.tiers))
+	for i := range p.tiers {
+		resolvers[i] = &tierResolver{tier: &p.tiers[i]}
+	}
+	return resolvers
+}
+
+func (p *tierPageResolver) Page() int32  { return p.page }
+func (p *tierPageResolver) Total() int32 { return p.total }
+
+// tierResolver resolves the Tier type
+type tierResolver struct {
+	tier *models.Tier
+}
+
+func (t *tierResolver) ID() graphql.ID          { return graphqlID(t.tier.ID) }
+func (t *tierResolver) Platform() string        { return t.tier.Platform }
+func (t *tierResolver) Name() string            { return t.tier.Name }
+func (t *tierResolver) Description() string     { return t.tier.Description }
+func (t *tierResolver) IsPublic() bool          { return t.tier.IsPublic }
+func (t *tierResolver) Status() string          { return t.tier.Status }
+func (t *tierResolver) CPULimit() string        { return t.tier.CPULimit }
+func (t *tierResolver) MemoryLimit() string     { return t.tier.MemoryLimit }
+func (t *tierResolver) StorageLimit() string    { return t.tier.StorageLimit }
+func (t *tierResolver) BandwidthLimit() string  { return t.tier.BandwidthLimit }
+func (t *tierResolver) MonthlyHours() string    { return t.tier.MonthlyHours }
+func (t *tierResolver) PricingModel() string    { return t.tier.PricingModel }
+func (t *tierResolver) Category() string        { return t.tier.Category }
+func (t *tierResolver) EgressFree() bool        { return t.tier.EgressFree }
+func (t *tierResolver) EgressLimitGb() float64  { return t.tier.EgressLimitGB }
+func (t *tierResolver) URL() string             { return t.tier.URL }
+func (t *tierResolver) ScreenshotURL() string   { return t.tier.ScreenshotURL }
+func (t *tierResolver) UpvoteCount() int32      { return int32(t.tier.UpvoteCount) }
+func (t *tierResolver) DownvoteCount() int32    { return int32(t.tier.DownvoteCount) }
+func (t *tierResolver) CommentCount() int32     { return int32(t.tier.CommentCount) }
+func (t *tierResolver) CreatedAt() graphql.Time { return graphql.Time{Time: t.tier.CreatedAt} }
+func (t *tierResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: t.tier.UpdatedAt} }
+func (t *tierResolver) VoteCount() int32        { return int32(t.tier.UpvoteCount + t.tier.DownvoteCount) }
+
+func (t *tierResolver) GeoRestrictions() []string {
+	codes := t.tier.GeoRestrictions.Codes()
+	if codes == nil {
+		codes = []string{}
+	}
+	return codes
+}
+
+func (t *tierResolver) UserID() *graphql.ID {
+	if t.tier.UserID == 0 {
+		return nil
+	}
+	id := graphqlID(t.tier.UserID)
+	return &id
+}
+
+func (t *tierResolver) User() *userResolver {
+	if t.tier.User.ID == 0 {
+		return nil
+	}
+	return &userResolver{user: &t.tier.User}
+}
+
+func (t *tierResolver) Comments(ctx context.Context) ([]*commentResolver, error) {
+	var comments []models.Comment
+	if err := database.DB.WithContext(ctx).
+		Where("tier_id = ?", t.tier.ID).
+		Preload("User").
+		Order("created_at DESC").
+		Limit(graphqlCommentLimit).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*commentResolver, len(comments))
+	for i := range comments {
+		resolvers[i] = &commentResolver{comment: &comments[i]}
+	}
+	return resolvers, nil
+}
+
+// userResolver resolves the User type; email and credentials are not exposed
+type userResolver struct {
+	user *models.User
+}
+
+func (u *userResolver) ID() graphql.ID          { return graphqlID(u.user.ID) }
+func (u *userResolver) Username() string        { return u.user.Username }
+func (u *userResolver) GithubLogin() string     { return u.user.GitHubLogin }
+func (u *userResolver) AvatarURL() string       { return u.user.AvatarURL }
+func (u *userResolver) Role() string            { return u.user.Role }
+func (u *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: u.user.CreatedAt} }
+
+// commentResolver resolves the Comment type
+type commentResolver struct {
+	comment *models.Comment
+}
+
+func (c *commentResolver) ID() graphql.ID          { return graphqlID(c.comment.ID) }
+func (c *commentResolver) Content() string         { return c.comment.Content }
+func (c *commentResolver) CreatedAt() graphql.Time { return graphql.Time{Time: c.comment.CreatedAt} }
+
+func (c *commentResolver) User() *userResolver {
+	if c.comment.User.ID == 0 {
+		return nil
+	}
+	return &userResolver{user: &c.comment.User}
+}
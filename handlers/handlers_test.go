@@ -2,19 +2,40 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"freestealer/auth"
+	"freestealer/cache"
 	"freestealer/database"
+	"freestealer/features"
 	"freestealer/models"
+	"freestealer/views"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"golang.org/x/net/websocket"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func setupTestDB(t *testing.T) *gorm.DB {
+func setupTestDB(t testing.TB) *gorm.DB {
 	// Use PostgreSQL for testing
 	host := os.Getenv("TEST_DB_HOST")
 	if host == "" {
@@ -53,14 +74,27 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db.Exec("DROP SCHEMA IF EXISTS public CASCADE")
 	db.Exec("CREATE SCHEMA public")
 
-	err = db.AutoMigrate(&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{})
+	err = db.AutoMigrate(&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{}, &models.CommentReaction{}, &models.Notification{}, &models.Session{}, &models.RevokedToken{}, &models.Collection{}, &models.CollectionTier{}, &models.Platform{}, &models.VoteSnapshot{}, &models.Bookmark{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 
+	// IDs restart with every schema, so drop tiers cached by earlier tests
+	cache.Tiers = cache.NewTierCache(cache.DefaultTierTTL)
+
 	return db
 }
 
+// asUser returns req as the auth middleware passes it on for userID
+func asUser(req *http.Request, userID uint) *http.Request {
+	return req.WithContext(auth.WithUserID(req.Context(), userID))
+}
+
+// asRole returns req with the authenticated user's role set to role
+func asRole(req *http.Request, role string) *http.Request {
+	return req.WithContext(auth.WithUserRole(req.Context(), role))
+}
+
 func TestCreateUser(t *testing.T) {
 	db := setupTestDB(t)
 	database.DB = db
@@ -105,6 +139,21 @@ func TestCreateUser(t *testing.T) {
 		}
 	})
 
+	t.Run("Reserved username", func(t *testing.T) {
+		user := models.User{Username: "admin", Email: "admin@example.com"}
+		body, _ := json.Marshal(user)
+
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateUser(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
 	t.Run("Invalid method", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/users", nil)
 		w := httptest.NewRecorder()
@@ -161,6 +210,7 @@ func TestCreateTier(t *testing.T) {
 		body, _ := json.Marshal(tier)
 
 		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -178,11 +228,52 @@ func TestCreateTier(t *testing.T) {
 		}
 	})
 
+	t.Run("Platform is normalized", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "railway.app", Name: "Railway Hobby"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		var response models.Tier
+		json.NewDecoder(w.Body).Decode(&response)
+
+		if response.Platform != "Railway" {
+			t.Errorf("Expected platform 'Railway', got '%s'", response.Platform)
+		}
+	})
+
+	t.Run("Body user_id is replaced by the caller", func(t *testing.T) {
+		other := models.User{Username: "impersonated", Email: "impersonated@example.com", GitHubID: "impersonated_gh"}
+		db.Create(&other)
+
+		body, _ := json.Marshal(models.Tier{UserID: other.ID, Platform: "Fly.io", Name: "Fly Free"})
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", w.Code)
+		}
+		var response models.Tier
+		json.NewDecoder(w.Body).Decode(&response)
+		if response.UserID != user.ID {
+			t.Errorf("Expected the tier to belong to the caller %d, got %d", user.ID, response.UserID)
+		}
+	})
+
 	t.Run("Missing required fields", func(t *testing.T) {
 		tier := models.Tier{UserID: user.ID, Platform: "Railway"}
 		body, _ := json.Marshal(tier)
 
 		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -192,6 +283,34 @@ func TestCreateTier(t *testing.T) {
 			t.Errorf("Expected status 400, got %d", w.Code)
 		}
 	})
+
+	t.Run("Duplicate platform and name", func(t *testing.T) {
+		create := func() *httptest.ResponseRecorder {
+			body, _ := json.Marshal(models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Starter"})
+			req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+			req = asUser(req, user.ID)
+			w := httptest.NewRecorder()
+			CreateTier(w, req)
+			return w
+		}
+
+		if w := create(); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 for the first tier, got %d", w.Code)
+		}
+		w := create()
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for the duplicate, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "You already have a tier with this name for this platform") {
+			t.Errorf("Unexpected conflict message: %s", w.Body.String())
+		}
+
+		// A deleted tier no longer blocks its name
+		db.Where("user_id = ? AND name = ?", user.ID, "Koyeb Starter").Delete(&models.Tier{})
+		if w := create(); w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201 after deleting the original, got %d", w.Code)
+		}
+	})
 }
 
 func TestGetTiers(t *testing.T) {
@@ -236,6 +355,33 @@ func TestGetTiers(t *testing.T) {
 		if len(data) != 1 {
 			t.Errorf("Expected 1 public tier, got %d", len(data))
 		}
+
+		if w.Header().Get("X-Total-Count") != "1" {
+			t.Errorf("Expected X-Total-Count header '1', got '%s'", w.Header().Get("X-Total-Count"))
+		}
+
+		meta := response["meta"].(map[string]interface{})
+		if meta["total"].(float64) != 1 {
+			t.Errorf("Expected meta total 1, got %v", meta["total"])
+		}
+		if meta["per_page"].(float64) != 20 {
+			t.Errorf("Expected meta per_page 20, got %v", meta["per_page"])
+		}
+
+		if link := w.Header().Get("Link"); !strings.Contains(link, `rel="first"`) || strings.Contains(link, `rel="next"`) {
+			t.Errorf("Expected a single page Link header, got '%s'", link)
+		}
+	})
+
+	t.Run("Total count with no results", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?platform=Nowhere", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		if w.Header().Get("X-Total-Count") != "0" {
+			t.Errorf("Expected X-Total-Count header '0', got '%s'", w.Header().Get("X-Total-Count"))
+		}
 	})
 
 	t.Run("Filter by platform", func(t *testing.T) {
@@ -249,214 +395,5046 @@ func TestGetTiers(t *testing.T) {
 		}
 	})
 
-	t.Run("Get user's tiers", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/tiers?user_id=1", nil)
+	t.Run("Filter by platform alias", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?platform=railway.app", nil)
 		w := httptest.NewRecorder()
 
 		GetTiers(w, req)
 
-		if w.Code != http.StatusOK {
-			t.Errorf("Expected status 200, got %d", w.Code)
+		if w.Header().Get("X-Total-Count") != "1" {
+			t.Errorf("Expected X-Total-Count header '1', got '%s'", w.Header().Get("X-Total-Count"))
 		}
+	})
+
+	t.Run("Filter by multiple platforms", func(t *testing.T) {
+		koyebTier := models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Free", IsPublic: true}
+		db.Create(&koyebTier)
+		defer db.Unscoped().Delete(&koyebTier)
+
+		req := httptest.NewRequest(http.MethodGet, "/tiers?platform=Railway&platform=Koyeb", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
 
 		var response map[string]interface{}
 		json.NewDecoder(w.Body).Decode(&response)
 
 		data := response["data"].([]interface{})
 		if len(data) != 2 {
-			t.Errorf("Expected 2 tiers for user, got %d", len(data))
+			t.Errorf("Expected 2 tiers from both platforms, got %d", len(data))
 		}
-	})
-}
-
-func TestVoteTier(t *testing.T) {
-	db := setupTestDB(t)
-	database.DB = db
 
-	user := models.User{Username: "voter", Email: "voter@example.com"}
-	db.Create(&user)
+		req = httptest.NewRequest(http.MethodGet, "/tiers", nil)
+		w = httptest.NewRecorder()
 
-	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
-	db.Create(&tier)
+		GetTiers(w, req)
 
-	t.Run("Create upvote", func(t *testing.T) {
-		voteReq := VoteRequest{
-			UserID:   user.ID,
-			TierID:   tier.ID,
-			VoteType: 1,
+		if w.Header().Get("X-Total-Count") != "2" {
+			t.Errorf("Expected all 2 public tiers without a platform filter, got '%s'", w.Header().Get("X-Total-Count"))
 		}
-		body, _ := json.Marshal(voteReq)
+	})
 
-		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+	t.Run("Filter by pricing model", func(t *testing.T) {
+		trialTier := models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Trial", IsPublic: true, PricingModel: models.PricingFreeTrial}
+		db.Create(&trialTier)
+		defer db.Unscoped().Delete(&trialTier)
+
+		req := httptest.NewRequest(http.MethodGet, "/tiers?pricing_model=free-trial", nil)
 		w := httptest.NewRecorder()
 
-		VoteTier(w, req)
+		GetTiers(w, req)
 
-		if w.Code != http.StatusCreated {
-			t.Errorf("Expected status 201, got %d", w.Code)
+		var response struct {
+			Data []models.Tier `json:"data"`
 		}
+		json.NewDecoder(w.Body).Decode(&response)
 
-		// Check tier upvote count
-		var updatedTier models.Tier
-		db.First(&updatedTier, tier.ID)
-		if updatedTier.UpvoteCount != 1 {
-			t.Errorf("Expected upvote count 1, got %d", updatedTier.UpvoteCount)
+		if len(response.Data) != 1 || response.Data[0].ID != trialTier.ID {
+			t.Errorf("Expected only the free-trial tier, got %d tiers", len(response.Data))
 		}
-	})
-
-	t.Run("Toggle vote off", func(t *testing.T) {
-		// Vote first time
-		voteReq := VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: -1}
-		body, _ := json.Marshal(voteReq)
-		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
-		w := httptest.NewRecorder()
-		VoteTier(w, req)
 
-		// Vote again (should toggle off)
-		req = httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+		req = httptest.NewRequest(http.MethodGet, "/tiers?pricing_model=free-forever", nil)
 		w = httptest.NewRecorder()
-		VoteTier(w, req)
 
-		if w.Code != http.StatusOK {
-			t.Errorf("Expected status 200, got %d", w.Code)
+		GetTiers(w, req)
+
+		if w.Header().Get("X-Total-Count") != "1" {
+			t.Errorf("Expected 1 free-forever tier by default, got '%s'", w.Header().Get("X-Total-Count"))
 		}
 	})
 
-	t.Run("Invalid vote type", func(t *testing.T) {
-		voteReq := VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: 5}
-		body, _ := json.Marshal(voteReq)
+	t.Run("Filter by category", func(t *testing.T) {
+		dbTier := models.Tier{UserID: user.ID, Platform: "Neon", Name: "Neon Free", IsPublic: true, Category: models.CategoryDatabase}
+		db.Create(&dbTier)
+		defer db.Unscoped().Delete(&dbTier)
 
-		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/tiers?category=database", nil)
 		w := httptest.NewRecorder()
 
-		VoteTier(w, req)
+		GetTiers(w, req)
 
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected status 400, got %d", w.Code)
+		var response struct {
+			Data []models.Tier `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		if len(response.Data) != 1 || response.Data[0].ID != dbTier.ID {
+			t.Errorf("Expected only the database tier, got %d tiers", len(response.Data))
 		}
 	})
-}
 
-func TestCreateComment(t *testing.T) {
-	db := setupTestDB(t)
-	database.DB = db
+	t.Run("Filter by egress fees", func(t *testing.T) {
+		egressTier := models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Egress", IsPublic: true, EgressFree: true, UpvoteCount: 3}
+		db.Create(&egressTier)
+		defer db.Unscoped().Delete(&egressTier)
 
-	user := models.User{Username: "commenter", Email: "commenter@example.com"}
-	db.Create(&user)
+		req := httptest.NewRequest(http.MethodGet, "/tiers?egress_free=true", nil)
+		w := httptest.NewRecorder()
 
-	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
-	db.Create(&tier)
+		GetTiers(w, req)
 
-	t.Run("Valid comment", func(t *testing.T) {
-		comment := models.Comment{
-			UserID:  user.ID,
-			TierID:  tier.ID,
-			Content: "Great tier!",
+		var response struct {
+			Data []models.Tier `json:"data"`
 		}
-		body, _ := json.Marshal(comment)
+		json.NewDecoder(w.Body).Decode(&response)
 
-		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
-		w := httptest.NewRecorder()
+		if len(response.Data) != 1 || response.Data[0].ID != egressTier.ID {
+			t.Errorf("Expected only the egress-free tier, got %d tiers", len(response.Data))
+		}
 
-		CreateComment(w, req)
+		// Combined with other filters
+		req = httptest.NewRequest(http.MethodGet, "/tiers?egress_free=true&min_upvotes=5", nil)
+		w = httptest.NewRecorder()
 
-		if w.Code != http.StatusCreated {
-			t.Errorf("Expected status 201, got %d", w.Code)
-		}
+		GetTiers(w, req)
 
-		// Check tier comment count
-		var updatedTier models.Tier
-		db.First(&updatedTier, tier.ID)
-		if updatedTier.CommentCount != 1 {
-			t.Errorf("Expected comment count 1, got %d", updatedTier.CommentCount)
+		if w.Header().Get("X-Total-Count") != "0" {
+			t.Errorf("Expected no egress-free tier with 5 upvotes, got '%s'", w.Header().Get("X-Total-Count"))
 		}
 	})
 
-	t.Run("Comment too long", func(t *testing.T) {
-		longContent := "This comment is way too long and exceeds the one hundred character limit that we have set for comments on tiers!"
-		comment := models.Comment{
-			UserID:  user.ID,
-			TierID:  tier.ID,
-			Content: longContent,
-		}
-		body, _ := json.Marshal(comment)
+	t.Run("Filter by minimum upvotes", func(t *testing.T) {
+		popularTier := models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Popular", IsPublic: true, UpvoteCount: 10}
+		db.Create(&popularTier)
+		defer db.Unscoped().Delete(&popularTier)
 
-		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/tiers?min_upvotes=5", nil)
 		w := httptest.NewRecorder()
 
-		CreateComment(w, req)
+		GetTiers(w, req)
 
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected status 400, got %d", w.Code)
+		var response struct {
+			Data []models.Tier `json:"data"`
 		}
-	})
+		json.NewDecoder(w.Body).Decode(&response)
 
-	t.Run("Empty comment", func(t *testing.T) {
-		comment := models.Comment{
-			UserID:  user.ID,
-			TierID:  tier.ID,
-			Content: "",
+		if len(response.Data) != 1 || response.Data[0].ID != popularTier.ID {
+			t.Errorf("Expected only the tier with 10 upvotes, got %d tiers", len(response.Data))
 		}
-		body, _ := json.Marshal(comment)
 
-		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+		req = httptest.NewRequest(http.MethodGet, "/tiers?min_upvotes=many", nil)
+		w = httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		if w.Code != http.StatusOK || w.Header().Get("X-Total-Count") != "2" {
+			t.Errorf("Expected an invalid min_upvotes to be ignored, got status %d and total '%s'", w.Code, w.Header().Get("X-Total-Count"))
+		}
+	})
+
+	t.Run("Get user's tiers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?user_id="+strconv.Itoa(int(user.ID)), nil)
+		req = asUser(req, user.ID)
 		w := httptest.NewRecorder()
 
-		CreateComment(w, req)
+		GetTiers(w, req)
 
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected status 400, got %d", w.Code)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var response map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		data := response["data"].([]interface{})
+		if len(data) != 2 {
+			t.Errorf("Expected 2 tiers for user, got %d", len(data))
 		}
 	})
 }
 
-func TestGetComments(t *testing.T) {
+func TestGetTiersPrivacy(t *testing.T) {
 	db := setupTestDB(t)
 	database.DB = db
 
-	user := models.User{Username: "commenter", Email: "commenter@example.com"}
-	db.Create(&user)
-
-	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
-	db.Create(&tier)
+	alice := models.User{Username: "alice", Email: "alice@example.com"}
+	db.Create(&alice)
+	bob := models.User{Username: "bob", Email: "bob@example.com"}
+	db.Create(&bob)
 
-	// Create test comments
-	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Comment 1"})
-	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Comment 2"})
+	for _, owner := range []models.User{alice, bob} {
+		db.Create(&models.Tier{UserID: owner.ID, Platform: "Railway", Name: owner.Username + " public", IsPublic: true})
+		private := models.Tier{UserID: owner.ID, Platform: "Railway", Name: owner.Username + " private"}
+		db.Create(&private)
+		db.Model(&private).Update("is_public", false)
+	}
 
-	t.Run("Get comments for tier", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/comments?tier_id=1", nil)
+	list := func(query string, callerID uint) (*httptest.ResponseRecorder, []models.Tier) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?"+query, nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
 		w := httptest.NewRecorder()
+		GetTiers(w, req)
 
-		GetComments(w, req)
-
-		if w.Code != http.StatusOK {
-			t.Errorf("Expected status 200, got %d", w.Code)
+		var response struct {
+			Data []models.Tier `json:"data"`
 		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Data
+	}
 
-		var comments []models.Comment
-		json.NewDecoder(w.Body).Decode(&comments)
-
-		if len(comments) != 2 {
-			t.Errorf("Expected 2 comments, got %d", len(comments))
+	t.Run("Other user's tiers are public only", func(t *testing.T) {
+		_, tiers := list("user_id="+strconv.Itoa(int(bob.ID)), alice.ID)
+		if len(tiers) != 1 || tiers[0].Name != "bob public" {
+			t.Errorf("Expected only bob's public tier, got %+v", tiers)
 		}
 	})
 
-	t.Run("Missing tier_id parameter", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/comments", nil)
-		w := httptest.NewRecorder()
+	t.Run("Anonymous user_id is public only", func(t *testing.T) {
+		_, tiers := list("user_id="+strconv.Itoa(int(bob.ID)), 0)
+		if len(tiers) != 1 || tiers[0].Name != "bob public" {
+			t.Errorf("Expected only bob's public tier, got %+v", tiers)
+		}
+	})
 
-		GetComments(w, req)
+	t.Run("Invalid user_id is public only", func(t *testing.T) {
+		_, tiers := list("user_id=bob", alice.ID)
+		for _, tier := range tiers {
+			if !tier.IsPublic {
+				t.Errorf("Expected no private tiers, got %s", tier.Name)
+			}
+		}
+	})
 
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected status 400, got %d", w.Code)
+	t.Run("My tiers include private ones", func(t *testing.T) {
+		_, tiers := list("my=true", alice.ID)
+		if len(tiers) != 2 {
+			t.Fatalf("Expected alice's 2 tiers, got %+v", tiers)
+		}
+		for _, tier := range tiers {
+			if tier.User.Username != alice.Username {
+				t.Errorf("Expected only alice's tiers, got %s", tier.Name)
+			}
 		}
 	})
+
+	t.Run("My tiers require authentication", func(t *testing.T) {
+		if w, _ := list("my=true", 0); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTiersListItems(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "lister", Email: "lister@example.com", AvatarURL: "https://example.com/lister.png", Bio: "Not in lists"}
+	db.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Long Tier", IsPublic: true,
+		Description: strings.Repeat("é", 250), CPULimit: "0.5 vCPU", MemoryLimit: "512MB", UpvoteCount: 4}
+	db.Create(&tier)
+
+	req := httptest.NewRequest(http.MethodGet, "/tiers", nil)
+	w := httptest.NewRecorder()
+	GetTiers(w, req)
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+	if len(response.Data) != 1 {
+		t.Fatalf("Expected 1 tier, got %d", len(response.Data))
+	}
+	item := response.Data[0]
+
+	fields := []string{"id", "platform", "name", "description", "upvote_count", "downvote_count", "comment_count", "is_public", "created_at", "user"}
+	if len(item) != len(fields) {
+		t.Errorf("Expected only %v, got %v", fields, item)
+	}
+	for _, field := range fields {
+		if _, ok := item[field]; !ok {
+			t.Errorf("Expected %s in list item", field)
+		}
+	}
+
+	if description := item["description"].(string); description != strings.Repeat("é", 200) {
+		t.Errorf("Expected the description cut to 200 characters, got %d", utf8.RuneCountInString(description))
+	}
+	if item["name"] != "Long Tier" || item["upvote_count"].(float64) != 4 {
+		t.Errorf("Expected the tier's name and votes, got %v", item)
+	}
+
+	author := item["user"].(map[string]interface{})
+	if len(author) != 2 || author["username"] != "lister" || author["avatar_url"] != "https://example.com/lister.png" {
+		t.Errorf("Expected only username and avatar_url, got %v", author)
+	}
+}
+
+func TestGetTiersByGitHubLogin(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	alice := models.User{Username: "alice", Email: "alice@example.com", GitHubLogin: "alice-gh"}
+	db.Create(&alice)
+	bob := models.User{Username: "bob", Email: "bob@example.com", GitHubLogin: "bob-gh"}
+	db.Create(&bob)
+
+	for _, owner := range []models.User{alice, bob} {
+		db.Create(&models.Tier{UserID: owner.ID, Platform: "Railway", Name: owner.Username + " public", IsPublic: true})
+		private := models.Tier{UserID: owner.ID, Platform: "Railway", Name: owner.Username + " private"}
+		db.Create(&private)
+		db.Model(&private).Update("is_public", false)
+	}
+
+	list := func(query string, callerID uint) []models.Tier {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?"+query, nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		GetTiers(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Data []models.Tier `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return response.Data
+	}
+
+	t.Run("Filters by login and excludes private tiers", func(t *testing.T) {
+		tiers := list("github_login=bob-gh&sort=recent", 0)
+		if len(tiers) != 1 || tiers[0].Name != "bob public" {
+			t.Errorf("Expected only bob's public tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Owner still gets public tiers only", func(t *testing.T) {
+		tiers := list("github_login=alice-gh", alice.ID)
+		if len(tiers) != 1 || tiers[0].Name != "alice public" {
+			t.Errorf("Expected only alice's public tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Login is case-insensitive", func(t *testing.T) {
+		tiers := list("github_login=BOB-GH", 0)
+		if len(tiers) != 1 || tiers[0].Name != "bob public" {
+			t.Errorf("Expected bob's tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Unknown login returns no tiers", func(t *testing.T) {
+		if tiers := list("github_login=nobody", 0); len(tiers) != 0 {
+			t.Errorf("Expected no tiers, got %+v", tiers)
+		}
+	})
+}
+
+func TestGetFeatures(t *testing.T) {
+	defer func(flags features.FeatureFlags) { features.Current = flags }(features.Current)
+
+	get := func() []string {
+		req := httptest.NewRequest(http.MethodGet, "/features", nil)
+		w := httptest.NewRecorder()
+		GetFeatures(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var response struct {
+			Enabled []string `json:"enabled"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return response.Enabled
+	}
+
+	features.Current = features.FeatureFlags{}
+	if enabled := get(); enabled == nil || len(enabled) != 0 {
+		t.Errorf("Expected an empty list, got %v", enabled)
+	}
+
+	features.Current = features.FeatureFlags{EnableGraphQL: true, EnableWebhooks: true}
+	if enabled := get(); len(enabled) != 2 || enabled[0] != features.GraphQL || enabled[1] != features.Webhooks {
+		t.Errorf("Expected graphql and webhooks, got %v", enabled)
+	}
+}
+
+func TestParseTimeParam(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z", false},
+		{"2024-03-15T10:30:00+02:00", "2024-03-15T08:30:00Z", false},
+		{"2024-01-01", "2024-01-01T00:00:00Z", false},
+		{"2024-13-01", "", true},
+		{"01/02/2024", "", true},
+		{"yesterday", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTimeParam(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTimeParam(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("parseTimeParam(%q) = %v, want nil", tt.input, got)
+			}
+			continue
+		}
+		if got == nil || got.UTC().Format(time.RFC3339) != tt.want {
+			t.Errorf("parseTimeParam(%q) = %v, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestGetTiersCreatedRange(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "rangeuser", Email: "range@example.com"}
+	db.Create(&user)
+
+	for _, date := range []string{"2024-01-10", "2024-02-10", "2024-03-10"} {
+		created, _ := time.Parse(time.DateOnly, date)
+		db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Tier " + date, IsPublic: true, CreatedAt: created})
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, []models.Tier) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?"+query, nil)
+		w := httptest.NewRecorder()
+		GetTiers(w, req)
+
+		var response struct {
+			Data []models.Tier `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Data
+	}
+
+	t.Run("Both bounds", func(t *testing.T) {
+		_, tiers := list("created_after=2024-02-01&created_before=2024-02-28T23:59:59Z")
+		if len(tiers) != 1 || tiers[0].Name != "Tier 2024-02-10" {
+			t.Errorf("Expected only the February tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Lower bound only", func(t *testing.T) {
+		_, tiers := list("created_after=2024-02-10")
+		if len(tiers) != 2 {
+			t.Errorf("Expected 2 tiers, got %d", len(tiers))
+		}
+	})
+
+	t.Run("Upper bound only", func(t *testing.T) {
+		_, tiers := list("created_before=2024-01-31T00:00:00Z")
+		if len(tiers) != 1 || tiers[0].Name != "Tier 2024-01-10" {
+			t.Errorf("Expected only the January tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		for _, query := range []string{"created_after=last-week", "created_before=2024-1-1"} {
+			if w, _ := list(query); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", query, w.Code)
+			}
+		}
+	})
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{"Combined filter", `{"category": "database", "egress_free": true, "regions": ["us"]}`, false},
+		{"Empty object", `{}`, false},
+		{"Unknown key", `{"categroy": "database"}`, true},
+		{"Not JSON", `category=database`, true},
+		{"Trailing data", `{"category": "database"} {}`, true},
+		{"Unknown category", `{"category": "spaceships"}`, true},
+		{"Unknown pricing model", `{"pricing_model": "free-ish"}`, true},
+		{"Invalid region", `{"regions": ["USA"]}`, true},
+		{"SQL keyword", `{"platform": "x' UNION SELECT password FROM users"}`, true},
+		{"SQL comment", `{"platform": "Railway'--"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFilter(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Normalizes values", func(t *testing.T) {
+		filter, err := ParseFilter(`{"regions": [" us "], "egress_free": false, "min_upvotes": 3}`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(filter.Regions) != 1 || filter.Regions[0] != "US" {
+			t.Errorf("Expected regions [US], got %v", filter.Regions)
+		}
+		if filter.EgressFree == nil || *filter.EgressFree {
+			t.Errorf("Expected egress_free false, got %v", filter.EgressFree)
+		}
+		if filter.MinUpvotes == nil || *filter.MinUpvotes != 3 {
+			t.Errorf("Expected min_upvotes 3, got %v", filter.MinUpvotes)
+		}
+	})
+}
+
+func TestGetTiersFilter(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "filterer", Email: "filterer@example.com"}
+	db.Create(&owner)
+
+	for _, tier := range []models.Tier{
+		{Name: "US database", Category: models.CategoryDatabase, EgressFree: true, GeoRestrictions: models.NewCountryList([]string{"US"})},
+		{Name: "EU database", Category: models.CategoryDatabase, EgressFree: true, GeoRestrictions: models.NewCountryList([]string{"DE"})},
+		{Name: "Paid egress database", Category: models.CategoryDatabase},
+		{Name: "Global compute", Category: models.CategoryCompute, EgressFree: true},
+	} {
+		tier.UserID = owner.ID
+		tier.Platform = "Railway"
+		tier.IsPublic = true
+		db.Create(&tier)
+	}
+
+	list := func(filter string) (*httptest.ResponseRecorder, []models.Tier) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?filter="+url.QueryEscape(filter), nil)
+		w := httptest.NewRecorder()
+		GetTiers(w, req)
+
+		var response struct {
+			Data []models.Tier `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Data
+	}
+
+	t.Run("Combined filter", func(t *testing.T) {
+		w, tiers := list(`{"category": "database", "egress_free": true, "regions": ["US"]}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(tiers) != 1 || tiers[0].Name != "US database" {
+			t.Errorf("Expected only the US database tier, got %+v", tiers)
+		}
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		if w, _ := list(`{"colour": "blue"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTiersControversialSort(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "sortuser", Email: "sort@example.com"}
+	db.Create(&user)
+
+	tiers := []models.Tier{
+		{UserID: user.ID, Platform: "Railway", Name: "Lopsided", IsPublic: true, UpvoteCount: 90, DownvoteCount: 10},
+		{UserID: user.ID, Platform: "Koyeb", Name: "Quiet split", IsPublic: true, UpvoteCount: 2, DownvoteCount: 2},
+		{UserID: user.ID, Platform: "Vercel", Name: "Split", IsPublic: true, UpvoteCount: 50, DownvoteCount: 50},
+	}
+	for i := range tiers {
+		db.Create(&tiers[i])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tiers?sort=controversial", nil)
+	w := httptest.NewRecorder()
+
+	GetTiers(w, req)
+
+	var response struct {
+		Data []models.Tier `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+
+	var names []string
+	for _, tier := range response.Data {
+		names = append(names, tier.Name)
+	}
+	want := []string{"Split", "Quiet split", "Lopsided"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected order %v, got %v", want, names)
+	}
+}
+
+func TestGetTiersUpdatedRecentSort(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "editor", Email: "editor@example.com"}
+	db.Create(&user)
+
+	now := time.Now()
+	for i, name := range []string{"Edited last week", "Edited today", "Edited yesterday"} {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: name, IsPublic: true,
+			Status: models.TierStatusApproved, CreatedAt: now.Add(-30 * 24 * time.Hour).Add(time.Duration(i) * time.Hour)}
+		db.Create(&tier)
+	}
+	db.Model(&models.Tier{}).Where("name = ?", "Edited last week").UpdateColumn("updated_at", now.Add(-7*24*time.Hour))
+	db.Model(&models.Tier{}).Where("name = ?", "Edited yesterday").UpdateColumn("updated_at", now.Add(-24*time.Hour))
+
+	req := httptest.NewRequest(http.MethodGet, "/tiers?sort=updated_recent", nil)
+	w := httptest.NewRecorder()
+	GetTiers(w, req)
+
+	var response struct {
+		Data []models.Tier `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+
+	var names []string
+	for _, tier := range response.Data {
+		names = append(names, tier.Name)
+	}
+	want := []string{"Edited today", "Edited yesterday", "Edited last week"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected order %v, got %v", want, names)
+	}
+}
+
+func TestVoteTierConcurrent(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "racer", Email: "racer@example.com"}
+	db.Create(&user)
+	owner := models.User{Username: "raceowner", Email: "raceowner@example.com", GitHubID: "raceowner_gh"}
+	db.Create(&owner)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Raced Tier"}
+	db.Create(&tier)
+
+	// Simultaneous upvotes from one user: with the tier locked each request
+	// sees the vote left by the previous one and toggles it, instead of
+	// several trying to insert. 50 toggles leave no vote behind.
+	const voters = 50
+	body, _ := json.Marshal(VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+
+	var created, removed atomic.Int32
+	var g errgroup.Group
+	for i := 0; i < voters; i++ {
+		g.Go(func() error {
+			req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewReader(body))
+			req = asUser(req, user.ID)
+			w := httptest.NewRecorder()
+			VoteTier(w, req)
+			switch w.Code {
+			case http.StatusCreated:
+				created.Add(1)
+			case http.StatusOK:
+				removed.Add(1)
+			default:
+				return fmt.Errorf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Load() != voters/2 || removed.Load() != voters/2 {
+		t.Errorf("Expected %d creates and %d removals, got %d and %d", voters/2, voters/2, created.Load(), removed.Load())
+	}
+
+	var votes int64
+	db.Model(&models.Vote{}).Where("user_id = ? AND tier_id = ?", user.ID, tier.ID).Count(&votes)
+
+	var updated models.Tier
+	db.First(&updated, tier.ID)
+	if votes != 0 || updated.UpvoteCount != 0 {
+		t.Errorf("Expected the vote to be toggled off, got %d votes and upvote count %d", votes, updated.UpvoteCount)
+	}
+}
+
+func TestGetTiersPopularSort(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "popular", Email: "popular@example.com"}
+	db.Create(&user)
+
+	tiers := []models.Tier{
+		{UserID: user.ID, Platform: "Railway", Name: "Upvoted", IsPublic: true, UpvoteCount: 50, ViewCount: 10},
+		{UserID: user.ID, Platform: "Koyeb", Name: "Viewed", IsPublic: true, UpvoteCount: 1, ViewCount: 500},
+	}
+	for i := range tiers {
+		db.Create(&tiers[i])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tiers?sort=popular", nil)
+	w := httptest.NewRecorder()
+
+	GetTiers(w, req)
+
+	var response struct {
+		Data []models.Tier `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+
+	if len(response.Data) != 2 || response.Data[0].Name != "Viewed" {
+		t.Errorf("Expected the most viewed tier first, got %+v", response.Data)
+	}
+}
+
+func TestParseSortParam(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"upvotes,-created_at", "upvote_count DESC, created_at DESC"},
+		{"created_at", "created_at ASC"},
+		{"-upvotes, name", "upvote_count ASC, name ASC"},
+		{"views,bogus,upvotes,views", "view_count DESC, upvote_count DESC"},
+		{"password,-id", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(parseSortParam(tt.raw), ", "); got != tt.expected {
+			t.Errorf("parseSortParam(%q) = %q, expected %q", tt.raw, got, tt.expected)
+		}
+	}
+
+	// The composed terms reach the query as a single ORDER BY clause
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open dry run database: %v", err)
+	}
+
+	var tiers []models.Tier
+	stmt := db.Order(strings.Join(parseSortParam("upvotes,-created_at"), ", ")).Find(&tiers).Statement
+	if sql := stmt.SQL.String(); !strings.HasSuffix(sql, "ORDER BY upvote_count DESC, created_at DESC") {
+		t.Errorf("Expected multi-field ORDER BY, got %q", sql)
+	}
+}
+
+func TestParsePerPage(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected int
+	}{
+		{"", 20},
+		{"abc", 20},
+		{"5", 5},
+		{"100", 100},
+		{"500", 100},
+		{"0", 1},
+		{"-3", 1},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePerPage(tt.raw)
+		if err != nil || got != tt.expected {
+			t.Errorf("parsePerPage(%q) = %d, %v, expected %d", tt.raw, got, err, tt.expected)
+		}
+	}
+
+	t.Setenv("MAX_PAGE_SIZE", "50")
+	if got, _ := parsePerPage("80"); got != 50 {
+		t.Errorf("Expected per_page clamped to MAX_PAGE_SIZE 50, got %d", got)
+	}
+
+	t.Setenv("STRICT_PAGE_SIZE", "true")
+	if _, err := parsePerPage("80"); err == nil {
+		t.Error("Expected an error for per_page above the maximum in strict mode")
+	}
+	if got, err := parsePerPage("50"); err != nil || got != 50 {
+		t.Errorf("Expected per_page 50 accepted in strict mode, got %d, %v", got, err)
+	}
+}
+
+func TestBuildLinkHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		page     int
+		perPage  int
+		total    int
+		expected string
+	}{
+		{
+			name:    "first page",
+			baseURL: "/tiers", page: 1, perPage: 20, total: 45,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=2&per_page=20>; rel="next", ` +
+				`</tiers?page=3&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "middle page",
+			baseURL: "/tiers", page: 2, perPage: 20, total: 45,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=1&per_page=20>; rel="prev", ` +
+				`</tiers?page=3&per_page=20>; rel="next", ` +
+				`</tiers?page=3&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "last page",
+			baseURL: "/tiers", page: 3, perPage: 20, total: 45,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=2&per_page=20>; rel="prev", ` +
+				`</tiers?page=3&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "exact multiple of page size",
+			baseURL: "/tiers", page: 1, perPage: 10, total: 20,
+			expected: `</tiers?page=1&per_page=10>; rel="first", ` +
+				`</tiers?page=2&per_page=10>; rel="next", ` +
+				`</tiers?page=2&per_page=10>; rel="last"`,
+		},
+		{
+			name:    "single page",
+			baseURL: "/tiers", page: 1, perPage: 20, total: 5,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=1&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "no results",
+			baseURL: "/tiers", page: 1, perPage: 20, total: 0,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=1&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "page past the end",
+			baseURL: "/tiers", page: 9, perPage: 20, total: 45,
+			expected: `</tiers?page=1&per_page=20>; rel="first", ` +
+				`</tiers?page=3&per_page=20>; rel="prev", ` +
+				`</tiers?page=3&per_page=20>; rel="last"`,
+		},
+		{
+			name:    "existing query string",
+			baseURL: "/tiers?platform=Railway", page: 1, perPage: 5, total: 6,
+			expected: `</tiers?platform=Railway&page=1&per_page=5>; rel="first", ` +
+				`</tiers?platform=Railway&page=2&per_page=5>; rel="next", ` +
+				`</tiers?platform=Railway&page=2&per_page=5>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildLinkHeader(tt.baseURL, tt.page, tt.perPage, tt.total); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTotalPages(t *testing.T) {
+	tests := []struct{ total, perPage, want int }{
+		{0, 20, 0},
+		{1, 20, 1},
+		{20, 20, 1},
+		{21, 20, 2},
+		{45, 20, 3},
+	}
+	for _, tt := range tests {
+		if got := totalPages(tt.total, tt.perPage); got != tt.want {
+			t.Errorf("totalPages(%d, %d) = %d, want %d", tt.total, tt.perPage, got, tt.want)
+		}
+	}
+}
+
+func TestGetTiersPageMeta(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "pager", Email: "pager@example.com"}
+	db.Create(&user)
+
+	meta := func(query string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?"+query, nil)
+		w := httptest.NewRecorder()
+		GetTiers(w, req)
+
+		var response struct {
+			Meta map[string]interface{} `json:"meta"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return response.Meta
+	}
+
+	t.Run("No tiers", func(t *testing.T) {
+		m := meta("per_page=20")
+		if m["total_pages"] != float64(0) || m["has_next"] != false || m["has_prev"] != false {
+			t.Errorf("Expected 0 pages without next or prev, got %v", m)
+		}
+	})
+
+	for i := 0; i < 45; i++ {
+		db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Tier " + strconv.Itoa(i), IsPublic: true})
+	}
+
+	tests := []struct {
+		page             string
+		hasNext, hasPrev bool
+	}{
+		{"1", true, false},
+		{"2", true, true},
+		{"3", false, true},
+	}
+	for _, tt := range tests {
+		t.Run("Page "+tt.page, func(t *testing.T) {
+			m := meta("per_page=20&page=" + tt.page)
+			if m["total_pages"] != float64(3) {
+				t.Errorf("Expected total_pages 3, got %v", m["total_pages"])
+			}
+			if m["has_next"] != tt.hasNext || m["has_prev"] != tt.hasPrev {
+				t.Errorf("Expected has_next %v and has_prev %v, got %v", tt.hasNext, tt.hasPrev, m)
+			}
+		})
+	}
+}
+
+func TestGetTiersIncludeStats(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "sidebar", Email: "sidebar@example.com"}
+	db.Create(&user)
+	for i, upvotes := range []int{1, 3, 5} {
+		db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway " + strconv.Itoa(i), UpvoteCount: upvotes, IsPublic: true})
+	}
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb", UpvoteCount: 2, IsPublic: true})
+	private := models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Hidden", IsPublic: true}
+	db.Create(&private)
+	db.Model(&private).Update("is_public", false)
+
+	get := func(query string) map[string]json.RawMessage {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?"+query, nil)
+		w := httptest.NewRecorder()
+		GetTiers(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response map[string]json.RawMessage
+		json.NewDecoder(w.Body).Decode(&response)
+		return response
+	}
+
+	if _, ok := get("")["platform_breakdown"]; ok {
+		t.Error("Expected no platform_breakdown without include_stats")
+	}
+
+	// The breakdown ignores the list filters
+	response := get("include_stats=true&platform=Koyeb")
+	var breakdown []PlatformBreakdown
+	if err := json.Unmarshal(response["platform_breakdown"], &breakdown); err != nil {
+		t.Fatalf("Failed to decode platform_breakdown: %v", err)
+	}
+	want := []PlatformBreakdown{
+		{Platform: "Railway", Count: 3, AvgUpvotes: 3},
+		{Platform: "Koyeb", Count: 1, AvgUpvotes: 2},
+	}
+	if !reflect.DeepEqual(breakdown, want) {
+		t.Errorf("Expected %+v, got %+v", want, breakdown)
+	}
+	if _, ok := response["data"]; !ok {
+		t.Error("Expected the tier list alongside the breakdown")
+	}
+}
+
+func TestToggleBookmark(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "bookowner", Email: "bookowner@example.com"}
+	db.Create(&owner)
+	reader := models.User{Username: "bookreader", Email: "bookreader@example.com"}
+	db.Create(&reader)
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Railway Hobby", IsPublic: true}
+	db.Create(&tier)
+	private := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Render Private", IsPublic: true}
+	db.Create(&private)
+	db.Model(&private).Update("is_public", false)
+
+	toggle := func(tierID, callerID uint) (*httptest.ResponseRecorder, BookmarkResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/"+strconv.Itoa(int(tierID))+"/bookmark", nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		ToggleBookmark(w, req)
+		var response BookmarkResponse
+		json.NewDecoder(bytes.NewReader(w.Body.Bytes())).Decode(&response)
+		return w, response
+	}
+
+	t.Run("Add and remove", func(t *testing.T) {
+		w, response := toggle(tier.ID, reader.ID)
+		if w.Code != http.StatusCreated || !response.Bookmarked || response.BookmarksCount != 1 {
+			t.Fatalf("Expected 201 with the bookmark added, got %d: %s", w.Code, w.Body.String())
+		}
+		if w, _ := toggle(tier.ID, owner.ID); w.Code != http.StatusCreated {
+			t.Fatalf("Expected owners to bookmark their own tier, got %d", w.Code)
+		}
+
+		w, response = toggle(tier.ID, reader.ID)
+		if w.Code != http.StatusOK || response.Bookmarked || response.BookmarksCount != 1 {
+			t.Fatalf("Expected 200 with the bookmark removed, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var stored models.Tier
+		db.First(&stored, tier.ID)
+		var rows int64
+		db.Model(&models.Bookmark{}).Where("tier_id = ?", tier.ID).Count(&rows)
+		if stored.BookmarksCount != 1 || rows != 1 {
+			t.Errorf("Expected bookmarks_count 1 matching 1 row, got %d and %d", stored.BookmarksCount, rows)
+		}
+	})
+
+	t.Run("Private tier of another user", func(t *testing.T) {
+		if w, _ := toggle(private.ID, reader.ID); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+		if w, _ := toggle(private.ID, owner.ID); w.Code != http.StatusCreated {
+			t.Errorf("Expected the owner to bookmark their private tier, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown tier", func(t *testing.T) {
+		if w, _ := toggle(99999, reader.ID); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Requires authentication", func(t *testing.T) {
+		if w, _ := toggle(tier.ID, 0); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTiersSortBookmarked(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "saver", Email: "saver@example.com"}
+	db.Create(&user)
+	for _, tier := range []models.Tier{
+		{UserID: user.ID, Platform: "Railway", Name: "Few Bookmarks", BookmarksCount: 2, UpvoteCount: 9, IsPublic: true},
+		{UserID: user.ID, Platform: "Koyeb", Name: "Most Bookmarks", BookmarksCount: 5, IsPublic: true},
+		{UserID: user.ID, Platform: "Render", Name: "No Bookmarks", UpvoteCount: 20, IsPublic: true},
+	} {
+		db.Create(&tier)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tiers?sort=bookmarked", nil)
+	w := httptest.NewRecorder()
+	GetTiers(w, req)
+
+	var response struct {
+		Data []TierListItem `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+	var names []string
+	for _, item := range response.Data {
+		names = append(names, item.Name)
+	}
+	want := []string{"Most Bookmarks", "Few Bookmarks", "No Bookmarks"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected order %v, got %v", want, names)
+	}
+	if len(response.Data) > 0 && response.Data[0].BookmarksCount != 5 {
+		t.Errorf("Expected bookmarks_count 5 in the list, got %d", response.Data[0].BookmarksCount)
+	}
+}
+
+func TestParseCompareIDs(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []uint
+		wantErr bool
+	}{
+		{"3,1,2", []uint{3, 1, 2}, false},
+		{" 4 , 5 ", []uint{4, 5}, false},
+		{"2,2,1", []uint{2, 1}, false},
+		{"1,2,3,4,5", []uint{1, 2, 3, 4, 5}, false},
+		{"1,2,3,4,5,6", nil, true},
+		{"1,abc", nil, true},
+		{"1,,2", nil, true},
+		{"-1", nil, true},
+		{"0", nil, true},
+		{"99999999999", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCompareIDs(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCompareIDs(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCompareIDs(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestCompareTiers(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "comparer", Email: "comparer@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "onlooker", Email: "onlooker@example.com"}
+	db.Create(&other)
+
+	railway := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Railway Hobby", CPULimit: "0.5 vCPU", IsPublic: true}
+	db.Create(&railway)
+	koyeb := models.Tier{UserID: owner.ID, Platform: "Koyeb", Name: "Koyeb Starter", IsPublic: true}
+	db.Create(&koyeb)
+	private := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Render Private", IsPublic: true}
+	db.Create(&private)
+	db.Model(&private).Update("is_public", false)
+
+	compare := func(ids string, callerID uint) (*httptest.ResponseRecorder, CompareTiersResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers/compare?ids="+url.QueryEscape(ids), nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		CompareTiers(w, req)
+		var response CompareTiersResponse
+		json.NewDecoder(bytes.NewReader(w.Body.Bytes())).Decode(&response)
+		return w, response
+	}
+	idList := func(ids ...uint) string {
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = strconv.Itoa(int(id))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	t.Run("Input order with missing and private IDs", func(t *testing.T) {
+		w, response := compare(idList(koyeb.ID, 99999, private.ID, railway.ID), other.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(response.Tiers) != 2 || response.Tiers[0].ID != koyeb.ID || response.Tiers[1].ID != railway.ID {
+			t.Fatalf("Expected Koyeb then Railway, got %+v", response.Tiers)
+		}
+		if response.Tiers[1].CPULimit != "0.5 vCPU" || response.Tiers[1].User == nil {
+			t.Errorf("Expected full tier details with the author, got %+v", response.Tiers[1])
+		}
+		if !reflect.DeepEqual(response.MissingIDs, []uint{99999, private.ID}) {
+			t.Errorf("Expected missing_ids [99999 %d], got %v", private.ID, response.MissingIDs)
+		}
+	})
+
+	t.Run("Owner sees their private tier", func(t *testing.T) {
+		_, response := compare(idList(private.ID, railway.ID), owner.ID)
+		if len(response.Tiers) != 2 || len(response.MissingIDs) != 0 {
+			t.Errorf("Expected both tiers and no missing IDs, got %d tiers and %v", len(response.Tiers), response.MissingIDs)
+		}
+	})
+
+	t.Run("Anonymous callers", func(t *testing.T) {
+		w, response := compare(idList(railway.ID), 0)
+		if w.Code != http.StatusOK || len(response.Tiers) != 1 || response.MissingIDs == nil {
+			t.Errorf("Expected one tier and an empty missing_ids, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for _, ids := range []string{"", "1,x", "1,2,3,4,5,6"} {
+			if w, _ := compare(ids, 0); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for ids=%q, got %d", ids, w.Code)
+			}
+		}
+	})
+}
+
+func TestVoteTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "voter", Email: "voter@example.com"}
+	db.Create(&user)
+	owner := models.User{Username: "voteowner", Email: "voteowner@example.com", GitHubID: "voteowner_gh"}
+	db.Create(&owner)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	t.Run("Anonymous vote", func(t *testing.T) {
+		body, _ := json.Marshal(VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("Owner cannot vote on own tier", func(t *testing.T) {
+		// The voter comes from the token, not from user_id in the body
+		body, _ := json.Marshal(VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+		var response map[string]string
+		json.NewDecoder(w.Body).Decode(&response)
+		if response["error"] != "self_vote_not_allowed" || response["message"] != "You cannot vote on your own tier" {
+			t.Errorf("Unexpected response %v", response)
+		}
+
+		var count int64
+		db.Model(&models.Vote{}).Where("tier_id = ?", tier.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no vote recorded, got %d", count)
+		}
+	})
+
+	t.Run("Create upvote", func(t *testing.T) {
+		voteReq := VoteRequest{
+			UserID:   user.ID,
+			TierID:   tier.ID,
+			VoteType: 1,
+		}
+		body, _ := json.Marshal(voteReq)
+
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", w.Code)
+		}
+
+		// Check tier upvote count
+		var updatedTier models.Tier
+		db.First(&updatedTier, tier.ID)
+		if updatedTier.UpvoteCount != 1 {
+			t.Errorf("Expected upvote count 1, got %d", updatedTier.UpvoteCount)
+		}
+	})
+
+	t.Run("Switch vote type", func(t *testing.T) {
+		// Upvote to downvote and back again moves one count each way
+		for _, step := range []struct {
+			voteType  int8
+			upvotes   int
+			downvotes int
+		}{
+			{-1, 0, 1},
+			{1, 1, 0},
+		} {
+			body, _ := json.Marshal(VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: step.voteType})
+			req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+			req = asUser(req, user.ID)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			VoteTier(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+
+			var updatedTier models.Tier
+			db.First(&updatedTier, tier.ID)
+			if updatedTier.UpvoteCount != step.upvotes || updatedTier.DownvoteCount != step.downvotes {
+				t.Errorf("After switching to %d expected %d up / %d down, got %d / %d",
+					step.voteType, step.upvotes, step.downvotes, updatedTier.UpvoteCount, updatedTier.DownvoteCount)
+			}
+		}
+	})
+
+	t.Run("Toggle vote off", func(t *testing.T) {
+		// Vote first time
+		voteReq := VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: -1}
+		body, _ := json.Marshal(voteReq)
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		VoteTier(w, req)
+
+		// Vote again (should toggle off)
+		req = httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		VoteTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Hidden tier", func(t *testing.T) {
+		pending := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Pending Tier", Status: models.TierStatusPending}
+		db.Create(&pending)
+
+		body, _ := json.Marshal(VoteRequest{TierID: pending.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid vote type", func(t *testing.T) {
+		voteReq := VoteRequest{UserID: user.ID, TierID: tier.ID, VoteType: 5}
+		body, _ := json.Marshal(voteReq)
+
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestCreateComment(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "commenter", Email: "commenter@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	t.Run("Valid comment", func(t *testing.T) {
+		comment := models.Comment{
+			UserID:  user.ID,
+			TierID:  tier.ID,
+			Content: "Great tier!",
+		}
+		body, _ := json.Marshal(comment)
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", w.Code)
+		}
+
+		// Check tier comment count
+		var updatedTier models.Tier
+		db.First(&updatedTier, tier.ID)
+		if updatedTier.CommentCount != 1 {
+			t.Errorf("Expected comment count 1, got %d", updatedTier.CommentCount)
+		}
+	})
+
+	t.Run("Comment too long", func(t *testing.T) {
+		longContent := "This comment is way too long and exceeds the one hundred character limit that we have set for comments on tiers!"
+		comment := models.Comment{
+			UserID:  user.ID,
+			TierID:  tier.ID,
+			Content: longContent,
+		}
+		body, _ := json.Marshal(comment)
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Multibyte comment at limit", func(t *testing.T) {
+		comment := models.Comment{
+			UserID:  user.ID,
+			TierID:  tier.ID,
+			Content: strings.Repeat("é", 100),
+		}
+		body, _ := json.Marshal(comment)
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", w.Code)
+		}
+	})
+
+	t.Run("Empty comment", func(t *testing.T) {
+		comment := models.Comment{
+			UserID:  user.ID,
+			TierID:  tier.ID,
+			Content: "",
+		}
+		body, _ := json.Marshal(comment)
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Missing tier", func(t *testing.T) {
+		body, _ := json.Marshal(models.Comment{TierID: 999999, Content: "Anyone there?"})
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Hidden tier", func(t *testing.T) {
+		stranger := models.User{Username: "lurker", Email: "lurker@example.com"}
+		db.Create(&stranger)
+		pending := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Pending Tier", Status: models.TierStatusPending}
+		db.Create(&pending)
+		body, _ := json.Marshal(models.Comment{TierID: pending.ID, Content: "Found it"})
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, stranger.ID)
+		w := httptest.NewRecorder()
+		CreateComment(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for another user's pending tier, got %d", w.Code)
+		}
+
+		// The owner can still comment on their own pending tier
+		req = httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w = httptest.NewRecorder()
+		CreateComment(w, req)
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201 for the owner, got %d", w.Code)
+		}
+	})
+
+	t.Run("Author comes from the token", func(t *testing.T) {
+		other := models.User{Username: "impersonated", Email: "impersonated@example.com"}
+		db.Create(&other)
+		body, _ := json.Marshal(models.Comment{UserID: other.ID, TierID: tier.ID, Content: "Not really theirs"})
+
+		anonymous := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		CreateComment(w, anonymous)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 without a token, got %d", w.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w = httptest.NewRecorder()
+		CreateComment(w, req)
+
+		var created models.Comment
+		json.NewDecoder(w.Body).Decode(&created)
+		if w.Code != http.StatusCreated || created.UserID != user.ID {
+			t.Errorf("Expected the comment to be posted as the caller, got %d and user %d", w.Code, created.UserID)
+		}
+	})
+}
+
+func TestGetComments(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "commenter", Email: "commenter@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	// Create test comments
+	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Comment 1"})
+	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Comment 2"})
+
+	t.Run("Get comments for tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/comments?tier_id=1", nil)
+		w := httptest.NewRecorder()
+
+		GetComments(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var comments []models.Comment
+		json.NewDecoder(w.Body).Decode(&comments)
+
+		if len(comments) != 2 {
+			t.Errorf("Expected 2 comments, got %d", len(comments))
+		}
+	})
+
+	t.Run("Private tier", func(t *testing.T) {
+		private := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Private Tier"}
+		db.Create(&private)
+		db.Model(&private).Update("is_public", false)
+		db.Create(&models.Comment{UserID: user.ID, TierID: private.ID, Content: "Secret"})
+
+		req := httptest.NewRequest(http.MethodGet, "/comments?tier_id="+strconv.Itoa(int(private.ID)), nil)
+		w := httptest.NewRecorder()
+
+		GetComments(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Missing tier_id parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/comments", nil)
+		w := httptest.NewRecorder()
+
+		GetComments(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	stranger := models.User{Username: "stranger", Email: "stranger@example.com"}
+	db.Create(&stranger)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	deleteAs := func(callerID uint, role string) (*httptest.ResponseRecorder, models.Comment) {
+		comment := models.Comment{UserID: author.ID, TierID: tier.ID, Content: "Nice tier"}
+		db.Create(&comment)
+
+		req := httptest.NewRequest(http.MethodDelete, "/comments/"+strconv.Itoa(int(comment.ID)), nil)
+		req = asUser(req, callerID)
+		if role != "" {
+			req = asRole(req, role)
+		}
+		w := httptest.NewRecorder()
+		DeleteComment(w, req)
+		return w, comment
+	}
+
+	for _, tc := range []struct {
+		name   string
+		caller models.User
+		role   string
+	}{
+		{"Author can delete", author, ""},
+		{"Tier owner can delete", owner, ""},
+		{"Admin can delete", admin, models.RoleAdmin},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, comment := deleteAs(tc.caller.ID, tc.role)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if err := db.First(&models.Comment{}, comment.ID).Error; err == nil {
+				t.Error("Expected comment to be deleted")
+			}
+		})
+	}
+
+	t.Run("Other user is forbidden", func(t *testing.T) {
+		w, comment := deleteAs(stranger.ID, "")
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+		if err := db.First(&models.Comment{}, comment.ID).Error; err != nil {
+			t.Errorf("Expected comment to remain, got %v", err)
+		}
+	})
+}
+
+func TestGetTierComments(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "pager", Email: "pager@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Chatty Tier"}
+	db.Create(&tier)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 1; i <= 5; i++ {
+		db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Comment " + strconv.Itoa(i),
+			CreatedAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	base := "/tiers/" + strconv.Itoa(int(tier.ID)) + "/comments"
+	get := func(path string) (*httptest.ResponseRecorder, []models.Comment) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		GetTierComments(w, req)
+
+		var response struct {
+			Data []models.Comment `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Data
+	}
+
+	t.Run("Newest first by default", func(t *testing.T) {
+		w, comments := get(base + "?per_page=2")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if len(comments) != 2 || comments[0].Content != "Comment 5" || comments[0].User.ID != user.ID {
+			t.Errorf("Expected newest 2 comments with authors, got %+v", comments)
+		}
+		if w.Header().Get("X-Total-Count") != "5" {
+			t.Errorf("Expected X-Total-Count '5', got '%s'", w.Header().Get("X-Total-Count"))
+		}
+	})
+
+	t.Run("Oldest last page", func(t *testing.T) {
+		_, comments := get(base + "?per_page=2&page=3&sort=oldest")
+		if len(comments) != 1 || comments[0].Content != "Comment 5" {
+			t.Errorf("Expected only the newest comment on the last page, got %+v", comments)
+		}
+	})
+
+	t.Run("Unknown sort falls back to newest", func(t *testing.T) {
+		w, comments := get(base + "?per_page=1&sort=created_at;DROP TABLE comments")
+		if w.Code != http.StatusOK || len(comments) != 1 || comments[0].Content != "Comment 5" {
+			t.Errorf("Expected the newest comment, got %d %+v", w.Code, comments)
+		}
+	})
+
+	t.Run("Unknown tier", func(t *testing.T) {
+		if w, _ := get("/tiers/999999/comments"); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Pending tier", func(t *testing.T) {
+		pending := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Pending Tier", Status: models.TierStatusPending}
+		db.Create(&pending)
+		if w, _ := get("/tiers/" + strconv.Itoa(int(pending.ID)) + "/comments"); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("GetTier omits comments", func(t *testing.T) {
+		cache.Tiers.Invalidate(tier.ID)
+		req := httptest.NewRequest(http.MethodGet, "/tiers/"+strconv.Itoa(int(tier.ID)), nil)
+		w := httptest.NewRecorder()
+		GetTier(w, req)
+
+		var detail struct {
+			Comments []models.Comment `json:"comments"`
+		}
+		json.NewDecoder(w.Body).Decode(&detail)
+		if len(detail.Comments) != 0 {
+			t.Errorf("Expected no embedded comments, got %d", len(detail.Comments))
+		}
+	})
+}
+
+func TestGetVoteHistory(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "charter", Email: "charter@example.com"}
+	db.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Rising Tier"}
+	db.Create(&tier)
+	other := models.Tier{UserID: user.ID, Platform: "Render", Name: "Other Tier"}
+	db.Create(&other)
+
+	// Inserted out of order to check the sort
+	for _, day := range []int{3, 1, 2} {
+		db.Create(&models.VoteSnapshot{TierID: tier.ID, UpvoteCount: day * 10, DownvoteCount: day,
+			SnapshottedAt: time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)})
+	}
+	db.Create(&models.VoteSnapshot{TierID: other.ID, UpvoteCount: 99, SnapshottedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)})
+
+	base := "/tiers/" + strconv.Itoa(int(tier.ID)) + "/vote-history"
+	get := func(path string) (*httptest.ResponseRecorder, []VoteHistoryPoint) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		GetVoteHistory(w, req)
+
+		var response struct {
+			Data []VoteHistoryPoint `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Data
+	}
+
+	t.Run("Oldest first", func(t *testing.T) {
+		w, points := get(base)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if len(points) != 3 || points[0].Upvotes != 10 || points[1].Upvotes != 20 || points[2].Upvotes != 30 {
+			t.Errorf("Expected the tier's 3 snapshots in order, got %+v", points)
+		}
+	})
+
+	t.Run("Date range", func(t *testing.T) {
+		_, points := get(base + "?from=2024-01-02&to=2024-01-02T23:59:59Z")
+		if len(points) != 1 || points[0].Upvotes != 20 || points[0].Downvotes != 2 {
+			t.Errorf("Expected only the January 2 snapshot, got %+v", points)
+		}
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		if w, _ := get(base + "?from=last-week"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown tier", func(t *testing.T) {
+		if w, _ := get("/tiers/999999/vote-history"); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestValidateScreenshotURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"Empty URL", "", false},
+		{"Valid https URL", "https://example.com/pricing.png", false},
+		{"Plain http URL", "http://example.com/pricing.png", true},
+		{"Missing scheme", "example.com/pricing.png", true},
+		{"Unparseable URL", "https://exa mple.com/%zz", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScreenshotURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateScreenshotURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateTierEgress(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "egress", Email: "egress@example.com"}
+	db.Create(&owner)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Egress Tier", EgressFree: true, EgressLimitGB: 100}
+	db.Create(&tier)
+
+	path := "/tiers/" + strconv.Itoa(int(tier.ID))
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+		UpdateTier(w, req)
+		return w
+	}
+
+	t.Run("Reset to false and zero", func(t *testing.T) {
+		w := update(`{"egress_free": false, "egress_limit_gb": 0}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var updated models.Tier
+		db.First(&updated, tier.ID)
+		if updated.EgressFree || updated.EgressLimitGB != 0 {
+			t.Errorf("Expected egress fields to be reset, got %v and %v", updated.EgressFree, updated.EgressLimitGB)
+		}
+	})
+
+	t.Run("Omitted fields are kept", func(t *testing.T) {
+		update(`{"egress_limit_gb": 250.5}`)
+		update(`{"name": "Renamed"}`)
+
+		var updated models.Tier
+		db.First(&updated, tier.ID)
+		if updated.EgressLimitGB != 250.5 || updated.Name != "Renamed" {
+			t.Errorf("Expected limit 250.5 and name Renamed, got %v and %q", updated.EgressLimitGB, updated.Name)
+		}
+	})
+
+	t.Run("Negative limit", func(t *testing.T) {
+		w := update(`{"egress_limit_gb": -1}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestTierGeoRestrictions(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "geo", Email: "geo@example.com"}
+	db.Create(&owner)
+
+	usOnly := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "US Only", IsPublic: true,
+		GeoRestrictions: models.NewCountryList([]string{"US"})}
+	euOnly := models.Tier{UserID: owner.ID, Platform: "Koyeb", Name: "EU Only", IsPublic: true,
+		GeoRestrictions: models.NewCountryList([]string{"DE", "FR"})}
+	anywhere := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Anywhere", IsPublic: true}
+	for _, tier := range []*models.Tier{&usOnly, &euOnly, &anywhere} {
+		db.Create(tier)
+	}
+
+	t.Run("Filter by country", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers?geo=us", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		var response struct {
+			Data []models.Tier `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		names := map[string]bool{}
+		for _, tier := range response.Data {
+			names[tier.Name] = true
+		}
+		if len(response.Data) != 2 || !names["US Only"] || !names["Anywhere"] {
+			t.Errorf("Expected the US and unrestricted tiers, got %v", names)
+		}
+	})
+
+	t.Run("Clear with empty array", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/tiers/"+strconv.Itoa(int(euOnly.ID)), strings.NewReader(`{"geo_restrictions": []}`))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var updated models.Tier
+		db.First(&updated, euOnly.ID)
+		if updated.GeoRestrictions != "" {
+			t.Errorf("Expected geo restrictions to be cleared, got %q", updated.GeoRestrictions)
+		}
+	})
+}
+
+func TestUpdateTierScreenshot(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "other", Email: "other@example.com", GitHubID: "other_gh"}
+	db.Create(&other)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	path := "/tiers/" + strconv.Itoa(int(tier.ID)) + "/screenshot"
+
+	t.Run("Owner updates screenshot", func(t *testing.T) {
+		body, _ := json.Marshal(ScreenshotRequest{URL: "https://example.com/shot.png"})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTierScreenshot(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var updatedTier models.Tier
+		db.First(&updatedTier, tier.ID)
+		if updatedTier.ScreenshotURL != "https://example.com/shot.png" {
+			t.Errorf("Expected screenshot URL to be updated, got '%s'", updatedTier.ScreenshotURL)
+		}
+	})
+
+	t.Run("Non-owner is forbidden", func(t *testing.T) {
+		body, _ := json.Marshal(ScreenshotRequest{URL: "https://example.com/other.png"})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTierScreenshot(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Admin updates screenshot", func(t *testing.T) {
+		body, _ := json.Marshal(ScreenshotRequest{URL: "https://example.com/admin.png"})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		req = asUser(req, other.ID)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		UpdateTierScreenshot(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Non-https URL", func(t *testing.T) {
+		body, _ := json.Marshal(ScreenshotRequest{URL: "http://example.com/shot.png"})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTierScreenshot(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestModifyTierAuthorization(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	path := "/tiers/" + strconv.Itoa(int(tier.ID))
+	body := `{"name": "Renamed"}`
+
+	t.Run("Non-owner cannot update", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTier(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Owner can update", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Non-owner cannot delete", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		DeleteTier(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Admin can delete", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req = asUser(req, other.ID)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		DeleteTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Delete missing tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		DeleteTier(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestDeleteTierCascades(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "cascader", Email: "cascader@example.com"}
+	db.Create(&owner)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Doomed Tier"}
+	db.Create(&tier)
+	db.Create(&models.Vote{UserID: owner.ID, TierID: tier.ID, VoteType: 1})
+	db.Create(&models.Comment{UserID: owner.ID, TierID: tier.ID, Content: "Gone soon"})
+
+	req := httptest.NewRequest(http.MethodDelete, "/tiers/"+strconv.Itoa(int(tier.ID)), nil)
+	req = asUser(req, owner.ID)
+	w := httptest.NewRecorder()
+
+	DeleteTier(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var votes, comments int64
+	db.Model(&models.Vote{}).Where("tier_id = ?", tier.ID).Count(&votes)
+	db.Model(&models.Comment{}).Where("tier_id = ?", tier.ID).Count(&comments)
+	if votes != 0 || comments != 0 {
+		t.Errorf("Expected votes and comments to be deleted with the tier, got %d votes and %d comments", votes, comments)
+	}
+
+	// Soft-deleted, not erased
+	db.Unscoped().Model(&models.Vote{}).Where("tier_id = ?", tier.ID).Count(&votes)
+	if votes != 1 {
+		t.Errorf("Expected the vote to be soft-deleted, got %d rows", votes)
+	}
+}
+
+func TestValidateTierFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		tier    models.Tier
+		wantErr bool
+	}{
+		{"No optional fields", models.Tier{}, false},
+		{"Valid https URL", models.Tier{URL: "https://railway.app/pricing"}, false},
+		{"Valid http URL", models.Tier{URL: "http://railway.app/pricing"}, false},
+		{"Relative URL", models.Tier{URL: "railway.app/pricing"}, true},
+		{"Unsupported scheme", models.Tier{URL: "ftp://railway.app/pricing"}, true},
+		{"Scheme without host", models.Tier{URL: "https:///pricing"}, true},
+		{"Monthly hours digits", models.Tier{MonthlyHours: "500"}, false},
+		{"Monthly hours with suffix", models.Tier{MonthlyHours: "500/month"}, false},
+		{"Monthly hours with text", models.Tier{MonthlyHours: "500 hours"}, true},
+		{"Monthly hours wrong suffix", models.Tier{MonthlyHours: "500/week"}, true},
+		{"Invalid screenshot URL", models.Tier{ScreenshotURL: "http://example.com/shot.png"}, true},
+		{"Known pricing model", models.Tier{PricingModel: models.PricingFreemium}, false},
+		{"Unknown pricing model", models.Tier{PricingModel: "pay-as-you-go"}, true},
+		{"Known category", models.Tier{Category: models.CategoryCDN}, false},
+		{"Unknown category", models.Tier{Category: "serverless"}, true},
+		{"Valid country codes", models.Tier{GeoRestrictions: models.NewCountryList([]string{"us", "GB"})}, false},
+		{"Invalid country code", models.Tier{GeoRestrictions: models.NewCountryList([]string{"US", "XX"})}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTierFields(&tt.tier)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTierFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTierContent(t *testing.T) {
+	valid := func(modify func(*models.Tier)) models.Tier {
+		tier := models.Tier{Platform: "Railway", Name: "Railway Hobby", URL: "https://railway.app/pricing"}
+		modify(&tier)
+		return tier
+	}
+
+	tests := []struct {
+		name    string
+		tier    models.Tier
+		wantErr bool
+	}{
+		{"Valid tier", valid(func(*models.Tier) {}), false},
+		{"Platform with dot", valid(func(t *models.Tier) { t.Platform = "Fly.io" }), false},
+		{"Platform with space and digit", valid(func(t *models.Tier) { t.Platform = "Cloudflare R2" }), false},
+		{"Platform too short", valid(func(t *models.Tier) { t.Platform = "AB" }), true},
+		{"Platform starts with digit", valid(func(t *models.Tier) { t.Platform = "1Password" }), true},
+		{"Platform ends with dot", valid(func(t *models.Tier) { t.Platform = "Render." }), true},
+		{"Platform with symbols", valid(func(t *models.Tier) { t.Platform = "Rail$way" }), true},
+		{"Platform too long", valid(func(t *models.Tier) { t.Platform = "A" + strings.Repeat("b", 100) }), true},
+		{"Name of five characters", valid(func(t *models.Tier) { t.Name = "Hobby" }), false},
+		{"Non-ASCII name", valid(func(t *models.Tier) { t.Name = "Gratis Ücretsiz" }), false},
+		{"Name too short", valid(func(t *models.Tier) { t.Name = "asdf" }), true},
+		{"Name padded to length", valid(func(t *models.Tier) { t.Name = "  Test  " }), true},
+		{"Name without a word", valid(func(t *models.Tier) { t.Name = "- - - !" }), true},
+		{"No URL", valid(func(t *models.Tier) { t.URL = "" }), false},
+		{"http URL", valid(func(t *models.Tier) { t.URL = "http://railway.app/pricing" }), true},
+		{"Relative URL", valid(func(t *models.Tier) { t.URL = "railway.app/pricing" }), true},
+		{"URL without host", valid(func(t *models.Tier) { t.URL = "https:///pricing" }), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTierContent(&tt.tier)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTierContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateTierIdempotency(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+	idempotencyCache = newTTLCache(24 * time.Hour)
+
+	user := models.User{Username: "retryuser", Email: "retry@example.com"}
+	db.Create(&user)
+
+	post := func(key string, userID uint) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Hobby"})
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req.Header.Set("Idempotency-Key", key)
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		CreateTier(w, req)
+		return w
+	}
+
+	first := post("7f0c5a4e-retry", user.ID)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", first.Code, first.Body.String())
+	}
+
+	retry := post("7f0c5a4e-retry", user.ID)
+	if retry.Code != http.StatusOK {
+		t.Errorf("Expected status 200 on retry, got %d", retry.Code)
+	}
+	if retry.Header().Get("X-Idempotent-Replayed") != "true" {
+		t.Error("Expected X-Idempotent-Replayed: true on retry")
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected the first response replayed, got %s", retry.Body.String())
+	}
+
+	var count int64
+	db.Model(&models.Tier{}).Where("name = ?", "Koyeb Hobby").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 tier, got %d", count)
+	}
+}
+
+func TestCreateTierRequiresAuth(t *testing.T) {
+	newRequest := func() *http.Request {
+		body := `{"user_id": 1, "platform": "Railway", "name": "Railway Free"}`
+		return httptest.NewRequest(http.MethodPost, "/tiers", strings.NewReader(body))
+	}
+	// Only the user the auth middleware puts in the context counts
+	spoofed := newRequest()
+	spoofed.Header.Set("X-User-ID", "1")
+
+	for name, req := range map[string]*http.Request{
+		"Anonymous":            newRequest(),
+		"User ID 0":            asUser(newRequest(), 0),
+		"Identity header only": spoofed,
+	} {
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status 401, got %d", name, w.Code)
+		}
+	}
+}
+
+func TestServerAssignedTierFields(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "promoter", Email: "promoter@example.com"}
+	db.Create(&owner)
+
+	t.Run("Create ignores featured fields and counters", func(t *testing.T) {
+		body := `{"id": 999, "platform": "Railway", "name": "Self Featured", "is_featured": true,
+			"upvote_count": 50, "view_count": 1000, "bookmarks_count": 7}`
+		req := httptest.NewRequest(http.MethodPost, "/tiers", strings.NewReader(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var created models.Tier
+		db.Where("name = ?", "Self Featured").First(&created)
+		if created.ID == 999 || created.IsFeatured || created.FeaturedUntil != nil {
+			t.Errorf("Expected server-assigned id and no featuring, got id %d featured %v", created.ID, created.IsFeatured)
+		}
+		if created.UpvoteCount != 0 || created.ViewCount != 0 || created.BookmarksCount != 0 {
+			t.Errorf("Expected zero counters, got %d, %d and %d", created.UpvoteCount, created.ViewCount, created.BookmarksCount)
+		}
+	})
+
+	t.Run("Update ignores featured fields and counters", func(t *testing.T) {
+		tier := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Owned Tier", UpvoteCount: 3}
+		db.Create(&tier)
+
+		body := `{"is_featured": true, "featured_until": "2099-01-01T00:00:00Z", "upvote_count": 500}`
+		req := httptest.NewRequest(http.MethodPut, "/tiers/"+strconv.Itoa(int(tier.ID)), strings.NewReader(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var updated models.Tier
+		db.First(&updated, tier.ID)
+		if updated.IsFeatured || updated.FeaturedUntil != nil || updated.UpvoteCount != 3 {
+			t.Errorf("Expected featuring and counters unchanged, got %v, %v and %d", updated.IsFeatured, updated.FeaturedUntil, updated.UpvoteCount)
+		}
+	})
+
+	t.Run("Rejection reason is kept by moderators", func(t *testing.T) {
+		body := `{"platform": "Koyeb", "name": "Self Reviewed", "rejection_reason": "Looks great"}`
+		req := httptest.NewRequest(http.MethodPost, "/tiers", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		CreateTier(w, asUser(req, owner.ID))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var created models.Tier
+		db.Where("name = ?", "Self Reviewed").First(&created)
+		if created.RejectionReason != "" {
+			t.Errorf("Expected no rejection reason on a new tier, got %q", created.RejectionReason)
+		}
+
+		rejected := models.Tier{UserID: owner.ID, Platform: "Koyeb", Name: "Rejected Tier",
+			Status: models.TierStatusRejected, RejectionReason: "Not a free tier"}
+		db.Create(&rejected)
+
+		req = httptest.NewRequest(http.MethodPut, "/tiers/"+strconv.Itoa(int(rejected.ID)), strings.NewReader(`{"rejection_reason": "Approved by me"}`))
+		w = httptest.NewRecorder()
+		UpdateTier(w, asUser(req, owner.ID))
+
+		var updated models.Tier
+		db.First(&updated, rejected.ID)
+		if updated.RejectionReason != "Not a free tier" {
+			t.Errorf("Expected the moderator's reason to be kept, got %q", updated.RejectionReason)
+		}
+	})
+
+	t.Run("Update cannot move the tier to another user", func(t *testing.T) {
+		victim := models.User{Username: "victim", Email: "victim@example.com"}
+		db.Create(&victim)
+		tier := models.Tier{UserID: owner.ID, Platform: "Fly", Name: "Kept Tier"}
+		db.Create(&tier)
+
+		body := `{"user_id": ` + strconv.Itoa(int(victim.ID)) + `, "id": 999999, "created_at": "2001-01-01T00:00:00Z", "name": "Kept Tier v2"}`
+		req := httptest.NewRequest(http.MethodPut, "/tiers/"+strconv.Itoa(int(tier.ID)), strings.NewReader(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		UpdateTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var updated models.Tier
+		if err := db.First(&updated, tier.ID).Error; err != nil {
+			t.Fatalf("Expected the tier to keep its id: %v", err)
+		}
+		if updated.UserID != owner.ID || updated.Name != "Kept Tier v2" || updated.CreatedAt.Year() == 2001 {
+			t.Errorf("Expected only the name to change, got owner %d, name %q, created %v", updated.UserID, updated.Name, updated.CreatedAt)
+		}
+	})
+}
+
+func TestIdempotencyKeyHandling(t *testing.T) {
+	idempotencyCache = newTTLCache(24 * time.Hour)
+	t.Cleanup(func() { idempotencyCache = newTTLCache(24 * time.Hour) })
+
+	post := func(key string, userID uint, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tiers", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		CreateTier(w, req)
+		return w
+	}
+
+	t.Run("Cached response is replayed", func(t *testing.T) {
+		idempotencyCache.Set("1:abc", []byte(`{"id":5}`+"\n"))
+		w := post("abc", 1, "{}")
+		if w.Code != http.StatusOK || w.Body.String() != `{"id":5}`+"\n" {
+			t.Errorf("Expected the cached response, got %d %s", w.Code, w.Body.String())
+		}
+		if w.Header().Get("X-Idempotent-Replayed") != "true" {
+			t.Error("Expected X-Idempotent-Replayed: true")
+		}
+	})
+
+	t.Run("Keys are scoped per user", func(t *testing.T) {
+		w := post("abc", 2, "not json")
+		if w.Code != http.StatusBadRequest || w.Header().Get("X-Idempotent-Replayed") != "" {
+			t.Errorf("Expected user 2's request to be processed, got %d", w.Code)
+		}
+	})
+
+	t.Run("Failed requests are not cached", func(t *testing.T) {
+		post("failed", 1, "not json")
+		if _, ok := idempotencyCache.Get("1:failed"); ok {
+			t.Error("Expected no cache entry for a failed request")
+		}
+	})
+
+	t.Run("Request in progress", func(t *testing.T) {
+		idempotencyCache.Set("1:pending", idempotencyPending{})
+		if w := post("pending", 1, "{}"); w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("Key too long", func(t *testing.T) {
+		if w := post(strings.Repeat("k", 256), 1, "{}"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestCreateTierValidation(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "tieruser", Email: "tier@example.com"}
+	db.Create(&user)
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free", URL: "not a url"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid monthly hours", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free", MonthlyHours: "lots"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid pricing model", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free", PricingModel: "lifetime"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Pricing model defaults to free-forever", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		var created models.Tier
+		json.NewDecoder(w.Body).Decode(&created)
+		if created.PricingModel != models.PricingFreeForever {
+			t.Errorf("Expected pricing model %q, got %q", models.PricingFreeForever, created.PricingModel)
+		}
+	})
+}
+
+func TestTierReviewWorkflow(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "submitter", Email: "submitter@example.com"}
+	db.Create(&user)
+
+	t.Run("New tier is pending", func(t *testing.T) {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		var response models.Tier
+		json.NewDecoder(w.Body).Decode(&response)
+
+		if response.Status != models.TierStatusPending {
+			t.Errorf("Expected status 'pending', got '%s'", response.Status)
+		}
+	})
+
+	t.Run("Auto approve", func(t *testing.T) {
+		t.Setenv("TIER_AUTO_APPROVE", "true")
+
+		tier := models.Tier{UserID: user.ID, Platform: "Vercel", Name: "Vercel Free"}
+		body, _ := json.Marshal(tier)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers", bytes.NewBuffer(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+
+		CreateTier(w, req)
+
+		var response models.Tier
+		json.NewDecoder(w.Body).Decode(&response)
+
+		if response.Status != models.TierStatusApproved {
+			t.Errorf("Expected status 'approved', got '%s'", response.Status)
+		}
+	})
+
+	t.Run("Pending tiers are hidden from listing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		var response map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		data := response["data"].([]interface{})
+		if len(data) != 1 {
+			t.Errorf("Expected 1 approved tier, got %d", len(data))
+		}
+	})
+
+	t.Run("Admin lists pending tiers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/tiers?status=pending", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		GetAdminTiers(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var tiers []models.Tier
+		json.NewDecoder(w.Body).Decode(&tiers)
+
+		if len(tiers) != 1 {
+			t.Errorf("Expected 1 pending tier, got %d", len(tiers))
+		}
+	})
+
+	t.Run("Non-admin cannot list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/tiers", nil)
+		w := httptest.NewRecorder()
+
+		GetAdminTiers(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Admin approves and rejects", func(t *testing.T) {
+		var pending models.Tier
+		db.Where("status = ?", models.TierStatusPending).First(&pending)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/tiers/"+strconv.Itoa(int(pending.ID))+"/approve", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		ApproveTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var updated models.Tier
+		db.First(&updated, pending.ID)
+		if updated.Status != models.TierStatusApproved {
+			t.Errorf("Expected status 'approved', got '%s'", updated.Status)
+		}
+
+		req = httptest.NewRequest(http.MethodPost, "/admin/tiers/"+strconv.Itoa(int(pending.ID))+"/reject", nil)
+		req = asRole(req, models.RoleAdmin)
+		w = httptest.NewRecorder()
+
+		RejectTier(w, req)
+
+		db.First(&updated, pending.ID)
+		if updated.Status != models.TierStatusRejected {
+			t.Errorf("Expected status 'rejected', got '%s'", updated.Status)
+		}
+	})
+}
+
+func TestBulkSetTierStatus(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "bulkowner", Email: "bulkowner@example.com"}
+	db.Create(&user)
+
+	var pending []uint
+	for i := 0; i < 3; i++ {
+		tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Pending " + strconv.Itoa(i), Status: models.TierStatusPending}
+		db.Create(&tier)
+		pending = append(pending, tier.ID)
+	}
+	approved := models.Tier{UserID: user.ID, Platform: "Render", Name: "Already approved", Status: models.TierStatusApproved}
+	db.Create(&approved)
+
+	post := func(role string, body interface{}) *httptest.ResponseRecorder {
+		encoded, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/admin/tiers/bulk-status", bytes.NewBuffer(encoded))
+		req = asRole(req, role)
+		w := httptest.NewRecorder()
+		BulkSetTierStatus(w, req)
+		return w
+	}
+
+	t.Run("Regular user is forbidden", func(t *testing.T) {
+		w := post(models.RoleUser, BulkStatusRequest{IDs: pending, Status: models.TierStatusApproved})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		w := post(models.RoleAdmin, BulkStatusRequest{IDs: pending, Status: "pending"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Moderator rejects pending tiers only", func(t *testing.T) {
+		ids := []uint{pending[0], pending[1], approved.ID}
+		w := post(models.RoleModerator, BulkStatusRequest{IDs: ids, Status: models.TierStatusRejected, RejectionReason: "Not a free tier"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Updated int `json:"updated"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		if response.Updated != 2 {
+			t.Errorf("Expected 2 tiers updated, got %d", response.Updated)
+		}
+
+		var rejected models.Tier
+		db.First(&rejected, pending[0])
+		if rejected.Status != models.TierStatusRejected || rejected.RejectionReason != "Not a free tier" {
+			t.Errorf("Expected a rejected tier with its reason, got %q %q", rejected.Status, rejected.RejectionReason)
+		}
+		var untouched models.Tier
+		db.First(&untouched, approved.ID)
+		if untouched.Status != models.TierStatusApproved || untouched.RejectionReason != "" {
+			t.Errorf("Expected the approved tier unchanged, got %q %q", untouched.Status, untouched.RejectionReason)
+		}
+	})
+
+	t.Run("Admin approves and owner is notified", func(t *testing.T) {
+		w := post(models.RoleAdmin, BulkStatusRequest{IDs: pending, Status: models.TierStatusApproved, RejectionReason: "ignored"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var tier models.Tier
+		db.First(&tier, pending[2])
+		if tier.Status != models.TierStatusApproved || tier.RejectionReason != "" {
+			t.Errorf("Expected the last pending tier approved without a reason, got %q %q", tier.Status, tier.RejectionReason)
+		}
+		var count int64
+		db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", user.ID, models.NotificationTierVerified).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected 1 verified notification, got %d", count)
+		}
+	})
+}
+
+func TestGetUserStats(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "contributor", Email: "contributor@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Railway Free", UpvoteCount: 3, DownvoteCount: 1}
+	db.Create(&tier)
+	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Nice"})
+	db.Create(&models.Vote{UserID: user.ID, TierID: tier.ID, VoteType: 1})
+	db.Create(&models.Bookmark{UserID: user.ID, TierID: tier.ID})
+
+	t.Run("Existing user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/"+strconv.Itoa(int(user.ID))+"/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetUserStats(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var stats UserStats
+		json.NewDecoder(w.Body).Decode(&stats)
+
+		if stats.TiersCreated != 1 {
+			t.Errorf("Expected 1 tier created, got %d", stats.TiersCreated)
+		}
+		if stats.TotalUpvotesReceived != 3 {
+			t.Errorf("Expected 3 upvotes received, got %d", stats.TotalUpvotesReceived)
+		}
+		if stats.TotalDownvotesReceived != 1 {
+			t.Errorf("Expected 1 downvote received, got %d", stats.TotalDownvotesReceived)
+		}
+		if stats.CommentsWritten != 1 {
+			t.Errorf("Expected 1 comment written, got %d", stats.CommentsWritten)
+		}
+		if stats.VotesCast != 1 {
+			t.Errorf("Expected 1 vote cast, got %d", stats.VotesCast)
+		}
+		if stats.BookmarksCount != 1 {
+			t.Errorf("Expected 1 bookmark, got %d", stats.BookmarksCount)
+		}
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/9999/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetUserStats(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid user ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/abc/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetUserStats(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "profiled", Email: "profiled@example.com", Role: models.RoleAdmin,
+		GitHubID: "4242", GitHubLogin: "profiled", Bio: "Hosting nerd", Location: "Jakarta"}
+	db.Create(&user)
+
+	t.Run("Existing user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/"+strconv.Itoa(int(user.ID)), nil)
+		w := httptest.NewRecorder()
+
+		GetUser(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var profile map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&profile)
+		if profile["username"] != "profiled" || profile["bio"] != "Hosting nerd" || profile["location"] != "Jakarta" {
+			t.Errorf("Expected the public profile, got %v", profile)
+		}
+		for _, field := range []string{"email", "role", "github_id", "last_login_at", "deletion_scheduled_at"} {
+			if _, ok := profile[field]; ok {
+				t.Errorf("Expected %s to be left out, got %v", field, profile[field])
+			}
+		}
+	})
+
+	t.Run("Collections", func(t *testing.T) {
+		db.Create(&models.Collection{UserID: user.ID, Name: "Shared picks", IsPublic: true})
+		private := models.Collection{UserID: user.ID, Name: "Drafts"}
+		db.Create(&private)
+		db.Model(&private).Update("is_public", false)
+
+		collections := func(callerID uint) []models.Collection {
+			req := httptest.NewRequest(http.MethodGet, "/users/"+strconv.Itoa(int(user.ID)), nil)
+			if callerID != 0 {
+				req = asUser(req, callerID)
+			}
+			w := httptest.NewRecorder()
+			GetUser(w, req)
+
+			var profile UserProfileDetail
+			json.NewDecoder(w.Body).Decode(&profile)
+			return profile.Collections
+		}
+
+		if got := collections(0); len(got) != 1 || got[0].Name != "Shared picks" {
+			t.Errorf("Expected only the public collection, got %+v", got)
+		}
+		if got := collections(user.ID); len(got) != 2 {
+			t.Errorf("Expected the owner to see both collections, got %+v", got)
+		}
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/999999", nil)
+		w := httptest.NewRecorder()
+
+		GetUser(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+		w := httptest.NewRecorder()
+
+		GetUser(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestUpdateUserAvatar(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "avatarer", Email: "avatarer@example.com", AvatarURL: "https://example.com/old.png",
+		Bio: "Unchanged bio", Location: "Bandung"}
+	db.Create(&user)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+
+	patch := func(callerID uint, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/users/"+strconv.Itoa(int(user.ID))+"/avatar", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		UpdateUserAvatar(w, req)
+		return w
+	}
+
+	t.Run("Only avatar_url changes", func(t *testing.T) {
+		w := patch(user.ID, `{"avatar_url": "https://example.com/new.png", "bio": "Hacked", "username": "renamed"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var updated models.User
+		db.First(&updated, user.ID)
+		if updated.AvatarURL != "https://example.com/new.png" {
+			t.Errorf("Expected new avatar, got %q", updated.AvatarURL)
+		}
+		if updated.Username != user.Username || updated.Bio != user.Bio || updated.Location != user.Location || updated.Email != user.Email {
+			t.Errorf("Expected other fields unchanged, got %+v", updated)
+		}
+	})
+
+	t.Run("Rejects non-https URLs", func(t *testing.T) {
+		for _, body := range []string{`{"avatar_url": "http://example.com/a.png"}`, `{"avatar_url": "https://"}`, `{"avatar_url": ""}`} {
+			if w := patch(user.ID, body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", body, w.Code)
+			}
+		}
+	})
+
+	t.Run("Other user is forbidden", func(t *testing.T) {
+		if w := patch(other.ID, `{"avatar_url": "https://example.com/evil.png"}`); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Requires authentication", func(t *testing.T) {
+		if w := patch(0, `{"avatar_url": "https://example.com/anon.png"}`); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+func TestUpdateUser(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "updater", Email: "updater@example.com", Bio: "Old bio", Role: models.RoleUser}
+	db.Create(&user)
+	other := models.User{Username: "taken", Email: "taken@example.com"}
+	db.Create(&other)
+
+	put := func(callerID uint, role, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/users/"+strconv.Itoa(int(user.ID)), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		if role != "" {
+			req = asRole(req, role)
+		}
+		w := httptest.NewRecorder()
+		UpdateUser(w, req)
+		return w
+	}
+
+	t.Run("Owner updates given fields only", func(t *testing.T) {
+		w := put(user.ID, "", `{"bio": "New bio", "role": "admin"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var updated models.User
+		db.First(&updated, user.ID)
+		if updated.Bio != "New bio" {
+			t.Errorf("Expected new bio, got %q", updated.Bio)
+		}
+		if updated.Username != user.Username || updated.Email != user.Email || updated.Role != models.RoleUser {
+			t.Errorf("Expected other fields unchanged, got %+v", updated)
+		}
+	})
+
+	t.Run("Admin can update another user", func(t *testing.T) {
+		if w := put(other.ID, models.RoleAdmin, `{"username": "renamed"}`); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var updated models.User
+		db.First(&updated, user.ID)
+		if updated.Username != "renamed" {
+			t.Errorf("Expected username renamed, got %q", updated.Username)
+		}
+	})
+
+	t.Run("Other user is forbidden", func(t *testing.T) {
+		if w := put(other.ID, "", `{"bio": "Hacked"}`); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Requires authentication", func(t *testing.T) {
+		if w := put(0, "", `{"bio": "Anonymous"}`); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("Rejects invalid fields", func(t *testing.T) {
+		for _, body := range []string{`{"username": "x"}`, `{"email": "  "}`, `{"bio": "` + strings.Repeat("a", 501) + `"}`} {
+			if w := put(user.ID, "", body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %.40s, got %d", body, w.Code)
+			}
+		}
+	})
+
+	t.Run("Taken username conflicts", func(t *testing.T) {
+		if w := put(user.ID, "", `{"username": "taken"}`); w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Rejects malformed emails", func(t *testing.T) {
+		for _, email := range []string{"not-an-email", "a@", "Updater <updater@example.com>", "two@at@example.com"} {
+			body, _ := json.Marshal(map[string]string{"email": email})
+			if w := put(user.ID, "", string(body)); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %q, got %d", email, w.Code)
+			}
+		}
+	})
+
+	t.Run("Email domain rules apply", func(t *testing.T) {
+		t.Setenv("BLOCKED_EMAIL_DOMAINS", "mailinator.com")
+		t.Setenv("ALLOWED_EMAIL_DOMAINS", "example.com")
+
+		if w := put(user.ID, "", `{"email": "updater@mailinator.com"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for a blocked domain, got %d", w.Code)
+		}
+		if w := put(user.ID, "", `{"email": "updater@elsewhere.org"}`); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 outside the allowed domains, got %d", w.Code)
+		}
+		if w := put(user.ID, "", `{"email": "updater@mail.example.com"}`); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for an allowed domain, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "leaver", Email: "leaver@example.com"}
+	db.Create(&user)
+	other := models.User{Username: "stayer", Email: "stayer@example.com"}
+	db.Create(&other)
+
+	del := func(id, callerID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/users/"+strconv.Itoa(int(id)), nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		DeleteUser(w, req)
+		return w
+	}
+
+	if w := del(user.ID, 0); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a user, got %d", w.Code)
+	}
+	if w := del(user.ID, other.ID); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another user, got %d", w.Code)
+	}
+
+	session := models.Session{UserID: user.ID, JTI: "leaver-session", ExpiresAt: time.Now().Add(time.Hour)}
+	db.Create(&session)
+
+	if w := del(user.ID, user.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := db.First(&models.User{}, user.ID).Error; err == nil {
+		t.Error("Expected deleted user to be gone")
+	}
+	var sessions, revoked int64
+	db.Model(&models.Session{}).Where("user_id = ?", user.ID).Count(&sessions)
+	db.Model(&models.RevokedToken{}).Where("jti = ?", session.JTI).Count(&revoked)
+	if sessions != 0 || revoked != 1 {
+		t.Errorf("Expected the user's sessions revoked, got %d sessions and %d revoked tokens", sessions, revoked)
+	}
+	if w := del(user.ID, user.ID); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted user, got %d", w.Code)
+	}
+}
+
+func TestErrorCodesInTaxonomy(t *testing.T) {
+	fset := token.NewFileSet()
+
+	// The taxonomy is every ErrCode constant in errorcodes.go
+	taxonomy, err := parser.ParseFile(fset, "errorcodes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse errorcodes.go: %v", err)
+	}
+	codes := map[string]bool{}
+	constants := map[string]bool{}
+	ast.Inspect(taxonomy, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok {
+			for i, name := range spec.Names {
+				code, _ := strconv.Unquote(spec.Values[i].(*ast.BasicLit).Value)
+				codes[code] = true
+				constants[name.Name] = true
+			}
+		}
+		return true
+	})
+
+	// Every "error" code written by a JSON error response anywhere in the
+	// module, as a literal or through writeError, must be one of them
+	checked := 0
+	err = filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "docs" || strings.HasPrefix(d.Name(), ".")) && path != ".." {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.KeyValueExpr:
+				key, ok := n.Key.(*ast.BasicLit)
+				value, isLit := n.Value.(*ast.BasicLit)
+				if !ok || key.Value != `"error"` || !isLit || value.Kind != token.STRING {
+					return true
+				}
+				code, _ := strconv.Unquote(value.Value)
+				if !codes[code] {
+					t.Errorf("%s: error code %q is not in the taxonomy", fset.Position(n.Pos()), code)
+				}
+				checked++
+			case *ast.CallExpr:
+				if fn, ok := n.Fun.(*ast.Ident); !ok || fn.Name != "writeError" || len(n.Args) != 4 {
+					return true
+				}
+				if code, ok := n.Args[2].(*ast.Ident); !ok || !constants[code.Name] {
+					t.Errorf("%s: writeError must be given an ErrCode constant", fset.Position(n.Pos()))
+				}
+				checked++
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan sources: %v", err)
+	}
+	if checked == 0 {
+		t.Error("Expected to find JSON error responses to check")
+	}
+}
+
+func TestTTLCache(t *testing.T) {
+	cache := newTTLCache(50 * time.Millisecond)
+
+	cache.Set("key", "value")
+	if v, ok := cache.Get("key"); !ok || v != "value" {
+		t.Errorf("Expected cached 'value', got %v (found=%v)", v, ok)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected missing key to be absent")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected entry to expire after TTL")
+	}
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+	leaderboardCache = newTTLCache(time.Hour)
+
+	top := models.User{Username: "top", Email: "top@example.com"}
+	db.Create(&top)
+	second := models.User{Username: "second", Email: "second@example.com", GitHubID: "second_gh"}
+	db.Create(&second)
+
+	db.Create(&models.Tier{UserID: top.ID, Platform: "Railway", Name: "A", IsPublic: true, UpvoteCount: 10})
+	db.Create(&models.Tier{UserID: top.ID, Platform: "Vercel", Name: "B", IsPublic: true, UpvoteCount: 5})
+	db.Create(&models.Tier{UserID: second.ID, Platform: "Koyeb", Name: "C", IsPublic: true, UpvoteCount: 7})
+
+	t.Run("All time ranking", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/leaderboard", nil)
+		w := httptest.NewRecorder()
+
+		GetLeaderboard(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var entries []LeaderboardEntry
+		json.NewDecoder(w.Body).Decode(&entries)
+
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(entries))
+		}
+		if entries[0].Username != "top" || entries[0].TotalUpvotes != 15 {
+			t.Errorf("Expected 'top' with 15 upvotes first, got '%s' with %d", entries[0].Username, entries[0].TotalUpvotes)
+		}
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/leaderboard?period=year", nil)
+		w := httptest.NewRecorder()
+
+		GetLeaderboard(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestPlatformStats(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "platformer", Email: "platformer@example.com"}
+	db.Create(&user)
+
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Hobby", IsPublic: true, UpvoteCount: 4})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "railway", Name: "Trial", IsPublic: true, UpvoteCount: 2})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Vercel", Name: "Hobby", IsPublic: true})
+
+	t.Run("Single platform is case-insensitive", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/platforms/RAILWAY/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetPlatformStats(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var stats PlatformStats
+		json.NewDecoder(w.Body).Decode(&stats)
+
+		if stats.TierCount != 2 {
+			t.Errorf("Expected 2 tiers, got %d", stats.TierCount)
+		}
+		if stats.AvgUpvotes != 3 {
+			t.Errorf("Expected average of 3 upvotes, got %f", stats.AvgUpvotes)
+		}
+		if stats.MostVotedTier == nil || stats.MostVotedTier.Name != "Hobby" {
+			t.Error("Expected most voted tier to be 'Hobby'")
+		}
+	})
+
+	t.Run("Unknown platform", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/platforms/heroku/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetPlatformStats(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Top platforms", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/platforms/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetTopPlatformStats(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var stats []PlatformStats
+		json.NewDecoder(w.Body).Decode(&stats)
+
+		if len(stats) != 3 {
+			t.Errorf("Expected 3 platform entries, got %d", len(stats))
+		}
+	})
+}
+
+func TestNotifications(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+	upvoteThrottle = NewNotificationThrottle(time.Hour)
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	voter := models.User{Username: "voter", Email: "voter@example.com", GitHubID: "voter_gh"}
+	db.Create(&voter)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	t.Run("Upvote notifies owner", func(t *testing.T) {
+		body, _ := json.Marshal(VoteRequest{UserID: voter.ID, TierID: tier.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, voter.ID)
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		var count int64
+		db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", owner.ID, models.NotificationTierUpvoted).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected 1 upvote notification, got %d", count)
+		}
+	})
+
+	t.Run("Upvotes within the hour are throttled", func(t *testing.T) {
+		second := models.User{Username: "voter2", Email: "voter2@example.com", GitHubID: "voter2_gh"}
+		db.Create(&second)
+		body, _ := json.Marshal(VoteRequest{UserID: second.ID, TierID: tier.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, second.ID)
+		w := httptest.NewRecorder()
+
+		VoteTier(w, req)
+
+		var count int64
+		db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", owner.ID, models.NotificationTierUpvoted).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected still 1 upvote notification, got %d", count)
+		}
+	})
+
+	t.Run("Owner comment does not notify", func(t *testing.T) {
+		body, _ := json.Marshal(models.Comment{UserID: owner.ID, TierID: tier.ID, Content: "My own tier"})
+		req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(body))
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		CreateComment(w, req)
+
+		var count int64
+		db.Model(&models.Notification{}).Where("type = ?", models.NotificationCommentAdded).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no comment notification, got %d", count)
+		}
+	})
+
+	t.Run("List and mark read", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/notifications", nil)
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		GetNotifications(w, req)
+
+		var notifications []models.Notification
+		json.NewDecoder(w.Body).Decode(&notifications)
+		if len(notifications) != 1 {
+			t.Fatalf("Expected 1 unread notification, got %d", len(notifications))
+		}
+
+		body, _ := json.Marshal(MarkReadRequest{IDs: []uint{notifications[0].ID}})
+		req = httptest.NewRequest(http.MethodPost, "/notifications/mark-read", bytes.NewBuffer(body))
+		req = asUser(req, owner.ID)
+		w = httptest.NewRecorder()
+
+		MarkNotificationsRead(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var unread int64
+		db.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", owner.ID, false).Count(&unread)
+		if unread != 0 {
+			t.Errorf("Expected 0 unread notifications, got %d", unread)
+		}
+	})
+}
+
+func TestRestoreAndPurgeTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Nice"})
+	db.Delete(&tier)
+
+	base := "/admin/tiers/" + strconv.Itoa(int(tier.ID))
+
+	t.Run("Non-admin cannot restore", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, base+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		RestoreTier(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Admin restores tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, base+"/restore", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		RestoreTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var restored models.Tier
+		if err := db.First(&restored, tier.ID).Error; err != nil {
+			t.Errorf("Restored tier should be visible: %v", err)
+		}
+	})
+
+	t.Run("Admin purges tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, base+"/purge", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		PurgeTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var count int64
+		db.Unscoped().Model(&models.Comment{}).Where("tier_id = ?", tier.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected comments to be purged, got %d", count)
+		}
+	})
+
+	t.Run("Restore purged tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, base+"/restore", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		RestoreTier(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestRestoreTierVotesAndComments(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "restorer", Email: "restorer@example.com"}
+	db.Create(&owner)
+	voter := models.User{Username: "loyal", Email: "loyal@example.com"}
+	db.Create(&voter)
+	former := models.User{Username: "former", Email: "former@example.com"}
+	db.Create(&former)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Returning Tier"}
+	db.Create(&tier)
+	path := "/tiers/" + strconv.Itoa(int(tier.ID))
+
+	vote := func(userID uint) int {
+		body, _ := json.Marshal(VoteRequest{TierID: tier.ID, VoteType: 1})
+		req := httptest.NewRequest(http.MethodPost, "/votes", bytes.NewBuffer(body))
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		VoteTier(w, req)
+		return w.Code
+	}
+
+	vote(voter.ID)
+	// A vote toggled off before the delete must stay deleted after the restore
+	toggled := models.Vote{UserID: former.ID, TierID: tier.ID, VoteType: -1}
+	db.Create(&toggled)
+	db.Delete(&toggled)
+	db.Create(&models.Comment{UserID: voter.ID, TierID: tier.ID, Content: "Still here"})
+
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	DeleteTier(httptest.NewRecorder(), asUser(req, owner.ID))
+
+	req = httptest.NewRequest(http.MethodPost, "/admin"+path+"/restore", nil)
+	w := httptest.NewRecorder()
+	RestoreTier(w, asRole(req, models.RoleAdmin))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var votes, comments int64
+	db.Model(&models.Vote{}).Where("tier_id = ?", tier.ID).Count(&votes)
+	db.Model(&models.Comment{}).Where("tier_id = ?", tier.ID).Count(&comments)
+	if votes != 1 || comments != 1 {
+		t.Errorf("Expected the vote and comment deleted with the tier back, got %d votes and %d comments", votes, comments)
+	}
+
+	// The restored vote is found again, so voting toggles it instead of
+	// colliding with idx_user_tier
+	if code := vote(voter.ID); code != http.StatusOK {
+		t.Errorf("Expected the restored vote to be toggled off with status 200, got %d", code)
+	}
+	var restored models.Tier
+	db.First(&restored, tier.ID)
+	if restored.UpvoteCount != 0 {
+		t.Errorf("Expected upvote count 0 after the toggle, got %d", restored.UpvoteCount)
+	}
+}
+
+func TestMergePlatforms(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "merger", Email: "merger@example.com"}
+	db.Create(&user)
+
+	// Created directly so the duplicate spellings skip normalization
+	for _, platform := range []string{"railway", "RAILWAY", "Railway"} {
+		db.Create(&models.Tier{UserID: user.ID, Platform: platform, Name: platform + " tier", IsPublic: true})
+	}
+	db.Create(&models.Platform{Name: "railway"})
+	db.Create(&models.Platform{Name: "Railway", URL: "https://railway.app"})
+
+	body := `{"source_platform": "railway", "target_platform": "railway.app"}`
+
+	t.Run("Non-admin cannot merge", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/platforms/merge", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		MergePlatforms(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Admin merges platforms", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/platforms/merge", strings.NewReader(body))
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		MergePlatforms(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var response struct {
+			Platform string `json:"platform"`
+			Updated  int64  `json:"updated"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		if response.Platform != "Railway" || response.Updated != 2 {
+			t.Errorf("Expected 2 tiers moved to Railway, got %+v", response)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/tiers?platform=Railway", nil)
+		w = httptest.NewRecorder()
+		GetTiers(w, req)
+		if w.Header().Get("X-Total-Count") != "3" {
+			t.Errorf("Expected all 3 tiers under Railway, got '%s'", w.Header().Get("X-Total-Count"))
+		}
+
+		var records []models.Platform
+		db.Order("name").Find(&records)
+		if len(records) != 1 || records[0].Name != "Railway" {
+			t.Errorf("Expected only the target platform record to remain, got %+v", records)
+		}
+	})
+
+	t.Run("Colliding tier names", func(t *testing.T) {
+		lower := models.Tier{UserID: user.ID, Platform: "koyeb", Name: "Hobby", IsPublic: true}
+		db.Create(&lower)
+		db.Create(&models.Tier{UserID: user.ID, Platform: "Koyeb", Name: "Hobby", IsPublic: true})
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/platforms/merge",
+			strings.NewReader(`{"source_platform": "koyeb", "target_platform": "Koyeb"}`))
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		MergePlatforms(w, req)
+
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Expected status 409, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Collisions []PlatformMergeCollision `json:"collisions"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		if len(response.Collisions) != 1 || response.Collisions[0].UserID != user.ID || response.Collisions[0].Name != "Hobby" {
+			t.Errorf("Expected the Hobby collision to be reported, got %+v", response.Collisions)
+		}
+
+		var unchanged models.Tier
+		db.First(&unchanged, lower.ID)
+		if unchanged.Platform != "koyeb" {
+			t.Errorf("Expected no tier to move, got platform %q", unchanged.Platform)
+		}
+	})
+}
+
+func TestGetPlatformDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "deduper", Email: "deduper@example.com"}
+	db.Create(&user)
+
+	// Created directly so the duplicate spellings skip normalization
+	tiers := []models.Tier{
+		{Platform: "railway", UpvoteCount: 1},
+		{Platform: "RAILWAY", UpvoteCount: 2},
+		{Platform: "Railway", UpvoteCount: 3},
+		{Platform: "render", UpvoteCount: 4},
+		{Platform: "Render", UpvoteCount: 5},
+		{Platform: "Koyeb", UpvoteCount: 6},
+		{Platform: "Koyeb", UpvoteCount: 7},
+	}
+	for i := range tiers {
+		tiers[i].UserID = user.ID
+		tiers[i].Name = "Tier " + strconv.Itoa(i)
+		db.Create(&tiers[i])
+	}
+
+	get := func(query string, role string) (*httptest.ResponseRecorder, []PlatformDuplicateGroup) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/platforms/duplicates"+query, nil)
+		req = asRole(req, role)
+		w := httptest.NewRecorder()
+		GetPlatformDuplicates(w, req)
+
+		var groups []PlatformDuplicateGroup
+		json.NewDecoder(w.Body).Decode(&groups)
+		return w, groups
+	}
+
+	t.Run("Non-admin is forbidden", func(t *testing.T) {
+		if w, _ := get("", models.RoleUser); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Groups with most variants first", func(t *testing.T) {
+		w, groups := get("", models.RoleAdmin)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if len(groups) != 2 {
+			t.Fatalf("Expected railway and render, got %+v", groups)
+		}
+		railway := groups[0]
+		if railway.Platform != "railway" || railway.VariantCount != 3 || railway.TierCount != 3 || railway.TotalVotes != 6 {
+			t.Errorf("Unexpected railway group %+v", railway)
+		}
+		if strings.Join(railway.Variants, ",") != "RAILWAY,Railway,railway" {
+			t.Errorf("Expected all railway spellings, got %v", railway.Variants)
+		}
+		if groups[1].Platform != "render" || groups[1].VariantCount != 2 {
+			t.Errorf("Unexpected render group %+v", groups[1])
+		}
+	})
+
+	t.Run("Threshold filters small groups", func(t *testing.T) {
+		_, groups := get("?threshold=2", models.RoleAdmin)
+		if len(groups) != 1 || groups[0].Platform != "railway" {
+			t.Errorf("Expected only railway, got %+v", groups)
+		}
+	})
+
+	t.Run("Invalid threshold", func(t *testing.T) {
+		if w, _ := get("?threshold=many", models.RoleAdmin); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetAdminUsers(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	alice := models.User{Username: "alice", Email: "alice@example.com"}
+	db.Create(&alice)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+	gone := models.User{Username: "gone", Email: "gone@example.com"}
+	db.Create(&gone)
+	db.Delete(&gone)
+
+	db.Create(&models.Tier{UserID: alice.ID, Platform: "Railway", Name: "Tier A"})
+	db.Create(&models.Tier{UserID: alice.ID, Platform: "Koyeb", Name: "Tier B"})
+
+	search := func(query string) []AdminUser {
+		req := httptest.NewRequest(http.MethodGet, "/admin/users"+query, nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		GetAdminUsers(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var response struct {
+			Data []AdminUser `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		return response.Data
+	}
+
+	t.Run("Non-admin forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		w := httptest.NewRecorder()
+
+		GetAdminUsers(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Active users only by default", func(t *testing.T) {
+		if users := search(""); len(users) != 2 {
+			t.Errorf("Expected 2 users, got %d", len(users))
+		}
+	})
+
+	t.Run("Include deleted", func(t *testing.T) {
+		users := search("?include_deleted=true&q=gone")
+		if len(users) != 1 {
+			t.Fatalf("Expected 1 user, got %d", len(users))
+		}
+		if users[0].DeletedAt == nil {
+			t.Error("Expected deleted_at to be set")
+		}
+	})
+
+	t.Run("Search and tier count", func(t *testing.T) {
+		users := search("?q=alice")
+		if len(users) != 1 {
+			t.Fatalf("Expected 1 user, got %d", len(users))
+		}
+		if users[0].TierCount != 2 {
+			t.Errorf("Expected tier count 2, got %d", users[0].TierCount)
+		}
+	})
+
+	t.Run("Filter by role", func(t *testing.T) {
+		users := search("?role=admin")
+		if len(users) != 1 || users[0].ID != admin.ID {
+			t.Errorf("Expected only the admin user, got %+v", users)
+		}
+	})
+
+	t.Run("Invalid role", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/users?role=owner", nil)
+		req = asRole(req, models.RoleAdmin)
+		w := httptest.NewRecorder()
+
+		GetAdminUsers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestCloneTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "other", Email: "other@example.com", GitHubID: "other_gh"}
+	db.Create(&other)
+
+	source := models.Tier{
+		UserID:       owner.ID,
+		Platform:     "Railway",
+		Name:         "Railway Free",
+		IsPublic:     true,
+		MemoryLimit:  "512MB",
+		UpvoteCount:  5,
+		CommentCount: 2,
+	}
+	db.Create(&source)
+
+	private := models.Tier{UserID: owner.ID, Platform: "Vercel", Name: "Vercel Free"}
+	db.Create(&private)
+	db.Model(&private).Update("is_public", false)
+
+	t.Run("Clone public tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/"+strconv.Itoa(int(source.ID))+"/clone", nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		CloneTier(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", w.Code)
+		}
+
+		var clone models.Tier
+		json.NewDecoder(w.Body).Decode(&clone)
+
+		if clone.ID == source.ID || clone.ID == 0 {
+			t.Errorf("Expected a new tier ID, got %d", clone.ID)
+		}
+		if clone.UpvoteCount != 0 || clone.DownvoteCount != 0 || clone.CommentCount != 0 {
+			t.Error("Expected clone counts to start at zero")
+		}
+		if clone.Name != "Railway Free (copy)" {
+			t.Errorf("Expected name 'Railway Free (copy)', got '%s'", clone.Name)
+		}
+		if clone.UserID != other.ID {
+			t.Errorf("Expected clone owned by caller, got user %d", clone.UserID)
+		}
+		if clone.Status != models.TierStatusDraft {
+			t.Errorf("Expected status 'draft', got '%s'", clone.Status)
+		}
+	})
+
+	t.Run("Private tier by non-owner", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/"+strconv.Itoa(int(private.ID))+"/clone", nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		CloneTier(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Pending tier by non-owner", func(t *testing.T) {
+		pending := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Render Pending", IsPublic: true, Status: models.TierStatusPending}
+		db.Create(&pending)
+
+		req := httptest.NewRequest(http.MethodPost, "/tiers/"+strconv.Itoa(int(pending.ID))+"/clone", nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		CloneTier(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Private tier by owner", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/"+strconv.Itoa(int(private.ID))+"/clone", nil)
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		CloneTier(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTierConditional(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "etaguser", Email: "etag@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Test Tier"}
+	db.Create(&tier)
+
+	path := "/tiers/" + strconv.Itoa(int(tier.ID))
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	GetTier(w, req)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header to be set")
+	}
+	if w.Header().Get("Last-Modified") == "" {
+		t.Error("Expected Last-Modified header to be set")
+	}
+
+	t.Run("Matching ETag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", etag)
+		w := httptest.NewRecorder()
+
+		GetTier(w, req)
+
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected status 304, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected empty body, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("Stale ETag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"0-0"`)
+		w := httptest.NewRecorder()
+
+		GetTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+		if w.Body.Len() == 0 {
+			t.Error("Expected full response body")
+		}
+	})
+
+	t.Run("Bookmark changes the ETag", func(t *testing.T) {
+		bookmark := httptest.NewRequest(http.MethodPost, path+"/bookmark", nil)
+		ToggleBookmark(httptest.NewRecorder(), asUser(bookmark, user.ID))
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", etag)
+		w := httptest.NewRecorder()
+
+		GetTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 after a bookmark, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTierVisibility(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "hidden", Email: "hidden@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "curious", Email: "curious@example.com"}
+	db.Create(&other)
+
+	private := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Private Tier"}
+	db.Create(&private)
+	db.Model(&private).Update("is_public", false)
+	pending := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Pending Tier", Status: models.TierStatusPending}
+	db.Create(&pending)
+
+	get := func(tier models.Tier, callerID uint) int {
+		req := httptest.NewRequest(http.MethodGet, "/tiers/"+strconv.Itoa(int(tier.ID)), nil)
+		if callerID != 0 {
+			req = asUser(req, callerID)
+		}
+		w := httptest.NewRecorder()
+		GetTier(w, req)
+		return w.Code
+	}
+
+	for _, tier := range []models.Tier{private, pending} {
+		t.Run(tier.Name, func(t *testing.T) {
+			cache.Tiers.Invalidate(tier.ID)
+			if code := get(tier, 0); code != http.StatusNotFound {
+				t.Errorf("Expected status 404 for an anonymous caller, got %d", code)
+			}
+			if code := get(tier, other.ID); code != http.StatusNotFound {
+				t.Errorf("Expected status 404 for another user, got %d", code)
+			}
+			if code := get(tier, owner.ID); code != http.StatusOK {
+				t.Errorf("Expected status 200 for the owner, got %d", code)
+			}
+			// The owner's request cached the tier; the check still applies
+			if code := get(tier, 0); code != http.StatusNotFound {
+				t.Errorf("Expected status 404 for a cached tier, got %d", code)
+			}
+		})
+	}
+}
+
+func TestGetTierViewCount(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "viewer", Email: "viewer@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Viewed Tier", ViewCount: 5}
+	db.Create(&tier)
+
+	path := "/tiers/" + strconv.Itoa(int(tier.ID))
+	for i := 0; i < 3; i++ {
+		GetTier(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if err := views.Tiers.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var updated models.Tier
+	db.First(&updated, tier.ID)
+	if updated.ViewCount != 8 {
+		t.Errorf("Expected view count 8, got %d", updated.ViewCount)
+	}
+	if views.Tiers.Pending(tier.ID) != 0 {
+		t.Errorf("Expected no pending views after flush, got %d", views.Tiers.Pending(tier.ID))
+	}
+}
+
+func TestGetTierRenderedDescription(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "mduser", Email: "md@example.com"}
+	db.Create(&user)
+
+	tier := models.Tier{
+		UserID:      user.ID,
+		Platform:    "Railway",
+		Name:        "Markdown Tier",
+		Description: "**Free** forever\n\n- 512MB RAM",
+		IsPublic:    true,
+		Status:      models.TierStatusApproved,
+	}
+	db.Create(&tier)
+
+	t.Run("Single tier is rendered", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers/"+strconv.Itoa(int(tier.ID)), nil)
+		w := httptest.NewRecorder()
+
+		GetTier(w, req)
+
+		var response map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		if response["description"] != tier.Description {
+			t.Errorf("Expected raw description %q, got %v", tier.Description, response["description"])
+		}
+		want := "<p><strong>Free</strong> forever</p>\n<ul>\n<li>512MB RAM</li>\n</ul>"
+		if response["rendered_description"] != want {
+			t.Errorf("Expected rendered description %q, got %v", want, response["rendered_description"])
+		}
+	})
+
+	t.Run("List omits rendered description", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tiers", nil)
+		w := httptest.NewRecorder()
+
+		GetTiers(w, req)
+
+		if strings.Contains(w.Body.String(), "rendered_description") {
+			t.Error("Expected list response without rendered_description")
+		}
+	})
+}
+
+func TestSanitizeTierFields(t *testing.T) {
+	t.Run("Strips tags", func(t *testing.T) {
+		tier := models.Tier{
+			Name:        "<b>Railway</b> Free",
+			Description: "Great for <em>hobby</em> projects",
+			Platform:    "Railway",
+			MemoryLimit: "512MB<br>",
+		}
+
+		if err := sanitizeTierFields(&tier); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tier.Name != "Railway Free" {
+			t.Errorf("Expected name 'Railway Free', got '%s'", tier.Name)
+		}
+		if tier.Description != "Great for hobby projects" {
+			t.Errorf("Unexpected description '%s'", tier.Description)
+		}
+		if tier.MemoryLimit != "512MB" {
+			t.Errorf("Expected memory limit '512MB', got '%s'", tier.MemoryLimit)
+		}
+	})
+
+	t.Run("Rejects mostly markup", func(t *testing.T) {
+		tier := models.Tier{Name: "<script>alert('xss')</script>", Platform: "Railway"}
+
+		if err := sanitizeTierFields(&tier); err == nil {
+			t.Error("Expected error for value that is mostly markup")
+		}
+	})
+}
+
+func TestCollections(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	owner := models.User{Username: "curator", Email: "curator@example.com"}
+	db.Create(&owner)
+	other := models.User{Username: "browser", Email: "browser@example.com", GitHubID: "browser_gh"}
+	db.Create(&other)
+
+	first := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Railway Free", IsPublic: true}
+	db.Create(&first)
+	second := models.Tier{UserID: other.ID, Platform: "Koyeb", Name: "Koyeb Free", IsPublic: true}
+	db.Create(&second)
+
+	body, _ := json.Marshal(models.Collection{Name: "Best PaaS for Node.js", IsPublic: true})
+	req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBuffer(body))
+	req = asUser(req, owner.ID)
+	w := httptest.NewRecorder()
+
+	CreateCollection(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+	var collection models.Collection
+	json.NewDecoder(w.Body).Decode(&collection)
+	collectionPath := "/collections/" + strconv.Itoa(int(collection.ID))
+
+	addTier := func(userID, tierID uint, position *int) int {
+		body, _ := json.Marshal(CollectionTierRequest{TierID: tierID, Position: position})
+		req := httptest.NewRequest(http.MethodPost, collectionPath+"/tiers", bytes.NewBuffer(body))
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		AddCollectionTier(w, req)
+		return w.Code
+	}
+
+	t.Run("Add tiers ordered by position", func(t *testing.T) {
+		top := 0
+		if code := addTier(owner.ID, second.ID, nil); code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", code)
+		}
+		if code := addTier(owner.ID, first.ID, &top); code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", code)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, collectionPath, nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		GetCollection(w, req)
+
+		var got models.Collection
+		json.NewDecoder(w.Body).Decode(&got)
+		if len(got.Tiers) != 2 || got.Tiers[0].ID != first.ID || got.Tiers[1].ID != second.ID {
+			t.Errorf("Expected tiers ordered by position, got %+v", got.Tiers)
+		}
+	})
+
+	t.Run("Duplicate tier rejected", func(t *testing.T) {
+		if code := addTier(owner.ID, first.ID, nil); code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", code)
+		}
+	})
+
+	t.Run("Non-owner cannot add tiers", func(t *testing.T) {
+		if code := addTier(other.ID, first.ID, nil); code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", code)
+		}
+	})
+
+	t.Run("Remove tier", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, collectionPath+"/tiers/"+strconv.Itoa(int(second.ID)), nil)
+		req = asUser(req, owner.ID)
+		w := httptest.NewRecorder()
+
+		RemoveCollectionTier(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var count int64
+		db.Model(&models.CollectionTier{}).Where("collection_id = ?", collection.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected 1 tier left in collection, got %d", count)
+		}
+	})
+
+	t.Run("Unapproved tiers hidden from others", func(t *testing.T) {
+		pending := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Render Pending", Status: models.TierStatusPending}
+		db.Create(&pending)
+		if code := addTier(owner.ID, pending.ID, nil); code != http.StatusCreated {
+			t.Fatalf("Expected the owner to add their own pending tier, got %d", code)
+		}
+
+		get := func(callerID uint) []models.Tier {
+			req := httptest.NewRequest(http.MethodGet, collectionPath, nil)
+			if callerID != 0 {
+				req = asUser(req, callerID)
+			}
+			w := httptest.NewRecorder()
+			GetCollection(w, req)
+
+			var got models.Collection
+			json.NewDecoder(w.Body).Decode(&got)
+			return got.Tiers
+		}
+
+		for name, callerID := range map[string]uint{"Anonymous": 0, "Other user": other.ID} {
+			if tiers := get(callerID); len(tiers) != 1 || tiers[0].ID != first.ID {
+				t.Errorf("%s: expected only the approved tier, got %+v", name, tiers)
+			}
+		}
+		if tiers := get(owner.ID); len(tiers) != 2 {
+			t.Errorf("Expected the owner to see their pending tier too, got %+v", tiers)
+		}
+	})
+
+	t.Run("Cannot add another user's unapproved tier", func(t *testing.T) {
+		rejected := models.Tier{UserID: other.ID, Platform: "Fly", Name: "Fly Rejected", Status: models.TierStatusRejected}
+		db.Create(&rejected)
+		if code := addTier(owner.ID, rejected.ID, nil); code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", code)
+		}
+	})
+
+	t.Run("Private collection hidden from others", func(t *testing.T) {
+		private := models.Collection{UserID: owner.ID, Name: "Drafts"}
+		db.Create(&private)
+		db.Model(&private).Update("is_public", false)
+
+		req := httptest.NewRequest(http.MethodGet, "/collections/"+strconv.Itoa(int(private.ID)), nil)
+		req = asUser(req, other.ID)
+		w := httptest.NewRecorder()
+
+		GetCollection(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestSitemap(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+	t.Setenv("APP_BASE_URL", "https://freetier.dev/")
+
+	user := models.User{Username: "mapper", Email: "mapper@example.com"}
+	db.Create(&user)
+
+	tiers := []models.Tier{
+		{UserID: user.ID, Platform: "Railway", Name: "Railway Free", IsPublic: true},
+		{UserID: user.ID, Platform: "Koyeb", Name: "Koyeb Free", IsPublic: true},
+		{UserID: user.ID, Platform: "Vercel", Name: "Vercel Free", IsPublic: true, Status: models.TierStatusPending},
+	}
+	for i := range tiers {
+		db.Create(&tiers[i])
+	}
+
+	t.Run("Single sitemap", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		w := httptest.NewRecorder()
+
+		GetSitemap(w, req)
+
+		if ct := w.Header().Get("Content-Type"); ct != "application/xml" {
+			t.Errorf("Expected Content-Type application/xml, got '%s'", ct)
+		}
+
+		var urlSet sitemapURLSet
+		if err := xml.NewDecoder(w.Body).Decode(&urlSet); err != nil {
+			t.Fatalf("Failed to decode sitemap: %v", err)
+		}
+		if len(urlSet.URLs) != 2 {
+			t.Fatalf("Expected 2 approved tiers in sitemap, got %d", len(urlSet.URLs))
+		}
+		want := "https://freetier.dev/tiers/" + strconv.Itoa(int(tiers[0].ID))
+		if urlSet.URLs[0].Loc != want {
+			t.Errorf("Expected loc '%s', got '%s'", want, urlSet.URLs[0].Loc)
+		}
+	})
+
+	t.Run("Sitemap index above page size", func(t *testing.T) {
+		defer func(size int) { sitemapPageSize = size }(sitemapPageSize)
+		sitemapPageSize = 1
+
+		req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		w := httptest.NewRecorder()
+
+		GetSitemap(w, req)
+
+		var index sitemapIndex
+		if err := xml.NewDecoder(w.Body).Decode(&index); err != nil {
+			t.Fatalf("Failed to decode sitemap index: %v", err)
+		}
+		if len(index.Sitemaps) != 2 || index.Sitemaps[1].Loc != "https://freetier.dev/sitemap-2.xml" {
+			t.Errorf("Expected 2 sitemap pages, got %+v", index.Sitemaps)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/sitemap-2.xml", nil)
+		w = httptest.NewRecorder()
+
+		GetSitemapPage(w, req)
+
+		var urlSet sitemapURLSet
+		xml.NewDecoder(w.Body).Decode(&urlSet)
+		if len(urlSet.URLs) != 1 || !strings.HasSuffix(urlSet.URLs[0].Loc, "/tiers/"+strconv.Itoa(int(tiers[1].ID))) {
+			t.Errorf("Expected second tier on page 2, got %+v", urlSet.URLs)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/sitemap-3.xml", nil)
+		w = httptest.NewRecorder()
+
+		GetSitemapPage(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 past the last page, got %d", w.Code)
+		}
+	})
+}
+
+func TestMergePlatformSuggestions(t *testing.T) {
+	got := mergePlatformSuggestions("rail",
+		[]string{"Railway", "Railgun"},
+		[]string{"Render", "Railway", "railway"},
+	)
+	want := []string{"Railgun", "Railway"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	var many []string
+	for i := 0; i < 15; i++ {
+		many = append(many, "Host"+strconv.Itoa(i))
+	}
+	if got := mergePlatformSuggestions("host", many); len(got) != autocompleteLimit {
+		t.Errorf("Expected %d suggestions, got %d", autocompleteLimit, len(got))
+	}
+}
+
+func TestGetPlatformAutocomplete(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+	autocompleteCache = newTTLCache(5 * time.Minute)
+
+	user := models.User{Username: "typist", Email: "typist@example.com"}
+	db.Create(&user)
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railgun", Name: "Railgun Free", IsPublic: true})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railroad", Name: "Railroad Free", IsPublic: true, Status: models.TierStatusPending})
+
+	req := httptest.NewRequest(http.MethodGet, "/platforms/autocomplete?q=RAIL", nil)
+	w := httptest.NewRecorder()
+
+	GetPlatformAutocomplete(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response AutocompleteResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	want := []string{"Railgun", "Railway"}
+	if strings.Join(response.Suggestions, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, response.Suggestions)
+	}
+}
+
+func TestGraphQLTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "grapher", Email: "grapher@example.com"}
+	db.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Vercel", Name: "Hobby", IsPublic: true, UpvoteCount: 3, DownvoteCount: 1}
+	db.Create(&tier)
+	hidden := models.Tier{UserID: user.ID, Platform: "Vercel", Name: "Hidden", IsPublic: true, Status: models.TierStatusPending}
+	db.Create(&hidden)
+	db.Create(&models.Comment{UserID: user.ID, TierID: tier.ID, Content: "Works well"})
+
+	query := `query($id: ID!, $hidden: ID!) {
+		tier(id: $id) { name voteCount user { username } comments { content } }
+		other: tier(id: $hidden) { name }
+	}`
+	body, _ := json.Marshal(map[string]interface{}{
+		"query": query,
+		"variables": map[string]interface{}{
+			"id":     strconv.FormatUint(uint64(tier.ID), 10),
+			"hidden": strconv.FormatUint(uint64(hidden.ID), 10),
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	GraphQL(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data struct {
+			Tier *struct {
+				Name      string
+				VoteCount int
+				User      struct{ Username string }
+				Comments  []struct{ Content string }
+			}
+			Other *struct{ Name string }
+		}
+		Errors []interface{}
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+
+	if len(response.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", response.Errors)
+	}
+	if response.Data.Tier == nil || response.Data.Tier.Name != "Hobby" {
+		t.Fatalf("Expected tier Hobby, got %+v", response.Data.Tier)
+	}
+	if response.Data.Tier.VoteCount != 4 {
+		t.Errorf("Expected voteCount 4, got %d", response.Data.Tier.VoteCount)
+	}
+	if response.Data.Tier.User.Username != "grapher" {
+		t.Errorf("Expected user grapher, got %q", response.Data.Tier.User.Username)
+	}
+	if len(response.Data.Tier.Comments) != 1 {
+		t.Errorf("Expected 1 comment, got %d", len(response.Data.Tier.Comments))
+	}
+	if response.Data.Other != nil {
+		t.Errorf("Expected pending tier to be hidden, got %+v", response.Data.Other)
+	}
+}
+
+func TestVoteBroadcasterPublish(t *testing.T) {
+	b := &VoteBroadcaster{}
+	events, unsubscribe := b.Subscribe(1)
+	other, unsubscribeOther := b.Subscribe(2)
+	defer unsubscribeOther()
+
+	b.Publish(1, VoteEvent{UpvoteCount: 3, DownvoteCount: 1})
+
+	select {
+	case event := <-events:
+		if event.UpvoteCount != 3 || event.DownvoteCount != 1 {
+			t.Errorf("Expected 3/1, got %d/%d", event.UpvoteCount, event.DownvoteCount)
+		}
+	default:
+		t.Fatal("Expected an event for tier 1")
+	}
+
+	select {
+	case event := <-other:
+		t.Errorf("Expected no event for tier 2, got %+v", event)
+	default:
+	}
+
+	unsubscribe()
+	b.Publish(1, VoteEvent{UpvoteCount: 4})
+	select {
+	case event := <-events:
+		t.Errorf("Expected no event after unsubscribe, got %+v", event)
+	default:
+	}
+}
+
+func TestStreamTierVotes(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "streamer", Email: "streamer@example.com"}
+	db.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Fly.io", Name: "Fly Free", IsPublic: true}
+	db.Create(&tier)
+	pending := models.Tier{UserID: user.ID, Platform: "Fly.io", Name: "Fly Pending", IsPublic: true, Status: models.TierStatusPending}
+	db.Create(&pending)
+
+	server := httptest.NewServer(http.HandlerFunc(StreamTierVotes))
+	defer server.Close()
+
+	t.Run("Hidden tier", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/ws/tiers/" + strconv.Itoa(int(pending.ID)) + "/votes")
+		if err != nil {
+			t.Fatalf("Failed to request stream: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Missing tier", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/ws/tiers/99999/votes")
+		if err != nil {
+			t.Fatalf("Failed to request stream: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Receives vote events", func(t *testing.T) {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/tiers/" + strconv.Itoa(int(tier.ID)) + "/votes"
+		ws, err := websocket.Dial(url, "", server.URL)
+		if err != nil {
+			t.Fatalf("Failed to dial: %v", err)
+		}
+		defer ws.Close()
+
+		// Wait for the server side to subscribe before publishing
+		deadline := time.Now().Add(2 * time.Second)
+		for !hasVoteSubscriber(tier.ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Server never subscribed to tier %d", tier.ID)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		Votes.Publish(tier.ID, VoteEvent{UpvoteCount: 5, DownvoteCount: 2})
+
+		ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var event VoteEvent
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			t.Fatalf("Failed to receive event: %v", err)
+		}
+		if event.UpvoteCount != 5 || event.DownvoteCount != 2 {
+			t.Errorf("Expected 5/2, got %d/%d", event.UpvoteCount, event.DownvoteCount)
+		}
+
+		// Disconnecting must remove the subscription
+		ws.Close()
+		deadline = time.Now().Add(2 * time.Second)
+		for hasVoteSubscriber(tier.ID) {
+			if time.Now().After(deadline) {
+				t.Fatal("Subscription was not cleaned up after disconnect")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// hasVoteSubscriber reports whether any connection is subscribed to tierID
+func hasVoteSubscriber(tierID uint) bool {
+	found := false
+	Votes.subscribers.Range(func(_, value interface{}) bool {
+		found = value.(uint) == tierID
+		return !found
+	})
+	return found
+}
+
+func TestGetPlatforms(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "lister", Email: "lister@example.com"}
+	db.Create(&user)
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Starter", IsPublic: true})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railgun", Name: "Railgun Free", IsPublic: true})
+	db.Create(&models.Platform{Name: "Render", URL: "https://render.example"})
+
+	req := httptest.NewRequest(http.MethodGet, "/platforms", nil)
+	w := httptest.NewRecorder()
+
+	GetPlatforms(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var listings []PlatformListing
+	json.NewDecoder(w.Body).Decode(&listings)
+
+	byName := make(map[string]PlatformListing)
+	for _, listing := range listings {
+		byName[listing.Name] = listing
+	}
+
+	if listing, ok := byName["Koyeb"]; !ok || listing.TierCount != 0 || listing.URL == "" {
+		t.Errorf("Expected known platform Koyeb without tiers, got %+v", listing)
+	}
+	if byName["Railway"].TierCount != 1 {
+		t.Errorf("Expected Railway to have 1 tier, got %d", byName["Railway"].TierCount)
+	}
+	if byName["Railgun"].TierCount != 1 {
+		t.Errorf("Expected tier-only platform Railgun to be listed, got %+v", byName["Railgun"])
+	}
+	if byName["Render"].URL != "https://render.example" {
+		t.Errorf("Expected database URL for Render, got %q", byName["Render"].URL)
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "categorizer", Email: "categorizer@example.com"}
+	db.Create(&user)
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Neon", Name: "Neon Free", IsPublic: true, Category: models.CategoryDatabase})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Supabase", Name: "Supabase Free", IsPublic: true, Category: models.CategoryDatabase})
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Upstash", Name: "Upstash Hidden", IsPublic: false, Category: models.CategoryCache})
+
+	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	w := httptest.NewRecorder()
+
+	GetCategories(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var listings []CategoryListing
+	json.NewDecoder(w.Body).Decode(&listings)
+
+	if len(listings) != len(models.TierCategories) {
+		t.Fatalf("Expected %d categories, got %d", len(models.TierCategories), len(listings))
+	}
+
+	byName := make(map[string]int64)
+	for _, listing := range listings {
+		byName[listing.Name] = listing.TierCount
+	}
+	if byName["database"] != 2 {
+		t.Errorf("Expected 2 database tiers, got %d", byName["database"])
+	}
+	if byName["cache"] != 0 {
+		t.Errorf("Expected private cache tier not to be counted, got %d", byName["cache"])
+	}
+}
+
+func TestSubmitTierURL(t *testing.T) {
+	page := `<html><head>
+		<title>Ignored</title>
+		<meta property="og:title" content="Pricing | Railway">
+		<meta property="og:description" content="Deploy <b>anything</b> for free">
+		<meta property="og:image" content="https://railway.app/og.png">
+	</head><body></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	submit := func(userID uint, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/submit-url", strings.NewReader(body))
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		SubmitTierURL(w, req)
+		return w
+	}
+
+	t.Run("Internal addresses are refused", func(t *testing.T) {
+		scrapeLimiter = newTTLCache(time.Minute)
+		w := submit(1, `{"url": "`+srv.URL+`"}`)
+		if w.Code != http.StatusBadGateway {
+			t.Errorf("Expected status 502 for a loopback URL, got %d", w.Code)
+		}
+	})
+
+	// The test server is on loopback, so use a client without the guard
+	defaultClient := scrapeClient
+	scrapeClient = srv.Client()
+	defer func() { scrapeClient = defaultClient }()
+
+	t.Run("Pre-fills tier", func(t *testing.T) {
+		scrapeLimiter = newTTLCache(time.Minute)
+		w := submit(1, `{"url": "`+srv.URL+`/pricing"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var tier models.Tier
+		json.NewDecoder(w.Body).Decode(&tier)
+		if tier.Platform != "Railway" || tier.Name != "Railway Free Tier" {
+			t.Errorf("Expected platform Railway from og:title, got %q / %q", tier.Platform, tier.Name)
+		}
+		if tier.Description != "Deploy anything for free" {
+			t.Errorf("Expected description without markup, got %q", tier.Description)
+		}
+		if tier.ScreenshotURL != "https://railway.app/og.png" || tier.URL != srv.URL+"/pricing" {
+			t.Errorf("Expected screenshot and page URL, got %q and %q", tier.ScreenshotURL, tier.URL)
+		}
+		if tier.ID != 0 {
+			t.Errorf("Expected an unsaved tier, got ID %d", tier.ID)
+		}
+	})
+
+	t.Run("One URL per user per minute", func(t *testing.T) {
+		scrapeLimiter = newTTLCache(time.Minute)
+		submit(2, `{"url": "`+srv.URL+`"}`)
+
+		w := submit(2, `{"url": "`+srv.URL+`"}`)
+		if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+			t.Errorf("Expected status 429 with Retry-After, got %d", w.Code)
+		}
+
+		if w := submit(3, `{"url": "`+srv.URL+`"}`); w.Code != http.StatusOK {
+			t.Errorf("Expected another user to be allowed, got %d", w.Code)
+		}
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		scrapeLimiter = newTTLCache(time.Minute)
+		if w := submit(1, `{"url": "file:///etc/passwd"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Authentication required", func(t *testing.T) {
+		if w := submit(0, `{"url": "`+srv.URL+`"}`); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestPlatformFromPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     pageMeta
+		rawURL   string
+		expected string
+	}{
+		{"Site name", pageMeta{siteName: "Koyeb", title: "Pricing - Something"}, "https://www.koyeb.com/pricing", "Koyeb"},
+		{"Title suffix", pageMeta{title: "Plans & Pricing | Render"}, "https://render.com/pricing", "Render"},
+		{"Domain", pageMeta{}, "https://docs.railway.app/reference/pricing", "Railway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.rawURL)
+			if got := platformFromPage(tt.meta, u); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidEmoji(t *testing.T) {
+	tests := []struct {
+		emoji string
+		want  bool
+	}{
+		{"👍", true},
+		{"👍🏽", true},
+		{"❤️", true},
+		{"🇮🇩", true},
+		{"👩‍💻", true},
+		{"", false},
+		{"+1", false},
+		{"ok", false},
+		{"👍 ", false},
+		{"é", false},
+		{"👍👍👍👍👍👍👍👍👍👍👍", false},
+	}
+
+	for _, tt := range tests {
+		if got := validEmoji(tt.emoji); got != tt.want {
+			t.Errorf("validEmoji(%q) = %v, want %v", tt.emoji, got, tt.want)
+		}
+	}
+}
+
+func TestCommentReactions(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	author := models.User{Username: "reactauthor", Email: "reactauthor@example.com"}
+	db.Create(&author)
+	reader := models.User{Username: "reactreader", Email: "reactreader@example.com"}
+	db.Create(&reader)
+
+	tier := models.Tier{UserID: author.ID, Platform: "Railway", Name: "Reacted Tier"}
+	db.Create(&tier)
+	comment := models.Comment{UserID: author.ID, TierID: tier.ID, Content: "Great tier"}
+	db.Create(&comment)
+
+	path := "/comments/" + strconv.Itoa(int(comment.ID)) + "/reactions"
+	toggle := func(userID uint, emoji string) (*httptest.ResponseRecorder, []ReactionSummary) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"emoji": "`+emoji+`"}`))
+		req = asUser(req, userID)
+		w := httptest.NewRecorder()
+		ToggleCommentReaction(w, req)
+
+		var reactions []ReactionSummary
+		json.NewDecoder(w.Body).Decode(&reactions)
+		return w, reactions
+	}
+
+	t.Run("Add reactions", func(t *testing.T) {
+		if w, _ := toggle(author.ID, "👍"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", w.Code)
+		}
+		toggle(author.ID, "🎉")
+		w, reactions := toggle(reader.ID, "👍")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", w.Code)
+		}
+		if len(reactions) != 2 || reactions[0].Emoji != "👍" || reactions[0].Count != 2 || !reactions[0].UserReacted {
+			t.Errorf("Expected 👍 first with 2 reactions including the caller's, got %+v", reactions)
+		}
+		if reactions[1].Emoji != "🎉" || reactions[1].UserReacted {
+			t.Errorf("Expected 🎉 not reacted by the caller, got %+v", reactions[1])
+		}
+	})
+
+	t.Run("Remove reaction", func(t *testing.T) {
+		w, reactions := toggle(author.ID, "🎉")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if len(reactions) != 1 || reactions[0].Emoji != "👍" {
+			t.Errorf("Expected only 👍 left, got %+v", reactions)
+		}
+
+		var stored models.Comment
+		db.First(&stored, comment.ID)
+		if len(stored.ReactionsSummary) != 1 || stored.ReactionsSummary["👍"] != 2 {
+			t.Errorf("Expected summary {👍: 2}, got %v", stored.ReactionsSummary)
+		}
+	})
+
+	t.Run("Anonymous read", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		GetCommentReactions(w, req)
+
+		var reactions []ReactionSummary
+		json.NewDecoder(w.Body).Decode(&reactions)
+		if w.Code != http.StatusOK || len(reactions) != 1 || reactions[0].Count != 2 || reactions[0].UserReacted {
+			t.Errorf("Expected 👍 x2 not reacted, got %d %+v", w.Code, reactions)
+		}
+	})
+
+	t.Run("Invalid emoji", func(t *testing.T) {
+		if w, _ := toggle(reader.ID, "like"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown comment", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/comments/999999/reactions", strings.NewReader(`{"emoji": "👍"}`))
+		req = asUser(req, reader.ID)
+		w := httptest.NewRecorder()
+		ToggleCommentReaction(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestVisitTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "visitor", Email: "visitor@example.com"}
+	db.Create(&user)
+
+	linked := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Linked Tier", URL: "https://railway.app/pricing"}
+	db.Create(&linked)
+	unlinked := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Unlinked Tier"}
+	db.Create(&unlinked)
+
+	visit := func(id uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tiers/"+strconv.Itoa(int(id))+"/visit", nil)
+		req.Header.Set("Referer", "https://example.com/list")
+		w := httptest.NewRecorder()
+		VisitTier(w, req)
+		return w
+	}
+
+	t.Run("Redirects to the tier URL", func(t *testing.T) {
+		before := views.Clicks.Pending(linked.ID)
+		w := visit(linked.ID)
+		if w.Code != http.StatusFound {
+			t.Fatalf("Expected status 302, got %d", w.Code)
+		}
+		if location := w.Header().Get("Location"); location != linked.URL {
+			t.Errorf("Expected redirect to %s, got %s", linked.URL, location)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for views.Clicks.Pending(linked.ID) == before && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if views.Clicks.Pending(linked.ID) != before+1 {
+			t.Errorf("Expected the click to be counted")
+		}
+	})
+
+	t.Run("No URL", func(t *testing.T) {
+		if w := visit(unlinked.ID); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown tier", func(t *testing.T) {
+		if w := visit(999999); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unapproved tier", func(t *testing.T) {
+		for _, status := range []string{models.TierStatusPending, models.TierStatusRejected} {
+			tier := models.Tier{UserID: user.ID, Platform: "Phish", Name: "Tier " + status, URL: "https://evil.example.com", Status: status}
+			db.Create(&tier)
+
+			if w := visit(tier.ID); w.Code != http.StatusNotFound {
+				t.Errorf("Expected status 404 for a %s tier, got %d", status, w.Code)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/tiers/"+strconv.Itoa(int(tier.ID))+"/visit", nil)
+			w := httptest.NewRecorder()
+			VisitTier(w, asUser(req, user.ID))
+			if w.Code != http.StatusFound {
+				t.Errorf("Expected the owner to be redirected from a %s tier, got %d", status, w.Code)
+			}
+		}
+	})
+}
+
+func TestCreateTiersBatch(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "batcher", Email: "batcher@example.com"}
+	db.Create(&user)
+
+	post := func(body string) (*httptest.ResponseRecorder, BatchCreateResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/tiers/batch", strings.NewReader(body))
+		req = asUser(req, user.ID)
+		w := httptest.NewRecorder()
+		CreateTiersBatch(w, req)
+
+		var response BatchCreateResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response
+	}
+
+	t.Run("Partial success", func(t *testing.T) {
+		w, response := post(`[
+			{"platform": "railway", "name": "Batch One"},
+			{"platform": "Render", "name": "Batch Two", "upvote_count": 99},
+			{"name": "No Platform"},
+			"not a tier",
+			{"platform": "Fly", "name": "Bad URL", "url": "ftp://fly.io"}
+		]`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", w.Code)
+		}
+		if len(response.Created) != 2 {
+			t.Fatalf("Expected 2 created tiers, got %v", response.Created)
+		}
+		if len(response.Failed) != 3 || response.Failed[0].Index != 2 || response.Failed[1].Index != 3 || response.Failed[2].Index != 4 {
+			t.Errorf("Expected failures at indexes 2, 3 and 4, got %+v", response.Failed)
+		}
+
+		var created models.Tier
+		db.First(&created, response.Created[1])
+		if created.UserID != user.ID || created.UpvoteCount != 0 || created.Status != models.TierStatusPending {
+			t.Errorf("Expected a pending tier owned by the caller without votes, got %+v", created)
+		}
+	})
+
+	t.Run("All invalid", func(t *testing.T) {
+		w, response := post(`[{"name": "No Platform"}]`)
+		if w.Code != http.StatusBadRequest || len(response.Created) != 0 || len(response.Failed) != 1 {
+			t.Errorf("Expected 400 with one failure, got %d %+v", w.Code, response)
+		}
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		w, response := post(`[
+			{"platform": "Railway", "name": "Batch One"},
+			{"platform": "Koyeb", "name": "Batch Three"},
+			{"platform": "Koyeb", "name": "Batch Three"}
+		]`)
+		if w.Code != http.StatusCreated || len(response.Created) != 1 {
+			t.Fatalf("Expected only the new tier created, got %d %+v", w.Code, response)
+		}
+		if len(response.Failed) != 2 || response.Failed[0].Index != 0 || response.Failed[1].Index != 2 {
+			t.Errorf("Expected duplicates at indexes 0 and 2, got %+v", response.Failed)
+		}
+	})
+
+	t.Run("Too many tiers", func(t *testing.T) {
+		items := make([]string, maxBatchSize+1)
+		for i := range items {
+			items[i] = `{"platform": "Railway", "name": "Railway Tier"}`
+		}
+		if w, _ := post("[" + strings.Join(items, ",") + "]"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetTiersFeaturedFirst(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "sponsor", Email: "sponsor@example.com"}
+	db.Create(&user)
+
+	until := time.Now().Add(24 * time.Hour)
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Railway", Name: "Popular Tier", IsPublic: true,
+		Status: models.TierStatusApproved, UpvoteCount: 50, ViewCount: 500})
+	featured := models.Tier{UserID: user.ID, Platform: "Zeabur", Name: "Featured Tier", IsPublic: true,
+		Status: models.TierStatusApproved, IsFeatured: true, FeaturedUntil: &until}
+	db.Create(&featured)
+	db.Create(&models.Tier{UserID: user.ID, Platform: "Render", Name: "Another Tier", IsPublic: true,
+		Status: models.TierStatusApproved, UpvoteCount: 10, ViewCount: 100})
+
+	for _, sort := range []string{"", "recent", "updated_recent", "popular", "controversial", "name", "-upvotes", "upvotes,-created_at"} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tiers?sort="+sort, nil)
+			w := httptest.NewRecorder()
+			GetTiers(w, req)
+
+			var response struct {
+				Data []models.Tier `json:"data"`
+			}
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Data) != 3 || response.Data[0].ID != featured.ID {
+				t.Errorf("Expected the featured tier first, got %+v", response.Data)
+			}
+		})
+	}
+}
+
+func TestFeatureTier(t *testing.T) {
+	db := setupTestDB(t)
+	database.DB = db
+
+	user := models.User{Username: "featured", Email: "featured@example.com"}
+	db.Create(&user)
+	tier := models.Tier{UserID: user.ID, Platform: "Railway", Name: "Partner Tier"}
+	db.Create(&tier)
+
+	feature := func(role, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/tiers/"+strconv.Itoa(int(tier.ID))+"/feature", strings.NewReader(body))
+		req = asRole(req, role)
+		w := httptest.NewRecorder()
+		FeatureTier(w, req)
+		return w
+	}
+
+	if w := feature(models.RoleUser, `{"duration_days": 30}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for non-admins, got %d", w.Code)
+	}
+	if w := feature(models.RoleAdmin, `{"duration_days": 0}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a zero duration, got %d", w.Code)
+	}
+	if w := feature(models.RoleAdmin, `{"duration_days": 30}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var updated models.Tier
+	db.First(&updated, tier.ID)
+	if !updated.IsFeatured || updated.FeaturedUntil == nil || time.Until(*updated.FeaturedUntil) < 29*24*time.Hour {
+		t.Errorf("Expected the tier featured for 30 days, got %v until %v", updated.IsFeatured, updated.FeaturedUntil)
+	}
+}
+
+func TestNotificationThrottle(t *testing.T) {
+	throttle := NewNotificationThrottle(time.Hour)
+
+	if !throttle.Allow(1) {
+		t.Error("Expected the first notification for tier 1 to be allowed")
+	}
+	if throttle.Allow(1) {
+		t.Error("Expected a second notification for tier 1 within the hour to be throttled")
+	}
+	if !throttle.Allow(2) {
+		t.Error("Expected tier 2 to be throttled separately")
+	}
+
+	throttle.last[1] = time.Now().Add(-time.Hour - time.Second)
+	if !throttle.Allow(1) {
+		t.Error("Expected tier 1 to be allowed again after an hour")
+	}
+}
+
+func TestTierResponseHidesPrivateUserFields(t *testing.T) {
+	tier := models.Tier{ID: 3, UserID: 7, Platform: "Railway", Name: "Railway Free", User: models.User{
+		ID: 7, Username: "alice", Email: "alice@example.com", Role: models.RoleAdmin,
+		AvatarURL: "https://example.com/a.png", Bio: "Builds things", GitHubID: "123",
+	}}
+
+	encoded, err := json.Marshal(newTierResponse(&tier))
+	if err != nil {
+		t.Fatalf("Failed to encode tier response: %v", err)
+	}
+
+	var decoded struct {
+		ID   uint                   `json:"id"`
+		Name string                 `json:"name"`
+		User map[string]interface{} `json:"user"`
+	}
+	json.Unmarshal(encoded, &decoded)
+	if decoded.ID != 3 || decoded.Name != "Railway Free" {
+		t.Errorf("Expected the tier fields, got %s", encoded)
+	}
+	want := map[string]interface{}{"id": float64(7), "username": "alice", "avatar_url": "https://example.com/a.png", "bio": "Builds things"}
+	if len(decoded.User) != len(want) {
+		t.Errorf("Expected user %v, got %v", want, decoded.User)
+	}
+	for key, value := range want {
+		if decoded.User[key] != value {
+			t.Errorf("Expected user %s %v, got %v", key, value, decoded.User[key])
+		}
+	}
+
+	// Tiers loaded without their author or whose author was erased have no user
+	encoded, _ = json.Marshal(newTierResponse(&models.Tier{ID: 4, Name: "Orphan"}))
+	if strings.Contains(string(encoded), `"user"`) {
+		t.Errorf("Expected no user for an unloaded author, got %s", encoded)
+	}
 }
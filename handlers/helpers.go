@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"freestealer/auth"
+	"freestealer/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+)
+
+// currentUserID returns the authenticated user's ID set by the auth middleware
+func currentUserID(r *http.Request) (uint, bool) {
+	return auth.UserIDFromContext(r.Context())
+}
+
+// isAdmin reports whether the authenticated user has the admin role
+func isAdmin(r *http.Request) bool {
+	return auth.UserRoleFromContext(r.Context()) == models.RoleAdmin
+}
+
+// canModerate reports whether the authenticated user may review tier
+// submissions, as an admin or moderator
+func canModerate(r *http.Request) bool {
+	role := auth.UserRoleFromContext(r.Context())
+	return role == models.RoleAdmin || role == models.RoleModerator
+}
+
+// canModify reports whether the authenticated user owns the resource or is an admin
+func canModify(r *http.Request, ownerID uint) bool {
+	if isAdmin(r) {
+		return true
+	}
+	userID, ok := currentUserID(r)
+	return ok && userID == ownerID
+}
+
+// duplicateTierMessage is returned when a user submits a second live tier
+// with the same platform and name
+const duplicateTierMessage = "You already have a tier with this name for this platform"
+
+// isUniqueViolation reports whether err is a PostgreSQL unique violation of
+// the named index or constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+// writeError sends a JSON error response with one of the ErrCode constants
+// and a message for people
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"error":   code,
+		"message": message,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode error response")
+	}
+}
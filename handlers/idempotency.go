@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// idempotencyCache holds the responses of requests sent with an
+// Idempotency-Key, keyed by user and key, for 24 hours
+var idempotencyCache = newTTLCache(24 * time.Hour)
+
+// idempotencyPending marks a key whose first request is still being handled
+type idempotencyPending struct{}
+
+var errInvalidIdempotencyKey = errors.New("Idempotency-Key must be at most 255 characters")
+
+// idempotencyCacheKey returns the cache key for the request's Idempotency-Key
+// header, scoped to the caller so users cannot replay each other's responses.
+// It returns "" when the header is absent.
+func idempotencyCacheKey(r *http.Request) (string, error) {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		return "", nil
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return "", errInvalidIdempotencyKey
+	}
+	userID, _ := currentUserID(r)
+	return strconv.FormatUint(uint64(userID), 10) + ":" + key, nil
+}
+
+// replayIdempotent answers a retried request from the cached response of the
+// first one, or with 409 while the first one is still in progress
+func replayIdempotent(w http.ResponseWriter, cacheKey string) {
+	cached, ok := idempotencyCache.Get(cacheKey)
+	body, done := cached.([]byte)
+	if !ok || !done {
+		http.Error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Idempotent-Replayed", "true")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.WithError(err).Error("Failed to write replayed response")
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LeaderboardEntry represents a contributor's rank on the leaderboard
+type LeaderboardEntry struct {
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	AvatarURL    string `json:"avatar_url,omitempty"`
+	TotalUpvotes int64  `json:"total_upvotes"`
+}
+
+// leaderboardCache holds computed leaderboards keyed by period
+var leaderboardCache = newTTLCache(time.Hour)
+
+// leaderboardLimit is the maximum number of contributors returned
+const leaderboardLimit = 50
+
+// leaderboardSince returns the earliest tier creation time counted for a period
+func leaderboardSince(period string, now time.Time) (time.Time, bool) {
+	switch period {
+	case "", "all-time":
+		return time.Time{}, true
+	case "month":
+		return now.AddDate(0, -1, 0), true
+	case "week":
+		return now.AddDate(0, 0, -7), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// GetLeaderboard handles GET /leaderboard - top contributors by upvotes received
+// @Summary Get contributor leaderboard
+// @Description Get up to 50 users ranked by upvotes received on their public approved tiers
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Param period query string false "Time period: all-time (default), month or week"
+// @Success 200 {array} LeaderboardEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leaderboard [get]
+func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	since, ok := leaderboardSince(period, time.Now())
+	if !ok {
+		http.Error(w, "Period must be all-time, month or week", http.StatusBadRequest)
+		return
+	}
+	if period == "" {
+		period = "all-time"
+	}
+
+	if cached, ok := leaderboardCache.Get(period); ok {
+		writeLeaderboard(w, cached.([]LeaderboardEntry))
+		return
+	}
+
+	query := database.DB.Table("users u").
+		Select("u.id AS user_id, u.username, u.avatar_url, SUM(t.upvote_count) AS total_upvotes").
+		Joins("JOIN tiers t ON t.user_id = u.id").
+		Where("t.is_public = ? AND t.status = ?", true, models.TierStatusApproved).
+		Where("t.deleted_at IS NULL AND u.deleted_at IS NULL")
+
+	if !since.IsZero() {
+		query = query.Where("t.created_at >= ?", since)
+	}
+
+	entries := []LeaderboardEntry{}
+	if err := query.Group("u.id").Order("total_upvotes DESC").Limit(leaderboardLimit).Scan(&entries).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch leaderboard")
+		http.Error(w, "Failed to fetch leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	leaderboardCache.Set(period, entries)
+	log.WithFields(log.Fields{
+		"period": period,
+		"count":  len(entries),
+	}).Info("Leaderboard computed")
+
+	writeLeaderboard(w, entries)
+}
+
+// writeLeaderboard encodes leaderboard entries as the JSON response
+func writeLeaderboard(w http.ResponseWriter, entries []LeaderboardEntry) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		log.WithError(err).Error("Failed to encode leaderboard response")
+	}
+}
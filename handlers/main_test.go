@@ -0,0 +1,111 @@
+//go:build testcontainers
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"freestealer/database"
+	"freestealer/seed"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// containerStartTimeout bounds how long the PostgreSQL container may take to
+// accept connections
+const containerStartTimeout = 30 * time.Second
+
+// TestMain runs the package's tests against a throwaway PostgreSQL 15
+// container instead of a server configured through TEST_DB_*. The container
+// is first brought up the way a deployment is, with the numbered migrations
+// and the platform seed, so a migration that fails on PostgreSQL 15 stops the
+// run. One container is shared by all tests; setupTestDB still recreates the
+// schema for each test so IDs and counts start from scratch. Requires Docker,
+// and runs in CI as the integration job:
+//
+//	go test -tags testcontainers ./handlers
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	container, err := tcpostgres.Run(ctx, "postgres:15-alpine",
+		tcpostgres.WithDatabase("freestealer_test"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(containerStartTimeout)),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PostgreSQL container did not start within %s: %v\n", containerStartTimeout, err)
+		os.Exit(1)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get PostgreSQL container host: %v\n", err)
+		_ = container.Terminate(ctx)
+		os.Exit(1)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get PostgreSQL container port: %v\n", err)
+		_ = container.Terminate(ctx)
+		os.Exit(1)
+	}
+
+	if err := prepareDatabase(ctx, container); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to prepare PostgreSQL container: %v\n", err)
+		_ = container.Terminate(ctx)
+		os.Exit(1)
+	}
+
+	// setupTestDB connects using these
+	os.Setenv("TEST_DB_HOST", host)
+	os.Setenv("TEST_DB_PORT", port.Port())
+	os.Setenv("TEST_DB_USER", "postgres")
+	os.Setenv("TEST_DB_PASSWORD", "postgres")
+	os.Setenv("TEST_DB_NAME", "freestealer_test")
+
+	code := m.Run()
+
+	if err := container.Terminate(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to terminate PostgreSQL container: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// prepareDatabase applies the migrations in database/migrations to the
+// container and seeds the known platforms
+func prepareDatabase(ctx context.Context, container *tcpostgres.PostgresContainer) error {
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return err
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	if _, err := seed.SeedPlatforms(db); err != nil {
+		return fmt.Errorf("seeding platforms: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MarkReadRequest represents a request to mark notifications as read
+type MarkReadRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+// NotificationThrottle remembers when each tier's owner was last notified so
+// bursts of activity on one tier produce a single notification
+type NotificationThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[uint]time.Time // tier ID -> last notified at
+}
+
+// NewNotificationThrottle creates a throttle allowing one notification per tier per interval
+func NewNotificationThrottle(interval time.Duration) *NotificationThrottle {
+	return &NotificationThrottle{interval: interval, last: make(map[uint]time.Time)}
+}
+
+// Allow reports whether a notification for tierID may be sent now and, if so,
+// records it as sent
+func (t *NotificationThrottle) Allow(tierID uint) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := t.last[tierID]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	t.last[tierID] = now
+	return true
+}
+
+// upvoteThrottle limits upvote notifications to one per tier per hour
+var upvoteThrottle = NewNotificationThrottle(time.Hour)
+
+// notifyTierOwner creates a notification for the owner of a tier unless the
+// actor is the owner. Upvote notifications are throttled by upvoteThrottle.
+func notifyTierOwner(tierID, actorID uint, notificationType, resourceType string, resourceID uint) {
+	var tier models.Tier
+	if err := database.DB.Select("id", "user_id").First(&tier, tierID).Error; err != nil {
+		log.WithError(err).WithField("tier_id", tierID).Warn("Failed to load tier for notification")
+		return
+	}
+
+	// Tiers of deleted accounts have no owner to notify
+	if tier.UserID == actorID || tier.UserID == 0 {
+		return
+	}
+	if notificationType == models.NotificationTierUpvoted && !upvoteThrottle.Allow(tierID) {
+		return
+	}
+
+	notification := models.Notification{
+		UserID:       tier.UserID,
+		Type:         notificationType,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+	}
+	if err := database.DB.Create(&notification).Error; err != nil {
+		log.WithError(err).WithField("tier_id", tierID).Warn("Failed to create notification")
+	}
+}
+
+// GetNotifications handles GET /notifications - get the current user's unread notifications
+// @Summary Get unread notifications
+// @Description Get the authenticated user's unread notifications, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Notification
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /notifications [get]
+func GetNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	notifications := []models.Notification{}
+	if err := database.DB.Where("user_id = ? AND is_read = ?", userID, false).
+		Order("created_at DESC").
+		Find(&notifications).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch notifications")
+		http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(notifications); err != nil {
+		log.WithError(err).Error("Failed to encode notifications response")
+	}
+}
+
+// MarkNotificationsRead handles POST /notifications/mark-read - mark notifications as read
+// @Summary Mark notifications as read
+// @Description Mark the given notifications of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body MarkReadRequest true "Notification IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /notifications/mark-read [post]
+func MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return
+	}
+
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ?", userID, req.IDs).
+		Update("is_read", true)
+	if result.Error != nil {
+		log.WithError(result.Error).Error("Failed to mark notifications as read")
+		http.Error(w, "Failed to mark notifications as read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Notifications marked as read",
+		"updated": result.RowsAffected,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
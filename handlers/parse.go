@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+)
+
+// errInvalidTime is returned for timestamps that are neither RFC 3339 nor a date
+var errInvalidTime = errors.New("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+
+// parseTimeParam parses a timestamp query parameter. It accepts full RFC 3339
+// timestamps and dates alone, which mean midnight UTC. An empty string
+// returns nil.
+func parseTimeParam(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return &t, nil
+	}
+	return nil, errInvalidTime
+}
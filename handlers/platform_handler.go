@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+	"freestealer/platforms"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// PlatformStats represents aggregated tier statistics for a platform
+type PlatformStats struct {
+	Platform      string        `json:"platform"`
+	TierCount     int64         `json:"tier_count"`
+	AvgUpvotes    float64       `json:"avg_upvotes"`
+	VerifiedCount int64         `json:"verified_count"` // tiers are not verified yet, always 0
+	MostVotedTier *TierResponse `json:"most_voted_tier,omitempty"`
+}
+
+// PlatformBreakdown is one platform in the platform_breakdown of
+// GET /tiers?include_stats=true
+type PlatformBreakdown struct {
+	Platform   string  `json:"platform"`
+	Count      int64   `gorm:"column:tier_count" json:"count"`
+	AvgUpvotes float64 `json:"avg_upvotes"`
+}
+
+// PlatformListing is one platform in GET /platforms
+type PlatformListing struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	TierCount int64  `json:"tier_count"`
+}
+
+// AutocompleteResponse holds platform name suggestions for a prefix
+type AutocompleteResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+// topPlatformsLimit is the number of platforms returned by GET /platforms/stats
+const topPlatformsLimit = 20
+
+// autocompleteLimit is the maximum number of suggestions returned by GET /platforms/autocomplete
+const autocompleteLimit = 10
+
+// autocompleteCache holds suggestions per lowercase prefix
+var autocompleteCache = newTTLCache(5 * time.Minute)
+
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// visibleTiers scopes a query to public, approved tiers
+func visibleTiers() *gorm.DB {
+	return database.DB.Model(&models.Tier{}).
+		Where("is_public = ? AND status = ?", true, models.TierStatusApproved)
+}
+
+// topPlatforms selects the platforms with the most visible tiers as
+// platform, tier_count and avg_upvotes rows, largest first
+func topPlatforms() *gorm.DB {
+	return visibleTiers().
+		Select("platform, COUNT(*) AS tier_count, COALESCE(AVG(upvote_count), 0) AS avg_upvotes").
+		Group("platform").
+		Order("tier_count DESC").
+		Limit(topPlatformsLimit)
+}
+
+// GetPlatformStats handles GET /platforms/{slug}/stats - get statistics for one platform
+// @Summary Get platform statistics
+// @Description Get tier count, average upvotes and the most voted tier for a platform (case-insensitive)
+// @Tags platforms
+// @Accept json
+// @Produce json
+// @Param slug path string true "Platform name"
+// @Success 200 {object} PlatformStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /platforms/{slug}/stats [get]
+func GetPlatformStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 3 || parts[2] == "" {
+		http.Error(w, "Invalid platform", http.StatusBadRequest)
+		return
+	}
+	slug := parts[2]
+
+	var stats PlatformStats
+	if err := visibleTiers().
+		Select("MAX(platform) AS platform, COUNT(*) AS tier_count, COALESCE(AVG(upvote_count), 0) AS avg_upvotes").
+		Where("LOWER(platform) = LOWER(?)", slug).
+		Scan(&stats).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch platform stats")
+		http.Error(w, "Failed to fetch platform stats", http.StatusInternalServerError)
+		return
+	}
+
+	if stats.TierCount == 0 {
+		http.Error(w, "Platform not found", http.StatusNotFound)
+		return
+	}
+
+	var mostVoted models.Tier
+	if err := visibleTiers().
+		Where("LOWER(platform) = LOWER(?)", slug).
+		Order("upvote_count DESC, created_at DESC").
+		First(&mostVoted).Error; err == nil {
+		response := newTierResponse(&mostVoted)
+		stats.MostVotedTier = &response
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.WithError(err).Error("Failed to encode platform stats response")
+	}
+}
+
+// GetPlatforms handles GET /platforms - list all platforms
+// @Summary List platforms
+// @Description List the well-known platforms merged with platform records and platforms of public approved tiers, sorted by name
+// @Tags platforms
+// @Accept json
+// @Produce json
+// @Success 200 {array} PlatformListing
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /platforms [get]
+func GetPlatforms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var records []models.Platform
+	if err := database.DB.Find(&records).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch platforms")
+		http.Error(w, "Failed to fetch platforms", http.StatusInternalServerError)
+		return
+	}
+
+	var counts []PlatformListing
+	if err := visibleTiers().
+		Select("platform AS name, COUNT(*) AS tier_count").
+		Group("platform").
+		Scan(&counts).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch platform tier counts")
+		http.Error(w, "Failed to fetch platforms", http.StatusInternalServerError)
+		return
+	}
+
+	// Merge by lowercase name; database records take precedence over the
+	// embedded list for the URL
+	byName := make(map[string]*PlatformListing)
+	merge := func(name, url string, tierCount int64) {
+		key := strings.ToLower(name)
+		listing, ok := byName[key]
+		if !ok {
+			listing = &PlatformListing{Name: name}
+			byName[key] = listing
+		}
+		if url != "" {
+			listing.URL = url
+		}
+		listing.TierCount += tierCount
+	}
+	for _, platform := range platforms.Known() {
+		merge(platform.Name, platform.URL, 0)
+	}
+	for _, platform := range records {
+		merge(platform.Name, platform.URL, 0)
+	}
+	for _, count := range counts {
+		merge(count.Name, "", count.TierCount)
+	}
+
+	listings := make([]PlatformListing, 0, len(byName))
+	for _, listing := range byName {
+		listings = append(listings, *listing)
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return strings.ToLower(listings[i].Name) < strings.ToLower(listings[j].Name)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(listings); err != nil {
+		log.WithError(err).Error("Failed to encode platforms response")
+	}
+}
+
+// GetTopPlatformStats handles GET /platforms/stats - get the top platforms by tier count
+// @Summary Get top platform statistics
+// @Description Get the top 20 platforms by number of public approved tiers
+// @Tags platforms
+// @Accept json
+// @Produce json
+// @Success 200 {array} PlatformStats
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /platforms/stats [get]
+func GetTopPlatformStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats := []PlatformStats{}
+	if err := topPlatforms().Scan(&stats).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch platform stats")
+		http.Error(w, "Failed to fetch platform stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.WithError(err).Error("Failed to encode platform stats response")
+	}
+}
+
+// GetPlatformAutocomplete handles GET /platforms/autocomplete - suggest platform names for a prefix
+// @Summary Autocomplete platform names
+// @Description Suggest up to 10 platform names starting with q, from existing tiers and a curated list of known platforms. Results are cached for 5 minutes
+// @Tags platforms
+// @Accept json
+// @Produce json
+// @Param q query string true "Platform name prefix"
+// @Success 200 {object} AutocompleteResponse
+// @Failure 500 {object} map[string]string
+// @Router /platforms/autocomplete [get]
+func GetPlatformAutocomplete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+
+	response, ok := autocompleteCache.Get(prefix)
+	if !ok {
+		suggestions := []string{}
+		if prefix != "" {
+			var dbPlatforms []string
+			if err := visibleTiers().
+				Distinct("platform").
+				Where(`LOWER(platform) LIKE ? || '%'`, likeEscaper.Replace(prefix)).
+				Order("platform").
+				Limit(autocompleteLimit).
+				Pluck("platform", &dbPlatforms).Error; err != nil {
+				log.WithError(err).Error("Failed to fetch platform suggestions")
+				http.Error(w, "Failed to fetch platform suggestions", http.StatusInternalServerError)
+				return
+			}
+			suggestions = mergePlatformSuggestions(prefix, dbPlatforms, platforms.Names())
+		}
+		response = AutocompleteResponse{Suggestions: suggestions}
+		autocompleteCache.Set(prefix, response)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("Failed to encode autocomplete response")
+	}
+}
+
+// mergePlatformSuggestions combines platform names matching prefix, dropping
+// case-insensitive duplicates, sorted and capped at autocompleteLimit
+func mergePlatformSuggestions(prefix string, sources ...[]string) []string {
+	seen := make(map[string]bool)
+	merged := []string{}
+	for _, source := range sources {
+		for _, name := range source {
+			key := strings.ToLower(name)
+			if seen[key] || !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, name)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return strings.ToLower(merged[i]) < strings.ToLower(merged[j])
+	})
+	if len(merged) > autocompleteLimit {
+		merged = merged[:autocompleteLimit]
+	}
+	return merged
+}
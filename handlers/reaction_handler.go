@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxEmojiLength is the most code points a reaction may have, matching the
+// emoji column size. Flags and skin tones take several code points.
+const maxEmojiLength = 10
+
+// ReactionRequest is the body of POST /comments/{id}/reactions
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// ReactionSummary is one emoji in GET /comments/{id}/reactions
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	UserReacted bool   `json:"user_reacted"`
+}
+
+// validEmoji reports whether s looks like a single emoji: a short run of
+// non-ASCII symbols, optionally joined by zero-width joiners or modifiers
+func validEmoji(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > maxEmojiLength {
+		return false
+	}
+	for _, r := range s {
+		if r < utf8.RuneSelf || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return false
+		}
+		if !unicode.IsGraphic(r) && r != '\u200d' {
+			return false
+		}
+	}
+	return true
+}
+
+// reactionCountDelta is an expression adding delta to emoji's count in
+// reactions_summary, dropping the key once it reaches zero
+func reactionCountDelta(emoji string, delta int) clause.Expr {
+	return gorm.Expr(`CASE WHEN COALESCE((reactions_summary->>?::text)::int, 0) + ? <= 0
+		THEN COALESCE(reactions_summary, '{}'::jsonb) - ?::text
+		ELSE jsonb_set(COALESCE(reactions_summary, '{}'::jsonb), ARRAY[?::text], to_jsonb(COALESCE((reactions_summary->>?::text)::int, 0) + ?))
+		END`, emoji, delta, emoji, emoji, emoji, delta)
+}
+
+// commentReactionID extracts the comment ID from a /comments/{id}/reactions path
+func commentReactionID(r *http.Request) (uint64, bool) {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 || parts[3] != "reactions" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// commentReactions lists a comment's reactions from its summary, most used
+// first, marking the ones userID made
+func commentReactions(comment *models.Comment, userID uint) ([]ReactionSummary, error) {
+	mine := make(map[string]bool)
+	if userID != 0 {
+		var emojis []string
+		if err := database.DB.Model(&models.CommentReaction{}).
+			Where("comment_id = ? AND user_id = ?", comment.ID, userID).
+			Pluck("emoji", &emojis).Error; err != nil {
+			return nil, err
+		}
+		for _, emoji := range emojis {
+			mine[emoji] = true
+		}
+	}
+
+	reactions := make([]ReactionSummary, 0, len(comment.ReactionsSummary))
+	for emoji, count := range comment.ReactionsSummary {
+		reactions = append(reactions, ReactionSummary{Emoji: emoji, Count: count, UserReacted: mine[emoji]})
+	}
+	sort.Slice(reactions, func(i, j int) bool {
+		if reactions[i].Count != reactions[j].Count {
+			return reactions[i].Count > reactions[j].Count
+		}
+		return reactions[i].Emoji < reactions[j].Emoji
+	})
+	return reactions, nil
+}
+
+// GetCommentReactions handles GET /comments/{id}/reactions - list reactions to a comment
+// @Summary Get comment reactions
+// @Description List the emoji reactions to a comment with their counts, most used first, and whether the caller used each
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Success 200 {array} ReactionSummary
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /comments/{id}/reactions [get]
+func GetCommentReactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := commentReactionID(r)
+	if !ok {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	var comment models.Comment
+	if err := database.DB.Select("id", "reactions_summary").First(&comment, id).Error; err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	reactions, err := commentReactions(&comment, userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch comment reactions")
+		http.Error(w, "Failed to fetch reactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reactions); err != nil {
+		log.WithError(err).Error("Failed to encode reactions response")
+	}
+}
+
+// ToggleCommentReaction handles POST /comments/{id}/reactions - add or remove a reaction
+// @Summary Toggle a comment reaction
+// @Description Add the caller's emoji reaction to a comment, or remove it if it is already there. Returns the comment's reactions afterwards
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body ReactionRequest true "Emoji"
+// @Success 200 {array} ReactionSummary "Reaction removed"
+// @Success 201 {array} ReactionSummary "Reaction added"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /comments/{id}/reactions [post]
+func ToggleCommentReaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, ok := commentReactionID(r)
+	if !ok {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !validEmoji(req.Emoji) {
+		http.Error(w, "emoji must be a single emoji", http.StatusBadRequest)
+		return
+	}
+
+	// The comment row is locked so concurrent toggles of one reaction and
+	// their summary updates apply one after the other
+	added := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Comment{}, id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ? AND comment_id = ? AND emoji = ?", userID, id, req.Emoji).Delete(&models.CommentReaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		delta := -1
+		if result.RowsAffected == 0 {
+			reaction := models.CommentReaction{UserID: userID, CommentID: uint(id), Emoji: req.Emoji}
+			if err := tx.Create(&reaction).Error; err != nil {
+				return err
+			}
+			delta = 1
+			added = true
+		}
+
+		return tx.Model(&models.Comment{}).Where("id = ?", id).
+			UpdateColumn("reactions_summary", reactionCountDelta(req.Emoji, delta)).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to toggle comment reaction")
+		http.Error(w, "Failed to update reaction", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":    userID,
+		"comment_id": id,
+		"added":      added,
+	}).Info("Comment reaction toggled")
+
+	var comment models.Comment
+	if err := database.DB.Select("id", "reactions_summary").First(&comment, id).Error; err != nil {
+		log.WithError(err).Error("Failed to reload comment reactions")
+		http.Error(w, "Failed to fetch reactions", http.StatusInternalServerError)
+		return
+	}
+	reactions, err := commentReactions(&comment, userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch comment reactions")
+		http.Error(w, "Failed to fetch reactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if added {
+		w.WriteHeader(http.StatusCreated)
+	}
+	if err := json.NewEncoder(w).Encode(reactions); err != nil {
+		log.WithError(err).Error("Failed to encode reactions response")
+	}
+}
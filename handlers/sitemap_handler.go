@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sitemapNamespace is the XML namespace of the Sitemap Protocol
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// defaultAppBaseURL is used for sitemap URLs when APP_BASE_URL is not set
+const defaultAppBaseURL = "http://localhost:8080"
+
+// sitemapPageSize is the maximum number of URLs in one sitemap file, as set by the protocol
+var sitemapPageSize = 50000
+
+// sitemapURLSet is a single sitemap file listing tier pages
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is one page entry in a sitemap
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapIndex lists the paginated sitemap files
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// sitemapEntry is one sitemap file in a sitemap index
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// appBaseURL returns the public base URL of the site without a trailing slash
+func appBaseURL() string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = defaultAppBaseURL
+	}
+	return strings.TrimRight(base, "/")
+}
+
+// writeXML encodes v as an XML document
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		log.WithError(err).Error("Failed to write sitemap header")
+		return
+	}
+	if err := xml.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Failed to encode sitemap")
+	}
+}
+
+// writeSitemapPage writes the tier URLs of one sitemap page (1-based)
+func writeSitemapPage(w http.ResponseWriter, page int) {
+	var tiers []models.Tier
+	if err := visibleTiers().
+		Select("id", "updated_at").
+		Order("id ASC").
+		Limit(sitemapPageSize).
+		Offset((page - 1) * sitemapPageSize).
+		Find(&tiers).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch sitemap tiers")
+		http.Error(w, "Failed to generate sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	base := appBaseURL()
+	urlSet := sitemapURLSet{Xmlns: sitemapNamespace, URLs: make([]sitemapURL, 0, len(tiers))}
+	for _, tier := range tiers {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     fmt.Sprintf("%s/tiers/%d", base, tier.ID),
+			LastMod: tier.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	writeXML(w, urlSet)
+}
+
+// GetSitemap handles GET /sitemap.xml - sitemap of public tiers for search engines
+// @Summary Get the sitemap
+// @Description Sitemap of public, approved tiers. Switches to a sitemap index pointing at /sitemap-{n}.xml when there are more than 50,000 tiers
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index XML"
+// @Failure 500 {object} map[string]string
+// @Router /sitemap.xml [get]
+func GetSitemap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var total int64
+	if err := visibleTiers().Count(&total).Error; err != nil {
+		log.WithError(err).Error("Failed to count sitemap tiers")
+		http.Error(w, "Failed to generate sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	if total <= int64(sitemapPageSize) {
+		writeSitemapPage(w, 1)
+		return
+	}
+
+	base := appBaseURL()
+	pages := int((total + int64(sitemapPageSize) - 1) / int64(sitemapPageSize))
+	index := sitemapIndex{Xmlns: sitemapNamespace, Sitemaps: make([]sitemapEntry, 0, pages)}
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+			Loc: fmt.Sprintf("%s/sitemap-%d.xml", base, page),
+		})
+	}
+
+	log.WithFields(log.Fields{
+		"tiers": total,
+		"pages": pages,
+	}).Info("Generated sitemap index")
+
+	writeXML(w, index)
+}
+
+// GetSitemapPage handles GET /sitemap-{n}.xml - one page of a paginated sitemap
+// @Summary Get a sitemap page
+// @Description One page of up to 50,000 tier URLs, as listed by the sitemap index
+// @Tags sitemap
+// @Produce xml
+// @Param n path int true "Sitemap page number"
+// @Success 200 {string} string "Sitemap XML"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /sitemap-{n}.xml [get]
+func GetSitemapPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	number := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/sitemap-"), ".xml")
+	page, err := strconv.Atoi(number)
+	if err != nil || page < 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	var total int64
+	if err := visibleTiers().Count(&total).Error; err != nil {
+		log.WithError(err).Error("Failed to count sitemap tiers")
+		http.Error(w, "Failed to generate sitemap", http.StatusInternalServerError)
+		return
+	}
+	if page > 1 && int64((page-1)*sitemapPageSize) >= total {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeSitemapPage(w, page)
+}
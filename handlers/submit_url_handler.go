@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"freestealer/models"
+	"freestealer/normalize"
+	"freestealer/sanitize"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+)
+
+// scrapeTimeout bounds fetching a pricing page, redirects included
+const scrapeTimeout = 5 * time.Second
+
+// scrapeBodyLimit is the most of a pricing page that is parsed
+const scrapeBodyLimit = 1 << 20
+
+// scrapeClient fetches pricing pages for SubmitTierURL. It refuses to connect
+// to loopback, private and link-local addresses, also after redirects, so the
+// endpoint cannot be used to reach internal services.
+var scrapeClient = &http.Client{
+	Timeout: scrapeTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{Timeout: scrapeTimeout, Control: refuseInternalAddress}).DialContext,
+	},
+}
+
+// scrapeLimiter holds the users who submitted a URL in the last minute
+var scrapeLimiter = newTTLCache(time.Minute)
+
+// titleSeparators split page titles such as "Pricing | Railway"
+var titleSeparators = []string{" | ", " - ", " – ", " — ", " · "}
+
+// refuseInternalAddress is a net.Dialer Control func rejecting non-public IPs
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errors.New("refusing to connect to internal address " + host)
+	}
+	return nil
+}
+
+// SubmitURLRequest is the body of POST /tiers/submit-url
+type SubmitURLRequest struct {
+	URL string `json:"url"`
+}
+
+// pageMeta is what SubmitTierURL reads from a pricing page
+type pageMeta struct {
+	title       string
+	siteName    string
+	description string
+	image       string
+}
+
+// parsePageMeta extracts the title and Open Graph tags from an HTML page
+func parsePageMeta(r io.Reader) (pageMeta, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return pageMeta{}, err
+	}
+
+	var meta pageMeta
+	var title string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if title == "" && n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "meta":
+				var key, content string
+				for _, attr := range n.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(attr.Val)
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				switch key {
+				case "og:title":
+					meta.title = content
+				case "og:site_name":
+					meta.siteName = content
+				case "og:description":
+					meta.description = content
+				case "description":
+					if meta.description == "" {
+						meta.description = content
+					}
+				case "og:image":
+					meta.image = content
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if meta.title == "" {
+		meta.title = title
+	}
+	return meta, nil
+}
+
+// platformFromPage picks a platform name from the page's og:site_name, the
+// trailing part of its og:title ("Pricing | Railway"), or else the domain
+func platformFromPage(meta pageMeta, pageURL *url.URL) string {
+	if siteName := sanitize.StripTags(meta.siteName); siteName != "" {
+		return normalize.NormalizePlatform(siteName)
+	}
+
+	if meta.title != "" {
+		name := sanitize.StripTags(meta.title)
+		for _, sep := range titleSeparators {
+			if i := strings.LastIndex(name, sep); i >= 0 {
+				name = name[i+len(sep):]
+			}
+		}
+		if platform := normalize.NormalizePlatform(name); platform != "" {
+			return platform
+		}
+	}
+
+	// "www.railway.app" and "docs.railway.app" both become "railway.app"
+	labels := strings.Split(pageURL.Hostname(), ".")
+	if len(labels) > 2 {
+		labels = labels[len(labels)-2:]
+	}
+	return normalize.NormalizePlatform(strings.Join(labels, "."))
+}
+
+// SubmitTierURL handles POST /tiers/submit-url - suggest tier fields from a pricing page
+// @Summary Suggest a tier from a URL
+// @Description Fetches a pricing page and returns a pre-filled tier for the user to review and submit with POST /tiers. Nothing is saved. Limited to one URL per user per minute.
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param request body SubmitURLRequest true "Pricing page URL"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/submit-url [post]
+func SubmitTierURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req SubmitURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pageURL, err := url.ParseRequestURI(req.URL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		http.Error(w, "url must be a valid http or https URL", http.StatusBadRequest)
+		return
+	}
+
+	// The slot is claimed before fetching so failed fetches count too
+	key := strconv.FormatUint(uint64(userID), 10)
+	if !scrapeLimiter.Add(key, time.Now()) {
+		retryAfter := time.Minute
+		if v, ok := scrapeLimiter.Get(key); ok {
+			retryAfter = time.Until(v.(time.Time).Add(time.Minute))
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		http.Error(w, "Only one URL may be submitted per minute", http.StatusTooManyRequests)
+		return
+	}
+
+	resp, err := scrapeClient.Get(pageURL.String())
+	if err != nil {
+		log.WithError(err).WithField("url", pageURL.String()).Warn("Failed to fetch submitted URL")
+		http.Error(w, "Failed to fetch url", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.WithFields(log.Fields{"url": pageURL.String(), "status": resp.StatusCode}).Warn("Submitted URL returned an error")
+		http.Error(w, "Failed to fetch url", http.StatusBadGateway)
+		return
+	}
+
+	meta, err := parsePageMeta(io.LimitReader(resp.Body, scrapeBodyLimit))
+	if err != nil {
+		log.WithError(err).WithField("url", pageURL.String()).Warn("Failed to parse submitted URL")
+		http.Error(w, "Failed to parse url", http.StatusBadGateway)
+		return
+	}
+
+	platform := platformFromPage(meta, pageURL)
+	tier := models.Tier{
+		UserID:       userID,
+		Platform:     platform,
+		Name:         platform + " Free Tier",
+		Description:  strings.TrimSpace(sanitize.StripTags(meta.description)),
+		IsPublic:     true,
+		PricingModel: models.PricingFreeForever,
+		Category:     models.CategoryOther,
+		URL:          pageURL.String(),
+	}
+	if validateScreenshotURL(meta.image) == nil {
+		tier.ScreenshotURL = meta.image
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":  userID,
+		"url":      pageURL.String(),
+		"platform": platform,
+	}).Info("Suggested tier from URL")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier suggestion")
+	}
+}
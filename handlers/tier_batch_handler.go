@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxBatchSize is the most tiers POST /tiers/batch accepts at once
+const maxBatchSize = 50
+
+// BatchFailure is a tier of a batch that was not created
+type BatchFailure struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// BatchCreateResponse lists the outcome of POST /tiers/batch. Created holds
+// the IDs of the created tiers in submission order.
+type BatchCreateResponse struct {
+	Created []uint         `json:"created"`
+	Failed  []BatchFailure `json:"failed"`
+}
+
+// CreateTiersBatch handles POST /tiers/batch - create several tiers at once
+// @Summary Create tiers in a batch
+// @Description Create up to 50 tiers at once. Each tier is validated like POST /tiers; invalid tiers are reported by their index in the array and the valid ones are still created. Tiers are owned by the caller
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param tiers body []models.Tier true "Tier objects"
+// @Success 201 {object} BatchCreateResponse "At least one tier created"
+// @Failure 400 {object} BatchCreateResponse "No tier created"
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/batch [post]
+func CreateTiersBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	// Items are decoded one by one so a malformed item fails on its own
+	var items []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		http.Error(w, "Request body must be an array of tiers", http.StatusBadRequest)
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchSize {
+		http.Error(w, "A batch must have between 1 and 50 tiers", http.StatusBadRequest)
+		return
+	}
+
+	response := BatchCreateResponse{Created: []uint{}, Failed: []BatchFailure{}}
+	tiers := make([]models.Tier, 0, len(items))
+	seen := make(map[[2]string]bool)
+	for i, item := range items {
+		var tier models.Tier
+		if err := json.Unmarshal(item, &tier); err != nil {
+			response.Failed = append(response.Failed, BatchFailure{Index: i, Error: "Invalid tier object"})
+			continue
+		}
+
+		tier.UserID = userID
+
+		if err := prepareNewTier(&tier); err != nil {
+			response.Failed = append(response.Failed, BatchFailure{Index: i, Error: err.Error()})
+			continue
+		}
+
+		// Duplicates would fail the whole insert on idx_unique_user_platform_name
+		key := [2]string{tier.Platform, tier.Name}
+		var existing int64
+		if err := database.DB.Model(&models.Tier{}).
+			Where("user_id = ? AND platform = ? AND name = ?", userID, tier.Platform, tier.Name).
+			Count(&existing).Error; err != nil {
+			log.WithError(err).Error("Failed to check for duplicate tiers")
+			http.Error(w, "Failed to create tiers", http.StatusInternalServerError)
+			return
+		}
+		if seen[key] || existing > 0 {
+			response.Failed = append(response.Failed, BatchFailure{Index: i, Error: duplicateTierMessage})
+			continue
+		}
+		seen[key] = true
+		tiers = append(tiers, tier)
+	}
+
+	if len(tiers) > 0 {
+		if err := database.DB.CreateInBatches(&tiers, 10).Error; err != nil {
+			// Only a concurrent request can have created a duplicate since the check
+			if isUniqueViolation(err, "idx_unique_user_platform_name") {
+				http.Error(w, duplicateTierMessage, http.StatusConflict)
+				return
+			}
+			log.WithError(err).Error("Failed to create tier batch")
+			http.Error(w, "Failed to create tiers", http.StatusInternalServerError)
+			return
+		}
+		for _, tier := range tiers {
+			response.Created = append(response.Created, tier.ID)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"user_id": userID,
+		"created": len(response.Created),
+		"failed":  len(response.Failed),
+	}).Info("Tier batch created")
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(response.Created) > 0 {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("Failed to encode batch response")
+	}
+}
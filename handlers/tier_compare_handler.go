@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxCompareTiers is the most tiers GET /tiers/compare compares at once
+const maxCompareTiers = 5
+
+// CompareTiersResponse holds the compared tiers in the requested order and
+// the requested IDs that could not be shown
+type CompareTiersResponse struct {
+	Tiers      []TierResponse `json:"tiers"`
+	MissingIDs []uint         `json:"missing_ids"` // not found, or not visible to the caller
+}
+
+// parseCompareIDs parses a comma separated list of tier IDs, dropping repeats
+// and keeping the order they were given in
+func parseCompareIDs(raw string) ([]uint, error) {
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("Invalid tier ID %q", part)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) > maxCompareTiers {
+		return nil, fmt.Errorf("At most %d tiers can be compared", maxCompareTiers)
+	}
+	return ids, nil
+}
+
+// CompareTiers handles GET /tiers/compare - get several tiers side by side
+// @Summary Compare tiers
+// @Description Get up to 5 tiers with their full details in the order their IDs were given. IDs that do not exist, or whose tier is private or not approved and not the caller's, are listed in missing_ids
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma separated tier IDs, at most 5" example(1,2,3)
+// @Success 200 {object} CompareTiersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tiers/compare [get]
+func CompareTiers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	raw := r.URL.Query().Get("ids")
+	if strings.TrimSpace(raw) == "" {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return
+	}
+	ids, err := parseCompareIDs(raw)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var tiers []models.Tier
+	if err := database.DB.Preload("User").Where("id IN (?)", ids).Find(&tiers).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch tiers to compare")
+		http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
+		return
+	}
+	byID := make(map[uint]*models.Tier, len(tiers))
+	for i := range tiers {
+		byID[tiers[i].ID] = &tiers[i]
+	}
+
+	response := CompareTiersResponse{Tiers: []TierResponse{}, MissingIDs: []uint{}}
+	for _, id := range ids {
+		tier, ok := byID[id]
+		if ok && !canViewTier(r, tier) {
+			ok = false
+		}
+		if !ok {
+			response.MissingIDs = append(response.MissingIDs, id)
+			continue
+		}
+		response.Tiers = append(response.Tiers, newTierResponse(tier))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("Failed to encode compare response")
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"freestealer/models"
+	"freestealer/normalize"
+
+	"gorm.io/gorm"
+)
+
+// TierFilter is the decoded form of the filter query parameter of GET /tiers,
+// e.g. {"category": "database", "egress_free": true, "regions": ["US"]}.
+// Unset fields do not filter.
+type TierFilter struct {
+	Platform     string   `json:"platform,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	PricingModel string   `json:"pricing_model,omitempty"`
+	EgressFree   *bool    `json:"egress_free,omitempty"`
+	Regions      []string `json:"regions,omitempty"` // country codes the tier must be available in
+	MinUpvotes   *int     `json:"min_upvotes,omitempty"`
+}
+
+// filterSQLKeywords are rejected in filter values. Values are always bound as
+// query parameters; this only turns obvious injection attempts away early.
+var filterSQLKeywords = map[string]bool{
+	"select": true, "insert": true, "update": true, "delete": true, "drop": true,
+	"union": true, "alter": true, "truncate": true, "exec": true, "create": true,
+}
+
+// ParseFilter decodes and validates a filter expression. Unknown keys,
+// trailing data, invalid categories, pricing models or country codes and
+// values containing SQL keywords are errors.
+func ParseFilter(raw string) (TierFilter, error) {
+	var filter TierFilter
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&filter); err != nil {
+		return TierFilter{}, fmt.Errorf("invalid filter: %w", err)
+	}
+	if dec.More() {
+		return TierFilter{}, errors.New("invalid filter: unexpected data after the object")
+	}
+
+	values := append([]string{filter.Platform, filter.Category, filter.PricingModel}, filter.Regions...)
+	for _, value := range values {
+		if containsSQLKeyword(value) {
+			return TierFilter{}, fmt.Errorf("invalid filter: value %q is not allowed", value)
+		}
+	}
+
+	if filter.Category != "" && !validCategory(filter.Category) {
+		return TierFilter{}, fmt.Errorf("invalid filter: unknown category %q", filter.Category)
+	}
+	if filter.PricingModel != "" && !validPricingModel(filter.PricingModel) {
+		return TierFilter{}, fmt.Errorf("invalid filter: unknown pricing_model %q", filter.PricingModel)
+	}
+	for i, region := range filter.Regions {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if len(region) != 2 || !isASCIIUpper(region) {
+			return TierFilter{}, fmt.Errorf("invalid filter: %q is not a two-letter country code", filter.Regions[i])
+		}
+		filter.Regions[i] = region
+	}
+	filter.Platform = normalize.NormalizePlatform(filter.Platform)
+	return filter, nil
+}
+
+// apply adds a Where clause to query for each field set in the filter
+func (f TierFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Platform != "" {
+		query = query.Where("platform = ?", f.Platform)
+	}
+	if f.Category != "" {
+		query = query.Where("category = ?", f.Category)
+	}
+	if f.PricingModel != "" {
+		query = query.Where("pricing_model = ?", f.PricingModel)
+	}
+	if f.EgressFree != nil {
+		query = query.Where("egress_free = ?", *f.EgressFree)
+	}
+	if len(f.Regions) > 0 {
+		// Same test as the geo parameter: unrestricted tiers are available everywhere
+		query = query.Where("(geo_restrictions IS NULL OR geo_restrictions = '' OR NULLIF(geo_restrictions, '')::jsonb @> ?::jsonb)",
+			string(models.NewCountryList(f.Regions)))
+	}
+	if f.MinUpvotes != nil {
+		query = query.Where("upvote_count >= ?", *f.MinUpvotes)
+	}
+	return query
+}
+
+// containsSQLKeyword reports whether s contains a word from filterSQLKeywords
+// or a SQL comment or statement separator
+func containsSQLKeyword(s string) bool {
+	if strings.Contains(s, "--") || strings.Contains(s, ";") || strings.Contains(s, "/*") {
+		return true
+	}
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if filterSQLKeywords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// isASCIIUpper reports whether s consists of the letters A to Z only
+func isASCIIUpper(s string) bool {
+	return strings.TrimLeft(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+}
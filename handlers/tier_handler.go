@@ -1,26 +1,230 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"freestealer/cache"
 	"freestealer/database"
+	"freestealer/markdown"
 	"freestealer/models"
+	"freestealer/normalize"
+	"freestealer/sanitize"
+	"freestealer/views"
 
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// monthlyHoursPattern matches values like "500" or "500/month"
+var monthlyHoursPattern = regexp.MustCompile(`^[0-9]+(/month)?$`)
+
+// platformNamePattern matches platform names such as "Railway" or "Fly.io":
+// 3 to 100 characters starting with a letter and ending with a letter or digit
+var platformNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9 ._-]{1,98}[A-Za-z0-9]$`)
+
+// wordPattern matches a run of at least two letters or digits
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]{2,}`)
+
+// minTierNameLength keeps placeholder names like "Test" or "asdf" out of the listing
+const minTierNameLength = 5
+
+// TierDetail is a single tier with its Markdown description rendered to HTML
+type TierDetail struct {
+	TierResponse
+	RenderedDescription string `json:"rendered_description"`
+}
+
+// ScreenshotRequest represents a tier screenshot update request
+type ScreenshotRequest struct {
+	URL string `json:"url"`
+}
+
+// validateScreenshotURL checks that a screenshot URL, if set, is a parseable https URL
+func validateScreenshotURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	if !strings.HasPrefix(rawURL, "https://") {
+		return errors.New("screenshot_url must start with https://")
+	}
+	if _, err := url.Parse(rawURL); err != nil {
+		return errors.New("screenshot_url is not a valid URL")
+	}
+	return nil
+}
+
+// sanitizeTierFields strips HTML from the free-text tier fields
+func sanitizeTierFields(tier *models.Tier) error {
+	fields := map[string]*string{
+		"name":            &tier.Name,
+		"description":     &tier.Description,
+		"platform":        &tier.Platform,
+		"cpu_limit":       &tier.CPULimit,
+		"memory_limit":    &tier.MemoryLimit,
+		"storage_limit":   &tier.StorageLimit,
+		"bandwidth_limit": &tier.BandwidthLimit,
+	}
+
+	for name, value := range fields {
+		cleaned, err := sanitize.Text(*value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*value = cleaned
+	}
+	return nil
+}
+
+// validPricingModel reports whether model is one of the known tier pricing models
+func validPricingModel(model string) bool {
+	switch model {
+	case models.PricingFreeForever, models.PricingFreeTrial, models.PricingFreemium:
+		return true
+	}
+	return false
+}
+
+// validCategory reports whether category is one of the known tier categories
+func validCategory(category string) bool {
+	for _, c := range models.TierCategories {
+		if category == c {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTierFields checks the optional tier fields that have a constrained format
+func validateTierFields(tier *models.Tier) error {
+	if tier.URL != "" {
+		u, err := url.ParseRequestURI(tier.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("url must be a valid http or https URL")
+		}
+	}
+
+	if tier.MonthlyHours != "" && !monthlyHoursPattern.MatchString(tier.MonthlyHours) {
+		return errors.New("monthly_hours must be a number, optionally followed by /month")
+	}
+
+	if tier.PricingModel != "" && !validPricingModel(tier.PricingModel) {
+		return errors.New("pricing_model must be one of free-forever, free-trial, freemium")
+	}
+
+	if tier.Category != "" && !validCategory(tier.Category) {
+		return errors.New("category must be one of " + strings.Join(models.TierCategories, ", "))
+	}
+
+	for _, code := range tier.GeoRestrictions.Codes() {
+		if !countryCodes[code] {
+			return fmt.Errorf("geo_restrictions: %q is not an ISO 3166-1 alpha-2 country code", code)
+		}
+	}
+
+	if tier.EgressLimitGB < 0 {
+		return errors.New("egress_limit_gb must not be negative")
+	}
+
+	return validateScreenshotURL(tier.ScreenshotURL)
+}
+
+// validateTierContent rejects submissions that do not look like a real
+// service: a malformed platform name, a placeholder tier name or a link
+// that is not https
+func validateTierContent(tier *models.Tier) error {
+	if !platformNamePattern.MatchString(tier.Platform) {
+		return errors.New("platform must be 3 to 100 letters, digits, spaces, dots, dashes or underscores, starting with a letter and ending with a letter or digit")
+	}
+
+	name := strings.TrimSpace(tier.Name)
+	if utf8.RuneCountInString(name) < minTierNameLength {
+		return fmt.Errorf("name must be at least %d characters", minTierNameLength)
+	}
+	if !wordPattern.MatchString(name) {
+		return errors.New("name must contain a word, not only symbols")
+	}
+
+	if tier.URL != "" {
+		u, err := url.ParseRequestURI(tier.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("url must be a valid https URL")
+		}
+	}
+	return nil
+}
+
+// canViewTier reports whether the tier is public and approved, or belongs to the caller.
+// Private and unreviewed tiers are shown to their owner and admins only.
+func canViewTier(r *http.Request, tier *models.Tier) bool {
+	return tier.IsPublic && tier.Status == models.TierStatusApproved || canModify(r, tier.UserID)
+}
+
+// prepareNewTier sanitizes and validates a submitted tier and fills in the
+// defaults of a new submission
+func prepareNewTier(tier *models.Tier) error {
+	// Server-assigned fields cannot be set by the submitter
+	tier.ID, tier.CreatedAt = 0, time.Time{}
+	tier.UpvoteCount, tier.DownvoteCount, tier.CommentCount = 0, 0, 0
+	tier.ViewCount, tier.ClickCount, tier.BookmarksCount = 0, 0, 0
+	tier.IsFeatured, tier.FeaturedUntil = false, nil
+	tier.RejectionReason = ""
+
+	if err := sanitizeTierFields(tier); err != nil {
+		return err
+	}
+	tier.Platform = normalize.NormalizePlatform(tier.Platform)
+
+	// Validate required fields
+	if tier.Platform == "" || tier.Name == "" {
+		return errors.New("Platform and name are required")
+	}
+
+	if err := validateTierContent(tier); err != nil {
+		return err
+	}
+	if err := validateTierFields(tier); err != nil {
+		return err
+	}
+	if tier.PricingModel == "" {
+		tier.PricingModel = models.PricingFreeForever
+	}
+	if tier.Category == "" {
+		tier.Category = models.CategoryOther
+	}
+
+	// New submissions wait for review unless auto-approval is enabled
+	tier.Status = models.TierStatusPending
+	if os.Getenv("TIER_AUTO_APPROVE") == "true" {
+		tier.Status = models.TierStatusApproved
+	}
+	return nil
+}
+
 // CreateTier handles POST /tiers - create a new tier
 // @Summary Create a new tier
-// @Description Create a new free tier hosting platform entry
+// @Description Create a new free tier hosting platform entry. pricing_model is one of free-forever (default), free-trial or freemium. category is one of compute, database, storage, cdn, email, queue, cache, monitoring or other (default). The tier is owned by the caller; user_id, id, created_at, rejection_reason, the counters, is_featured and featured_until in the body are ignored
 // @Tags tiers
 // @Accept json
 // @Produce json
 // @Param tier body models.Tier true "Tier object"
-// @Success 201 {object} models.Tier
+// @Param Idempotency-Key header string false "Unique key for this submission; a retry with the same key within 24 hours returns the first response with status 200 instead of creating another tier"
+// @Success 201 {object} TierResponse
+// @Success 200 {object} TierResponse "Replayed response, with X-Idempotent-Replayed: true"
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /tiers [post]
@@ -30,6 +234,35 @@ func CreateTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	// A retry with the same Idempotency-Key gets the first response back
+	// instead of creating the tier again
+	cacheKey, err := idempotencyCacheKey(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var response []byte
+	if cacheKey != "" {
+		if !idempotencyCache.Add(cacheKey, idempotencyPending{}) {
+			replayIdempotent(w, cacheKey)
+			return
+		}
+		// Only successful responses are kept; failed requests may be retried
+		defer func() {
+			if response != nil {
+				idempotencyCache.Set(cacheKey, response)
+			} else {
+				idempotencyCache.Delete(cacheKey)
+			}
+		}()
+	}
+
 	var tier models.Tier
 	if err := json.NewDecoder(r.Body).Decode(&tier); err != nil {
 		log.WithError(err).Error("Failed to decode tier request")
@@ -37,14 +270,20 @@ func CreateTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if tier.Platform == "" || tier.Name == "" {
-		http.Error(w, "Platform and name are required", http.StatusBadRequest)
+	// Tiers are always submitted by the caller, whatever user_id the body names
+	tier.UserID = userID
+
+	if err := prepareNewTier(&tier); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Create tier in database
 	if err := database.DB.Create(&tier).Error; err != nil {
+		if isUniqueViolation(err, "idx_unique_user_platform_name") {
+			http.Error(w, duplicateTierMessage, http.StatusConflict)
+			return
+		}
 		log.WithError(err).Error("Failed to create tier")
 		http.Error(w, "Failed to create tier", http.StatusInternalServerError)
 		return
@@ -53,26 +292,209 @@ func CreateTier(w http.ResponseWriter, r *http.Request) {
 	log.WithFields(log.Fields{
 		"tier_id":  tier.ID,
 		"platform": tier.Platform,
+		"status":   tier.Status,
 	}).Info("Tier created")
 
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+		http.Error(w, "Failed to encode tier", http.StatusInternalServerError)
+		return
+	}
+	response = body.Bytes()
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(tier); err != nil {
-		log.WithError(err).Error("Failed to encode tier response")
+	if _, err := w.Write(response); err != nil {
+		log.WithError(err).Error("Failed to write tier response")
 	}
 }
 
+// sortField is a column GetTiers may sort by and its direction when the
+// field is given without a "-" prefix
+type sortField struct {
+	column     string
+	descending bool
+}
+
+// sortFields is the allowlist of fields accepted in a comma separated sort
+// parameter. Counts sort highest first by default, everything else ascending.
+var sortFields = map[string]sortField{
+	"upvotes":    {column: "upvote_count", descending: true},
+	"downvotes":  {column: "downvote_count", descending: true},
+	"views":      {column: "view_count", descending: true},
+	"comments":   {column: "comment_count", descending: true},
+	"bookmarks":  {column: "bookmarks_count", descending: true},
+	"created_at": {column: "created_at"},
+	"updated_at": {column: "updated_at"},
+	"name":       {column: "name"},
+	"platform":   {column: "platform"},
+}
+
+// parseSortParam turns a JSON:API style sort parameter such as
+// "upvotes,-created_at" into ORDER BY terms. A "-" prefix reverses the field's
+// default direction. Unknown and repeated fields are ignored.
+func parseSortParam(raw string) []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(part)
+		reverse := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := sortFields[name]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		direction := "ASC"
+		if field.descending != reverse {
+			direction = "DESC"
+		}
+		order = append(order, field.column+" "+direction)
+	}
+	return order
+}
+
+// defaultPageSize is the number of tiers per page when per_page is not given
+const defaultPageSize = 20
+
+// defaultMaxPageSize caps per_page unless MAX_PAGE_SIZE overrides it
+const defaultMaxPageSize = 100
+
+// maxPageSize returns the largest per_page GetTiers serves
+func maxPageSize() int {
+	if v := os.Getenv("MAX_PAGE_SIZE"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err == nil && size > 0 {
+			return size
+		}
+		log.WithField("value", v).Warn("Invalid MAX_PAGE_SIZE, using default")
+	}
+	return defaultMaxPageSize
+}
+
+// parsePerPage returns the page size requested by a per_page parameter.
+// Out of range values are clamped to 1..maxPageSize(), or rejected when
+// STRICT_PAGE_SIZE is true. Missing or non-numeric values use the default.
+func parsePerPage(raw string) (int, error) {
+	perPage, err := strconv.Atoi(raw)
+	if err != nil {
+		return defaultPageSize, nil
+	}
+
+	max := maxPageSize()
+	if perPage >= 1 && perPage <= max {
+		return perPage, nil
+	}
+	if os.Getenv("STRICT_PAGE_SIZE") == "true" {
+		return 0, fmt.Errorf("per_page must be between 1 and %d", max)
+	}
+	if perPage < 1 {
+		return 1, nil
+	}
+	return max, nil
+}
+
+// totalPages returns how many pages of perPage items total items fill; 0 when there are none
+func totalPages(total, perPage int) int {
+	return (total + perPage - 1) / perPage
+}
+
+// buildLinkHeader returns an RFC 5988 Link header value with first, prev,
+// next and last links for a paginated listing. baseURL may already carry a
+// query string; page and per_page are appended to it. prev and next are
+// omitted at either end of the listing.
+func buildLinkHeader(baseURL string, page, perPage, total int) string {
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	link := func(p int, rel string) string {
+		return fmt.Sprintf(`<%s%spage=%d&per_page=%d>; rel="%s"`, baseURL, separator, p, perPage, rel)
+	}
+
+	// An empty listing still has a single (empty) page
+	last := totalPages(total, perPage)
+	if last < 1 {
+		last = 1
+	}
+
+	links := []string{link(1, "first")}
+	if page > 1 {
+		prev := page - 1
+		if prev > last {
+			prev = last
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if page < last {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+// tierListDescriptionLength is how much of the description GET /tiers returns
+const tierListDescriptionLength = 200
+
+// tierListColumns are the tiers columns read into a TierListItem
+var tierListColumns = fmt.Sprintf("id, platform, name, LEFT(description, %d) AS description, "+
+	"upvote_count, downvote_count, comment_count, bookmarks_count, is_public, created_at, user_id", tierListDescriptionLength)
+
+// attachListUsers loads the authors of items in a single query
+func attachListUsers(items []TierListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.UserID
+	}
+
+	var users []models.User
+	if err := database.DB.Select("id, username, avatar_url").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*TierListUser, len(users))
+	for _, user := range users {
+		byID[user.ID] = &TierListUser{Username: user.Username, AvatarURL: user.AvatarURL}
+	}
+	for i := range items {
+		items[i].User = byID[items[i].UserID]
+	}
+	return nil
+}
+
 // GetTiers handles GET /tiers - get all public tiers or user's tiers
 // @Summary Get all tiers
-// @Description Get list of tiers with optional filters (platform, user_id, sort)
+// @Description Get list of approved tiers with optional filters (platform, user_id, sort). Items are TierListItem summaries with the description cut to 200 characters; GET /tiers/{id} returns the full tier
 // @Tags tiers
 // @Accept json
 // @Produce json
-// @Param platform query string false "Filter by platform name"
-// @Param user_id query int false "Filter by user ID"
-// @Param sort query string false "Sort order: 'recent' or by upvotes (default)"
+// @Param platform query []string false "Filter by platform name; repeat to match any of several" collectionFormat(multi)
+// @Param user_id query int false "Filter by user ID; private tiers are included only for the caller's own ID"
+// @Param github_login query string false "Filter by the submitter's GitHub login (case-insensitive)"
+// @Param my query bool false "List the caller's own tiers, private ones included; requires authentication"
+// @Param pricing_model query string false "Filter by pricing model" Enums(free-forever, free-trial, freemium)
+// @Param category query string false "Filter by service category" Enums(compute, database, storage, cdn, email, queue, cache, monitoring, other)
+// @Param geo query string false "Only tiers available in this ISO 3166-1 alpha-2 country (unrestricted tiers included)"
+// @Param egress_free query bool false "Filter by whether outbound traffic is free"
+// @Param min_upvotes query int false "Only tiers with at least this many upvotes; ignored if not a number"
+// @Param filter query string false "URL-encoded JSON filter combining platform, category, pricing_model, egress_free, regions and min_upvotes"
+// @Param created_after query string false "Only tiers created at or after this RFC 3339 timestamp or YYYY-MM-DD date (midnight UTC)"
+// @Param created_before query string false "Only tiers created at or before this RFC 3339 timestamp or YYYY-MM-DD date (midnight UTC)"
+// @Param sort query string false "Sort order: 'recent', 'updated_recent' (recently edited first), 'popular' (most viewed), 'bookmarked' (most bookmarked), 'controversial', by upvotes (default), or comma separated fields such as 'upvotes,-created_at'"
 // @Param page query int false "Page number for pagination"
+// @Param per_page query int false "Tiers per page, 1 to MAX_PAGE_SIZE (default 20)"
+// @Param include_stats query bool false "Add platform_breakdown: the top 20 platforms by public approved tier count, regardless of the other filters"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {integer} X-Total-Count "Total number of matching tiers"
+// @Header 200 {string} Link "RFC 5988 first, prev, next and last page links"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /tiers [get]
 func GetTiers(w http.ResponseWriter, r *http.Request) {
@@ -81,36 +503,140 @@ func GetTiers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := database.DB.Model(&models.Tier{})
+	query := database.DB.Model(&models.Tier{}).Where("status = ?", models.TierStatusApproved)
+
+	// Filter by platform if provided; repeat the parameter to match any of several
+	var platforms []string
+	for _, p := range r.URL.Query()["platform"] {
+		if p = normalize.NormalizePlatform(p); p != "" {
+			platforms = append(platforms, p)
+		}
+	}
+	if len(platforms) > 1 {
+		query = query.Where("platform IN ?", platforms)
+	} else if len(platforms) == 1 {
+		query = query.Where("platform = ?", platforms[0])
+	}
+
+	// Filter by pricing model if provided
+	if pricingModel := r.URL.Query().Get("pricing_model"); pricingModel != "" {
+		query = query.Where("pricing_model = ?", pricingModel)
+	}
+
+	// Filter by category if provided
+	if category := r.URL.Query().Get("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
 
-	// Filter by platform if provided
-	platform := r.URL.Query().Get("platform")
-	if platform != "" {
-		query = query.Where("platform = ?", platform)
+	// Filter by availability in a country if provided; tiers without
+	// restrictions are available everywhere
+	if geo := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("geo"))); geo != "" {
+		query = query.Where("(geo_restrictions IS NULL OR geo_restrictions = '' OR NULLIF(geo_restrictions, '')::jsonb @> ?::jsonb)",
+			string(models.NewCountryList([]string{geo})))
+	}
+
+	// Filter by egress fees if provided
+	if egressFree, err := strconv.ParseBool(r.URL.Query().Get("egress_free")); err == nil {
+		query = query.Where("egress_free = ?", egressFree)
+	}
+
+	// Filter by minimum upvotes if provided; invalid values are ignored
+	if minUpvotes, err := strconv.Atoi(r.URL.Query().Get("min_upvotes")); err == nil {
+		query = query.Where("upvote_count >= ?", minUpvotes)
+	}
+
+	// Apply a combined filter expression if provided
+	if raw := r.URL.Query().Get("filter"); raw != "" {
+		filter, err := ParseFilter(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query = filter.apply(query)
 	}
 
-	// Filter by user_id if provided
-	userID := r.URL.Query().Get("user_id")
-	if userID != "" {
+	// Filter by the submitter's GitHub login if provided. GitHub logins are
+	// case-insensitive. A subquery rather than a join keeps the tiers columns
+	// used by the other filters and the sort unambiguous.
+	if login := strings.TrimSpace(r.URL.Query().Get("github_login")); login != "" {
+		query = query.Where("user_id IN (?)", database.DB.Model(&models.User{}).
+			Select("id").Where("LOWER(git_hub_login) = LOWER(?)", login))
+	}
+
+	// Filter by submission date if provided
+	createdAfter, err := parseTimeParam(r.URL.Query().Get("created_after"))
+	if err != nil {
+		http.Error(w, "created_after "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if createdAfter != nil {
+		query = query.Where("created_at >= ?", *createdAfter)
+	}
+	createdBefore, err := parseTimeParam(r.URL.Query().Get("created_before"))
+	if err != nil {
+		http.Error(w, "created_before "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if createdBefore != nil {
+		query = query.Where("created_at <= ?", *createdBefore)
+	}
+
+	// Filter by owner: my=true lists the caller's own tiers, user_id anyone's.
+	// Private tiers are only listed to their owner.
+	callerID, authenticated := currentUserID(r)
+	includePrivate := false
+	if my, _ := strconv.ParseBool(r.URL.Query().Get("my")); my {
+		if !authenticated {
+			http.Error(w, "Authentication required for my=true", http.StatusUnauthorized)
+			return
+		}
+		query = query.Where("user_id = ?", callerID)
+		includePrivate = true
+	} else if userID := r.URL.Query().Get("user_id"); userID != "" {
 		uid, err := strconv.ParseUint(userID, 10, 32)
 		if err != nil {
 			log.WithError(err).WithField("user_id", userID).Warn("Invalid user_id parameter")
 		} else {
 			query = query.Where("user_id = ?", uid)
+			includePrivate = authenticated && uint(uid) == callerID
 		}
 	}
 
-	if userID == "" {
-		// If no user_id, only show public tiers
+	if !includePrivate {
 		query = query.Where("is_public = ?", true)
 	}
 
-	// Sort by upvotes by default
+	// Count all matching tiers before pagination is applied
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		log.WithError(err).Error("Failed to count tiers")
+		http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	// Featured tiers come first whatever the sort order, then sort by
+	// upvotes by default
+	query = query.Order("is_featured DESC")
 	sortBy := r.URL.Query().Get("sort")
-	if sortBy == "recent" {
+	switch sortBy {
+	case "recent":
 		query = query.Order("created_at DESC")
-	} else {
-		query = query.Order("upvote_count DESC, created_at DESC")
+	case "updated_recent":
+		query = query.Order("updated_at DESC")
+	case "popular":
+		query = query.Order("view_count DESC, created_at DESC")
+	case "bookmarked":
+		query = query.Order("bookmarks_count DESC, created_at DESC")
+	case "controversial":
+		// Evenly split votes first, busier debates ahead of quieter ones
+		query = query.Order("ABS(upvote_count - downvote_count) ASC, (upvote_count + downvote_count) DESC, created_at DESC")
+	default:
+		if order := parseSortParam(sortBy); len(order) > 0 {
+			query = query.Order(strings.Join(order, ", "))
+		} else {
+			query = query.Order("upvote_count DESC, created_at DESC")
+		}
 	}
 
 	// Pagination
@@ -118,35 +644,82 @@ func GetTiers(w http.ResponseWriter, r *http.Request) {
 	if err != nil || page < 1 {
 		page = 1
 	}
-	pageSize := 20
+	pageSize, err := parsePerPage(r.URL.Query().Get("per_page"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	offset := (page - 1) * pageSize
 
-	var tiers []models.Tier
-	if err := query.Preload("User").Limit(pageSize).Offset(offset).Find(&tiers).Error; err != nil {
+	// Only the columns a list shows; GET /tiers/{id} has the rest
+	items := []TierListItem{}
+	if err := query.Select(tierListColumns).Limit(pageSize).Offset(offset).Scan(&items).Error; err != nil {
 		log.WithError(err).Error("Failed to fetch tiers")
 		http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
 		return
 	}
+	if err := attachListUsers(items); err != nil {
+		log.WithError(err).Error("Failed to fetch tier authors")
+		http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
+		return
+	}
 
-	log.WithField("count", len(tiers)).Info("Fetched tiers")
+	log.WithField("count", len(items)).Info("Fetched tiers")
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": tiers,
+	// The sidebar breakdown covers every visible tier, not just this page
+	// or filter, so it is a separate query
+	var breakdown []PlatformBreakdown
+	if includeStats, _ := strconv.ParseBool(r.URL.Query().Get("include_stats")); includeStats {
+		breakdown = []PlatformBreakdown{}
+		if err := topPlatforms().Scan(&breakdown).Error; err != nil {
+			log.WithError(err).Error("Failed to fetch platform breakdown")
+			http.Error(w, "Failed to fetch tiers", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Page links keep every other query parameter so filters carry over
+	linkQuery := r.URL.Query()
+	linkQuery.Del("page")
+	linkQuery.Del("per_page")
+	linkBase := r.URL.Path
+	if encoded := linkQuery.Encode(); encoded != "" {
+		linkBase += "?" + encoded
+	}
+	w.Header().Set("Link", buildLinkHeader(linkBase, page, pageSize, int(total)))
+	pages := totalPages(int(total), pageSize)
+
+	response := map[string]interface{}{
+		"data": items,
 		"page": page,
-	}); err != nil {
+		"meta": map[string]interface{}{
+			"total":       total,
+			"per_page":    pageSize,
+			"total_pages": pages,
+			"has_next":    page < pages,
+			"has_prev":    page > 1,
+		},
+	}
+	if breakdown != nil {
+		response["platform_breakdown"] = breakdown
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.WithError(err).Error("Failed to encode tiers response")
 	}
 }
 
 // GetTier handles GET /tiers/{id} - get a specific tier
 // @Summary Get a tier by ID
-// @Description Get detailed information about a specific tier
+// @Description Get detailed information about a specific tier, including its Markdown description rendered to HTML. Private and unapproved tiers are only returned to their owner and admins. Comments are not included; fetch them from GET /tiers/{id}/comments
 // @Tags tiers
 // @Accept json
 // @Produce json
 // @Param id path int true "Tier ID"
-// @Success 200 {object} models.Tier
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} TierDetail
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /tiers/{id} [get]
@@ -168,22 +741,47 @@ func GetTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var tier models.Tier
-	if err := database.DB.Preload("User").Preload("Comments.User").First(&tier, id).Error; err != nil {
-		log.WithError(err).Error("Failed to fetch tier")
+	tier, ok := cache.Tiers.Get(uint(id))
+	if !ok {
+		tier = &models.Tier{}
+		if err := database.DB.Preload("User").First(tier, id).Error; err != nil {
+			log.WithError(err).Error("Failed to fetch tier")
+			http.Error(w, "Tier not found", http.StatusNotFound)
+			return
+		}
+		cache.Tiers.Set(tier)
+	}
+	// Checked after the cache lookup since the cache holds every tier
+	if !canViewTier(r, tier) {
 		http.Error(w, "Tier not found", http.StatusNotFound)
 		return
 	}
+	views.Tiers.Record(tier.ID)
+
+	// Conditional GET: let clients reuse their cached copy if the tier is unchanged.
+	// Vote, comment and bookmark counters are bumped with UpdateColumn, which
+	// leaves updated_at alone, so they are part of the ETag as well.
+	etag := fmt.Sprintf(`"%d-%d-%d-%d-%d-%d"`, tier.ID, tier.UpdatedAt.Unix(),
+		tier.UpvoteCount, tier.DownvoteCount, tier.CommentCount, tier.BookmarksCount)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", tier.UpdatedAt.UTC().Format(http.TimeFormat))
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tier); err != nil {
+	if err := json.NewEncoder(w).Encode(TierDetail{
+		TierResponse:        newTierResponse(tier),
+		RenderedDescription: markdown.Render(tier.Description),
+	}); err != nil {
 		log.WithError(err).Error("Failed to encode tier response")
 	}
 }
 
 // UpdateTier handles PUT /tiers/{id} - update a tier
 // @Summary Update a tier
-// @Description Update an existing tier information
+// @Description Update an existing tier information (owner or admin only). id, user_id, created_at, status, rejection_reason, the counters, is_featured and featured_until cannot be changed here
 // @Tags tiers
 // @Accept json
 // @Produce json
@@ -191,7 +789,11 @@ func GetTier(w http.ResponseWriter, r *http.Request) {
 // @Param tier body models.Tier true "Tier update data"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string// @Security BearerAuth// @Router /tiers/{id} [put]
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id} [put]
 func UpdateTier(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -209,18 +811,85 @@ func UpdateTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var tier models.Tier
+	if err := database.DB.First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	if !canModify(r, tier.UserID) {
+		http.Error(w, "Only the tier owner or an admin can update this tier", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
 	var updates models.Tier
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+	if err := json.Unmarshal(body, &updates); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := database.DB.Model(&models.Tier{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+	// Updates skips zero values, so fields that may be reset to false, 0 or
+	// an empty list are written explicitly when present in the body
+	var present map[string]json.RawMessage
+	json.Unmarshal(body, &present)
+	explicit := map[string]interface{}{}
+	if _, ok := present["egress_free"]; ok {
+		explicit["egress_free"] = updates.EgressFree
+	}
+	if _, ok := present["egress_limit_gb"]; ok {
+		explicit["egress_limit_gb"] = updates.EgressLimitGB
+	}
+	if _, ok := present["geo_restrictions"]; ok {
+		explicit["geo_restrictions"] = string(updates.GeoRestrictions)
+	}
+
+	if err := sanitizeTierFields(&updates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updates.Platform = normalize.NormalizePlatform(updates.Platform)
+
+	if err := validateTierFields(&updates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Status changes go through the admin review endpoints, featuring through
+	// the promotion endpoint, and counters are maintained by the server.
+	// Ownership and identity never change through an update.
+	updates.ID, updates.UserID, updates.CreatedAt = 0, 0, time.Time{}
+	updates.Status, updates.RejectionReason = "", ""
+	updates.IsFeatured, updates.FeaturedUntil = false, nil
+	updates.UpvoteCount, updates.DownvoteCount, updates.CommentCount = 0, 0, 0
+	updates.ViewCount, updates.ClickCount, updates.BookmarksCount = 0, 0, 0
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Tier{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if len(explicit) > 0 {
+			return tx.Model(&models.Tier{}).Where("id = ?", id).Updates(explicit).Error
+		}
+		return nil
+	})
+	if isUniqueViolation(err, "idx_unique_user_platform_name") {
+		http.Error(w, duplicateTierMessage, http.StatusConflict)
+		return
+	}
+	if err != nil {
 		log.WithError(err).Error("Failed to update tier")
 		http.Error(w, "Failed to update tier", http.StatusInternalServerError)
 		return
 	}
 
+	cache.Tiers.Invalidate(uint(id))
+
 	log.WithField("tier_id", id).Info("Tier updated")
 
 	w.Header().Set("Content-Type", "application/json")
@@ -231,14 +900,18 @@ func UpdateTier(w http.ResponseWriter, r *http.Request) {
 
 // DeleteTier handles DELETE /tiers/{id} - delete a tier
 // @Summary Delete a tier
-// @Description Delete a tier from the database
+// @Description Delete a tier from the database (owner or admin only)
 // @Tags tiers
 // @Accept json
 // @Produce json
 // @Param id path int true "Tier ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string// @Security BearerAuth// @Router /tiers/{id} [delete]
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id} [delete]
 func DeleteTier(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -256,12 +929,37 @@ func DeleteTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.Tier{}, id).Error; err != nil {
+	var tier models.Tier
+	if err := database.DB.First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	if !canModify(r, tier.UserID) {
+		http.Error(w, "Only the tier owner or an admin can delete this tier", http.StatusForbidden)
+		return
+	}
+
+	// Votes and comments go with the tier so nothing counts rows of a deleted tier.
+	// They share the tier's deleted_at so RestoreTier can bring back exactly these rows.
+	deletedAt := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Vote{}).Where("tier_id = ?", id).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Comment{}).Where("tier_id = ?", id).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&tier).UpdateColumn("deleted_at", deletedAt).Error
+	})
+	if err != nil {
 		log.WithError(err).Error("Failed to delete tier")
 		http.Error(w, "Failed to delete tier", http.StatusInternalServerError)
 		return
 	}
 
+	cache.Tiers.Invalidate(uint(id))
+
 	log.WithField("tier_id", id).Info("Tier deleted")
 
 	w.Header().Set("Content-Type", "application/json")
@@ -269,3 +967,180 @@ func DeleteTier(w http.ResponseWriter, r *http.Request) {
 		log.WithError(err).Error("Failed to encode response")
 	}
 }
+
+// UpdateTierScreenshot handles POST /tiers/{id}/screenshot - set a tier's screenshot URL
+// @Summary Update a tier screenshot
+// @Description Set the screenshot URL of a tier (owner or admin only). The URL must use https
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Param screenshot body ScreenshotRequest true "Screenshot URL"
+// @Success 200 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id}/screenshot [post]
+func UpdateTierScreenshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 3 {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ScreenshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.URL == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateScreenshotURL(req.URL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.First(&tier, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	if !canModify(r, tier.UserID) {
+		http.Error(w, "Only the tier owner or an admin can update the screenshot", http.StatusForbidden)
+		return
+	}
+
+	if err := database.DB.Model(&tier).UpdateColumn("screenshot_url", req.URL).Error; err != nil {
+		log.WithError(err).Error("Failed to update tier screenshot")
+		http.Error(w, "Failed to update tier screenshot", http.StatusInternalServerError)
+		return
+	}
+	tier.ScreenshotURL = req.URL
+	cache.Tiers.Invalidate(tier.ID)
+
+	log.WithField("tier_id", tier.ID).Info("Tier screenshot updated")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTierResponse(&tier)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+	}
+}
+
+// CloneTier handles POST /tiers/{id}/clone - create a draft copy of a tier
+// @Summary Clone a tier
+// @Description Create a draft copy of a tier owned by the caller. Private and unapproved tiers can only be cloned by their owner
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Success 201 {object} TierResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id}/clone [post]
+func CloneTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 3 {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var source models.Tier
+	if err := database.DB.First(&source, id).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	// Private and unreviewed tiers are hidden from everyone but their owner
+	if !canViewTier(r, &source) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	clone := models.Tier{
+		UserID:          userID,
+		Platform:        source.Platform,
+		Name:            source.Name + " (copy)",
+		Description:     source.Description,
+		IsPublic:        source.IsPublic,
+		Status:          models.TierStatusDraft,
+		CPULimit:        source.CPULimit,
+		MemoryLimit:     source.MemoryLimit,
+		StorageLimit:    source.StorageLimit,
+		BandwidthLimit:  source.BandwidthLimit,
+		MonthlyHours:    source.MonthlyHours,
+		PricingModel:    source.PricingModel,
+		Category:        source.Category,
+		GeoRestrictions: source.GeoRestrictions,
+		EgressFree:      source.EgressFree,
+		EgressLimitGB:   source.EgressLimitGB,
+		URL:             source.URL,
+		ScreenshotURL:   source.ScreenshotURL,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&clone).Error; err != nil {
+			return err
+		}
+		// is_public defaults to true in the database, so a false value must be written explicitly
+		if !clone.IsPublic {
+			return tx.Model(&clone).UpdateColumn("is_public", false).Error
+		}
+		return nil
+	})
+	if isUniqueViolation(err, "idx_unique_user_platform_name") {
+		http.Error(w, duplicateTierMessage, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to clone tier")
+		http.Error(w, "Failed to clone tier", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"source_tier_id": source.ID,
+		"tier_id":        clone.ID,
+		"user_id":        userID,
+	}).Info("Tier cloned")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newTierResponse(&clone)); err != nil {
+		log.WithError(err).Error("Failed to encode tier response")
+	}
+}
@@ -1,15 +1,48 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"freestealer/auth"
 	"freestealer/database"
 	"freestealer/models"
+	"freestealer/username"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// UserStats represents a user's contribution statistics
+type UserStats struct {
+	TiersCreated           int64     `json:"tiers_created"`
+	TotalUpvotesReceived   int64     `json:"total_upvotes_received"`
+	TotalDownvotesReceived int64     `json:"total_downvotes_received"`
+	CommentsWritten        int64     `json:"comments_written"`
+	VotesCast              int64     `json:"votes_cast"`
+	BookmarksCount         int64     `json:"bookmarks_count"` // tiers the user bookmarked
+	MemberSince            time.Time `json:"member_since"`
+}
+
+// userStatsQuery gathers all contribution counters for a user in one round trip
+const userStatsQuery = `
+SELECT
+	(SELECT COUNT(*) FROM tiers WHERE user_id = @id AND deleted_at IS NULL) AS tiers_created,
+	(SELECT COALESCE(SUM(upvote_count), 0) FROM tiers WHERE user_id = @id AND deleted_at IS NULL) AS total_upvotes_received,
+	(SELECT COALESCE(SUM(downvote_count), 0) FROM tiers WHERE user_id = @id AND deleted_at IS NULL) AS total_downvotes_received,
+	(SELECT COUNT(*) FROM comments WHERE user_id = @id AND deleted_at IS NULL) AS comments_written,
+	(SELECT COUNT(*) FROM votes WHERE user_id = @id AND deleted_at IS NULL) AS votes_cast,
+	(SELECT COUNT(*) FROM bookmarks WHERE user_id = @id) AS bookmarks_count,
+	u.created_at AS member_since
+FROM users u
+WHERE u.id = @id AND u.deleted_at IS NULL`
+
 // CreateUser handles POST /users - create a new user
 // @Summary Create a new user
 // @Description Register a new user with username and email
@@ -40,6 +73,11 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := username.ValidateUsername(user.Username); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Create user
 	if err := database.DB.Create(&user).Error; err != nil {
 		log.WithError(err).Error("Failed to create user")
@@ -87,3 +125,396 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 		log.WithError(err).Error("Failed to encode users response")
 	}
 }
+
+// UserProfileDetail is a user's public profile with their collections
+type UserProfileDetail struct {
+	UserProfile
+	Collections []models.Collection `json:"collections"`
+}
+
+// GetUser handles GET /users/{id} - get a user's public profile
+// @Summary Get a user
+// @Description Get the public profile of a user with their collections, newest first. Private collections are only included for the user themselves and admins. Email, role and GitHub ID are not included
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} UserProfileDetail
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [get]
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 3 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	query := database.DB.Where("user_id = ?", user.ID)
+	if !canModify(r, user.ID) {
+		query = query.Where("is_public = ?", true)
+	}
+	collections := []models.Collection{}
+	if err := query.Order("created_at DESC, id DESC").Find(&collections).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch user collections")
+		http.Error(w, "Failed to fetch user", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(UserProfileDetail{
+		UserProfile: newUserProfile(&user),
+		Collections: collections,
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode user response")
+	}
+}
+
+// maxBioLength matches the size of the users.bio column
+const maxBioLength = 500
+
+// UpdateUserRequest is the body of PUT /users/{id}. Omitted fields are left
+// unchanged; role and GitHub fields cannot be set here.
+type UpdateUserRequest struct {
+	Username *string `json:"username,omitempty" example:"john_doe"`
+	Email    *string `json:"email,omitempty" example:"john@example.com"`
+	Bio      *string `json:"bio,omitempty" example:"Collects free tiers"`
+}
+
+// UpdateUser handles PUT /users/{id} - update a user's username, email or bio
+// @Summary Update a user
+// @Description Update the username, email or bio of a user. Only the user themselves or an admin may update it. The email must be a valid address and is subject to ALLOWED_EMAIL_DOMAINS and BLOCKED_EMAIL_DOMAINS
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param user body UpdateUserRequest true "Fields to change"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [put]
+func UpdateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 3 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := currentUserID(r); !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !canModify(r, uint(id)) {
+		http.Error(w, "You can only update your own account", http.StatusForbidden)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Username != nil {
+		if err := username.ValidateUsername(*req.Username); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updates["username"] = *req.Username
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email == "" {
+			http.Error(w, "Email cannot be empty", http.StatusBadRequest)
+			return
+		}
+		// A bare address only; ParseAddress also accepts "Name <addr>"
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			http.Error(w, "Invalid email address", http.StatusBadRequest)
+			return
+		}
+		// The same domain rules as registration apply to a changed email
+		if status, message := auth.CheckEmailDomain(email); status != 0 {
+			http.Error(w, message, status)
+			return
+		}
+		updates["email"] = email
+	}
+	if req.Bio != nil {
+		if len(*req.Bio) > maxBioLength {
+			http.Error(w, "Bio must be at most 500 characters", http.StatusBadRequest)
+			return
+		}
+		updates["bio"] = *req.Bio
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if len(updates) > 0 {
+		err := database.DB.Model(&user).Updates(updates).Error
+		if isUniqueViolation(err, "idx_users_username") || isUniqueViolation(err, "idx_users_email") {
+			http.Error(w, "Username or email already taken", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.WithError(err).Error("Failed to update user")
+			http.Error(w, "Failed to update user", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	log.WithField("user_id", user.ID).Info("User updated")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.WithError(err).Error("Failed to encode user response")
+	}
+}
+
+// DeleteUser handles DELETE /users/{id} - delete a user
+// @Summary Delete a user
+// @Description Soft-delete a user account and log out all of its sessions. Only the user themselves or an admin may delete it
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [delete]
+func DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 3 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := currentUserID(r); !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !canModify(r, uint(id)) {
+		http.Error(w, "You can only delete your own account", http.StatusForbidden)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := database.DB.Delete(&user).Error; err != nil {
+		log.WithError(err).Error("Failed to delete user")
+		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
+		return
+	}
+
+	// Tokens already issued to the user must stop working as well
+	if err := auth.RevokeAllSessions(user.ID); err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("Failed to revoke sessions")
+		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithField("user_id", user.ID).Info("User deleted")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "User deleted successfully"}); err != nil {
+		log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// maxAvatarURLLength matches the size of the users.avatar_url column
+const maxAvatarURLLength = 500
+
+// UpdateAvatarRequest is the body of PATCH /users/{id}/avatar
+type UpdateAvatarRequest struct {
+	AvatarURL string `json:"avatar_url" example:"https://example.com/avatar.png"`
+}
+
+// validateAvatarURL checks that rawURL is an absolute https URL that fits the column
+func validateAvatarURL(rawURL string) error {
+	if !strings.HasPrefix(rawURL, "https://") {
+		return errors.New("avatar_url must start with https://")
+	}
+	if len(rawURL) > maxAvatarURLLength {
+		return errors.New("avatar_url must be at most 500 characters")
+	}
+	if u, err := url.Parse(rawURL); err != nil || u.Host == "" {
+		return errors.New("avatar_url is not a valid URL")
+	}
+	return nil
+}
+
+// UpdateUserAvatar handles PATCH /users/{id}/avatar - change only a user's avatar URL
+// @Summary Update a user's avatar
+// @Description Set the avatar URL of a user. Only the user themselves or an admin may change it; no other field is touched
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param avatar body UpdateAvatarRequest true "New avatar URL, must start with https://"
+// @Success 200 {object} UserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id}/avatar [patch]
+func UpdateUserAvatar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := currentUserID(r); !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !canModify(r, uint(id)) {
+		http.Error(w, "You can only change your own avatar", http.StatusForbidden)
+		return
+	}
+
+	var req UpdateAvatarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.AvatarURL = strings.TrimSpace(req.AvatarURL)
+	if err := validateAvatarURL(req.AvatarURL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := database.DB.Model(&user).Update("avatar_url", req.AvatarURL).Error; err != nil {
+		log.WithError(err).Error("Failed to update avatar")
+		http.Error(w, "Failed to update avatar", http.StatusInternalServerError)
+		return
+	}
+
+	log.WithField("user_id", user.ID).Info("User avatar updated")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserProfile(&user)); err != nil {
+		log.WithError(err).Error("Failed to encode user response")
+	}
+}
+
+// GetUserStats handles GET /users/{id}/stats - get a user's contribution statistics
+// @Summary Get user statistics
+// @Description Get contribution statistics for a user (tiers, votes received, comments, votes cast)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} UserStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/stats [get]
+func GetUserStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 3 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var stats UserStats
+	result := database.DB.Raw(userStatsQuery, sql.Named("id", id)).Scan(&stats)
+	if result.Error != nil {
+		log.WithError(result.Error).Error("Failed to fetch user stats")
+		http.Error(w, "Failed to fetch user stats", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.WithError(err).Error("Failed to encode user stats response")
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"freestealer/cache"
+	"freestealer/database"
+	"freestealer/models"
+	"freestealer/views"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recordClick counts a visit to a tier's official URL and logs it for analytics
+func recordClick(tierID uint, referrer, userAgent string) {
+	views.Clicks.Record(tierID)
+	log.WithFields(log.Fields{
+		"event":      "ClickEvent",
+		"tier_id":    tierID,
+		"referrer":   referrer,
+		"user_agent": userAgent,
+	}).Info("Tier URL visited")
+}
+
+// VisitTier handles GET /tiers/{id}/visit - redirect to a tier's official URL
+// @Summary Visit a tier's URL
+// @Description Redirect to the tier's official URL, counting the visit in click_count. Private and unapproved tiers are only followed for their owner and admins. Counts are written in batches and may lag behind by up to 30 seconds
+// @Tags tiers
+// @Param id path int true "Tier ID"
+// @Success 302 "Redirect to the tier URL"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tiers/{id}/visit [get]
+func VisitTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	tier, ok := cache.Tiers.Get(uint(id))
+	if !ok {
+		tier = &models.Tier{}
+		if err := database.DB.Preload("User").First(tier, id).Error; err != nil {
+			http.Error(w, "Tier not found", http.StatusNotFound)
+			return
+		}
+		cache.Tiers.Set(tier)
+	}
+	// Unreviewed tiers must not turn the API into an open redirect
+	if !canViewTier(r, tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	// Only http(s) URLs are followed, in case older rows predate URL validation
+	target, err := url.Parse(tier.URL)
+	if tier.URL == "" || err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		http.Error(w, "Tier has no URL", http.StatusNotFound)
+		return
+	}
+
+	// Counting and logging happen off the request path to keep the redirect fast
+	go recordClick(tier.ID, r.Referer(), r.UserAgent())
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// VoteEvent carries a tier's vote counts after a vote changed them
+type VoteEvent struct {
+	UpvoteCount   int `json:"upvote_count"`
+	DownvoteCount int `json:"downvote_count"`
+}
+
+// voteEventBuffer is how many events a slow subscriber may fall behind before
+// further events are dropped for it
+const voteEventBuffer = 8
+
+// VoteBroadcaster fans vote count changes out to the subscribers of each tier
+type VoteBroadcaster struct {
+	// subscribers maps each chan VoteEvent to the tier ID it watches
+	subscribers sync.Map
+}
+
+// Votes is the broadcaster VoteTier publishes to
+var Votes = &VoteBroadcaster{}
+
+// Subscribe returns a channel receiving events for tierID and a function that
+// unsubscribes it. The channel is never closed.
+func (b *VoteBroadcaster) Subscribe(tierID uint) (<-chan VoteEvent, func()) {
+	ch := make(chan VoteEvent, voteEventBuffer)
+	b.subscribers.Store(ch, tierID)
+	return ch, func() { b.subscribers.Delete(ch) }
+}
+
+// Publish sends event to every subscriber of tierID without blocking
+func (b *VoteBroadcaster) Publish(tierID uint, event VoteEvent) {
+	b.subscribers.Range(func(key, value interface{}) bool {
+		if value.(uint) != tierID {
+			return true
+		}
+		select {
+		case key.(chan VoteEvent) <- event:
+		default:
+			log.WithField("tier_id", tierID).Warn("Dropped vote event for slow subscriber")
+		}
+		return true
+	})
+}
+
+// publishVoteCounts broadcasts the current vote counts of a tier
+func publishVoteCounts(tierID uint) {
+	var tier models.Tier
+	if err := database.DB.Select("upvote_count", "downvote_count").First(&tier, tierID).Error; err != nil {
+		log.WithError(err).WithField("tier_id", tierID).Error("Failed to load vote counts for broadcast")
+		return
+	}
+	Votes.Publish(tierID, VoteEvent{UpvoteCount: tier.UpvoteCount, DownvoteCount: tier.DownvoteCount})
+}
+
+// StreamTierVotes handles GET /ws/tiers/{id}/votes - live vote counts over WebSocket
+// @Summary Stream live vote counts
+// @Description Upgrades to a WebSocket and sends {"upvote_count": N, "downvote_count": N} whenever a vote on the tier changes
+// @Tags votes
+// @Param id path int true "Tier ID"
+// @Success 101 {object} VoteEvent
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 405 {object} map[string]string
+// @Security BearerAuth
+// @Router /ws/tiers/{id}/votes [get]
+func StreamTierVotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path is /ws/tiers/{id}/votes
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 5 || parts[4] != "votes" {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+
+	tierID, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	// Hidden tiers must not leak their existence through the stream
+	var tier models.Tier
+	if err := database.DB.First(&tier, tierID).Error; err != nil || !canViewTier(r, &tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	websocket.Handler(func(ws *websocket.Conn) {
+		streamVotes(ws, uint(tierID))
+	}).ServeHTTP(w, r)
+}
+
+// streamVotes writes vote events to ws until the client disconnects
+func streamVotes(ws *websocket.Conn, tierID uint) {
+	defer ws.Close()
+
+	// The connection outlives the server's request write timeout
+	ws.SetDeadline(time.Time{})
+
+	events, unsubscribe := Votes.Subscribe(tierID)
+	defer unsubscribe()
+
+	log.WithField("tier_id", tierID).Debug("Vote stream opened")
+
+	// Clients only listen; a failed read means they went away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				log.WithError(err).WithField("tier_id", tierID).Debug("Vote stream send failed")
+				return
+			}
+		case <-closed:
+			log.WithField("tier_id", tierID).Debug("Vote stream closed")
+			return
+		}
+	}
+}
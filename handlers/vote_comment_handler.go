@@ -2,27 +2,32 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
+	"freestealer/cache"
 	"freestealer/database"
 	"freestealer/models"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // VoteRequest represents a vote request
 type VoteRequest struct {
-	UserID   uint `json:"user_id"`
+	UserID   uint `json:"user_id"` // ignored; the voter is the authenticated user
 	TierID   uint `json:"tier_id"`
 	VoteType int8 `json:"vote_type"` // 1 for upvote, -1 for downvote
 }
 
 // VoteTier handles POST /votes - create or update a vote
 // @Summary Vote on a tier
-// @Description Upvote or downvote a tier. Toggle off if same vote, change if different
+// @Description Upvote or downvote a tier as the authenticated user. Toggle off if same vote, change if different. Owners cannot vote on their own tiers
 // @Tags votes
 // @Accept json
 // @Produce json
@@ -30,6 +35,9 @@ type VoteRequest struct {
 // @Success 200 {object} models.Vote
 // @Success 201 {object} models.Vote
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "self_vote_not_allowed"
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /votes [post]
@@ -39,18 +47,48 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	var req VoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	// Votes are always cast by the caller, whatever user_id the body names
+	req.UserID = userID
+
 	// Validate vote type
 	if req.VoteType != 1 && req.VoteType != -1 {
 		http.Error(w, "Vote type must be 1 (upvote) or -1 (downvote)", http.StatusBadRequest)
 		return
 	}
 
+	// Hidden tiers cannot be voted on, and owners cannot vote their own
+	// tiers up the rankings
+	var owner models.Tier
+	if err := database.DB.Select("user_id", "is_public", "status").First(&owner, req.TierID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			http.Error(w, "Tier not found", http.StatusNotFound)
+			return
+		}
+		log.WithError(err).Error("Failed to fetch tier for voting")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !canViewTier(r, &owner) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+	if owner.UserID == req.UserID {
+		writeError(w, http.StatusForbidden, ErrCodeSelfVote, "You cannot vote on your own tier")
+		return
+	}
+
 	// Start a transaction
 	tx := database.DB.Begin()
 	defer func() {
@@ -59,9 +97,27 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
+	// Lock the tier row so concurrent votes on it are decided one at a time.
+	// Locking the vote row alone is not enough: when no vote exists yet there
+	// is nothing to lock and both requests would insert. The unique index on
+	// (user_id, tier_id) remains as a final safety net.
+	var tier models.Tier
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&tier, req.TierID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			http.Error(w, "Tier not found", http.StatusNotFound)
+			return
+		}
+		log.WithError(err).Error("Failed to lock tier for voting")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	// Check if vote already exists
 	var existingVote models.Vote
-	err := tx.Where("user_id = ? AND tier_id = ?", req.UserID, req.TierID).First(&existingVote).Error
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND tier_id = ?", req.UserID, req.TierID).
+		First(&existingVote).Error
 
 	if err == gorm.ErrRecordNotFound {
 		// Create new vote
@@ -85,6 +141,8 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		}
 
 		tx.Commit()
+		cache.Tiers.Invalidate(req.TierID)
+		publishVoteCounts(req.TierID)
 
 		log.WithFields(log.Fields{
 			"user_id": req.UserID,
@@ -92,6 +150,10 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 			"type":    req.VoteType,
 		}).Info("Vote created")
 
+		if req.VoteType == 1 {
+			notifyTierOwner(req.TierID, req.UserID, models.NotificationTierUpvoted, "tier", req.TierID)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(vote); err != nil {
@@ -127,6 +189,8 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		}
 
 		tx.Commit()
+		cache.Tiers.Invalidate(req.TierID)
+		publishVoteCounts(req.TierID)
 
 		log.WithFields(log.Fields{
 			"user_id": req.UserID,
@@ -148,16 +212,28 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update tier vote counts (decrement old, increment new)
-	if oldVoteType == 1 {
-		tx.Model(&models.Tier{}).Where("id = ?", req.TierID).UpdateColumn("upvote_count", gorm.Expr("upvote_count - 1"))
-		tx.Model(&models.Tier{}).Where("id = ?", req.TierID).UpdateColumn("downvote_count", gorm.Expr("downvote_count + 1"))
-	} else {
-		tx.Model(&models.Tier{}).Where("id = ?", req.TierID).UpdateColumn("downvote_count", gorm.Expr("downvote_count - 1"))
-		tx.Model(&models.Tier{}).Where("id = ?", req.TierID).UpdateColumn("upvote_count", gorm.Expr("upvote_count + 1"))
+	// Update tier vote counts (decrement old, increment new) in one statement
+	// so the two counts never disagree
+	counts := map[string]interface{}{
+		"upvote_count":   gorm.Expr("upvote_count - 1"),
+		"downvote_count": gorm.Expr("downvote_count + 1"),
+	}
+	if oldVoteType != 1 {
+		counts = map[string]interface{}{
+			"upvote_count":   gorm.Expr("upvote_count + 1"),
+			"downvote_count": gorm.Expr("downvote_count - 1"),
+		}
+	}
+	if err := tx.Model(&models.Tier{}).Where("id = ?", req.TierID).UpdateColumns(counts).Error; err != nil {
+		tx.Rollback()
+		log.WithError(err).Error("Failed to update tier vote counts")
+		http.Error(w, "Failed to update vote", http.StatusInternalServerError)
+		return
 	}
 
 	tx.Commit()
+	cache.Tiers.Invalidate(req.TierID)
+	publishVoteCounts(req.TierID)
 
 	log.WithFields(log.Fields{
 		"user_id":  req.UserID,
@@ -166,6 +242,10 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 		"old_type": oldVoteType,
 	}).Info("Vote updated")
 
+	if req.VoteType == 1 {
+		notifyTierOwner(req.TierID, req.UserID, models.NotificationTierUpvoted, "tier", req.TierID)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(existingVote); err != nil {
 		log.WithError(err).Error("Failed to encode vote response")
@@ -174,13 +254,15 @@ func VoteTier(w http.ResponseWriter, r *http.Request) {
 
 // CreateComment handles POST /comments - create a new comment
 // @Summary Create a comment
-// @Description Add a comment to a tier (max 100 characters)
+// @Description Add a comment to a tier (max 100 characters) as the authenticated user; user_id in the body is ignored
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param comment body models.Comment true "Comment data"
 // @Success 201 {object} models.Comment
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /comments [post]
@@ -190,18 +272,50 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := currentUserID(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	var comment models.Comment
 	if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	// Validate content length (max 100 characters)
-	if comment.Content == "" || len(comment.Content) > 100 {
+	// Comments are always posted by the caller, whatever user_id the body names
+	comment.ID = 0
+	comment.UserID = userID
+
+	// Validate content length (max 100 characters, counted as runes)
+	if comment.Content == "" || utf8.RuneCountInString(comment.Content) > 100 {
 		http.Error(w, "Comment must be between 1 and 100 characters", http.StatusBadRequest)
 		return
 	}
 
+	// PostgreSQL rejects invalid UTF-8 and NUL bytes in text columns
+	if !utf8.ValidString(comment.Content) || strings.ContainsRune(comment.Content, 0) {
+		http.Error(w, "Comment contains invalid characters", http.StatusBadRequest)
+		return
+	}
+
+	// Only tiers the caller can see accept comments
+	var tier models.Tier
+	if err := database.DB.Select("user_id", "is_public", "status").First(&tier, comment.TierID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			http.Error(w, "Tier not found", http.StatusNotFound)
+			return
+		}
+		log.WithError(err).Error("Failed to fetch tier for comment")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !canViewTier(r, &tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
 	// Start transaction
 	tx := database.DB.Begin()
 
@@ -224,6 +338,7 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tx.Commit()
+	cache.Tiers.Invalidate(comment.TierID)
 
 	log.WithFields(log.Fields{
 		"comment_id": comment.ID,
@@ -231,6 +346,8 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 		"user_id":    comment.UserID,
 	}).Info("Comment created")
 
+	notifyTierOwner(comment.TierID, comment.UserID, models.NotificationCommentAdded, "comment", comment.ID)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(comment); err != nil {
@@ -238,16 +355,36 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// commentSortOrders is the allowlist of comment sort parameters. Comments
+// cannot be edited, so only the creation time is offered.
+var commentSortOrders = map[string]string{
+	"newest": "created_at DESC",
+	"oldest": "created_at ASC",
+}
+
+// tierCommentsQuery selects the comments of a tier with their authors, newest
+// first unless sortBy names another order in commentSortOrders
+func tierCommentsQuery(tierID uint64, sortBy string) *gorm.DB {
+	order, ok := commentSortOrders[sortBy]
+	if !ok {
+		order = commentSortOrders["newest"]
+	}
+	return database.DB.Model(&models.Comment{}).Where("tier_id = ?", tierID).Preload("User").Order(order)
+}
+
 // GetComments handles GET /comments?tier_id={id} - get comments for a tier
 // @Summary Get comments for a tier
-// @Description Get all comments for a specific tier
+// @Description Get all comments for a specific tier. GET /tiers/{id}/comments returns them paginated
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param tier_id query int true "Tier ID"
+// @Param sort query string false "Sort order" Enums(newest, oldest)
 // @Success 200 {array} models.Comment
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /comments [get]
 func GetComments(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -267,8 +404,14 @@ func GetComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var tier models.Tier
+	if err := database.DB.Select("user_id", "is_public", "status").First(&tier, tid).Error; err != nil || !canViewTier(r, &tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
 	var comments []models.Comment
-	if err := database.DB.Where("tier_id = ?", tid).Preload("User").Order("created_at DESC").Find(&comments).Error; err != nil {
+	if err := tierCommentsQuery(tid, r.URL.Query().Get("sort")).Find(&comments).Error; err != nil {
 		log.WithError(err).Error("Failed to fetch comments")
 		http.Error(w, "Failed to fetch comments", http.StatusInternalServerError)
 		return
@@ -280,17 +423,109 @@ func GetComments(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetTierComments handles GET /tiers/{id}/comments - get a page of a tier's comments
+// @Summary Get paginated comments for a tier
+// @Description Get the comments of a tier one page at a time. GET /tiers/{id} does not include comments
+// @Tags comments
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Param page query int false "Page number"
+// @Param per_page query int false "Comments per page, 1 to MAX_PAGE_SIZE (default 20)"
+// @Param sort query string false "Sort order" Enums(newest, oldest)
+// @Success 200 {object} map[string]interface{}
+// @Header 200 {integer} X-Total-Count "Total number of comments on the tier"
+// @Header 200 {string} Link "RFC 5988 first, prev, next and last page links"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id}/comments [get]
+func GetTierComments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path is /tiers/{id}/comments
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 || parts[3] != "comments" {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+	tid, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	var tier models.Tier
+	if err := database.DB.Select("user_id", "is_public", "status").First(&tier, tid).Error; err != nil || !canViewTier(r, &tier) {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := parsePerPage(r.URL.Query().Get("per_page"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var total int64
+	if err := database.DB.Model(&models.Comment{}).Where("tier_id = ?", tid).Count(&total).Error; err != nil {
+		log.WithError(err).Error("Failed to count comments")
+		http.Error(w, "Failed to fetch comments", http.StatusInternalServerError)
+		return
+	}
+
+	var comments []models.Comment
+	if err := tierCommentsQuery(tid, r.URL.Query().Get("sort")).
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&comments).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch comments")
+		http.Error(w, "Failed to fetch comments", http.StatusInternalServerError)
+		return
+	}
+
+	linkBase := r.URL.Path
+	if sortBy := r.URL.Query().Get("sort"); sortBy != "" {
+		linkBase += "?" + url.Values{"sort": {sortBy}}.Encode()
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	w.Header().Set("Link", buildLinkHeader(linkBase, page, perPage, int(total)))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": comments,
+		"page": page,
+		"meta": map[string]interface{}{
+			"total":    total,
+			"per_page": perPage,
+		},
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode comments response")
+	}
+}
+
 // DeleteComment handles DELETE /comments/{id} - delete a comment
 // @Summary Delete a comment
-// @Description Delete a comment from a tier
+// @Description Delete a comment from a tier. Allowed for the comment's author, the tier's owner and admins.
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param id path int true "Comment ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string// @Security BearerAuth// @Router /comments/{id} [delete]
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /comments/{id} [delete]
 func DeleteComment(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -315,6 +550,20 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The author, the owner of the tier it was posted on, or an admin may delete it
+	if !canModify(r, comment.UserID) {
+		var tier models.Tier
+		if err := database.DB.Select("user_id").First(&tier, comment.TierID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WithError(err).Error("Failed to fetch tier")
+			http.Error(w, "Failed to delete comment", http.StatusInternalServerError)
+			return
+		}
+		if !canModify(r, tier.UserID) {
+			http.Error(w, "Only the comment author, the tier owner or an admin can delete this comment", http.StatusForbidden)
+			return
+		}
+	}
+
 	// Start transaction
 	tx := database.DB.Begin()
 
@@ -337,6 +586,7 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tx.Commit()
+	cache.Tiers.Invalidate(comment.TierID)
 
 	log.WithField("comment_id", id).Info("Comment deleted")
 
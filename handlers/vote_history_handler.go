@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VoteHistoryPoint is a tier's vote counts at one point in time
+type VoteHistoryPoint struct {
+	Upvotes   int       `json:"upvotes"`
+	Downvotes int       `json:"downvotes"`
+	At        time.Time `json:"at"`
+}
+
+// GetVoteHistory handles GET /tiers/{id}/vote-history - get a tier's vote counts over time
+// @Summary Get a tier's vote history
+// @Description Get the hourly snapshots of a tier's upvote and downvote counts, oldest first. Only hours in which the tier had at least one vote are recorded
+// @Tags tiers
+// @Produce json
+// @Param id path int true "Tier ID"
+// @Param from query string false "Only snapshots taken at or after this RFC 3339 timestamp or YYYY-MM-DD date (midnight UTC)"
+// @Param to query string false "Only snapshots taken at or before this RFC 3339 timestamp or YYYY-MM-DD date (midnight UTC)"
+// @Success 200 {object} map[string][]VoteHistoryPoint
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tiers/{id}/vote-history [get]
+func GetVoteHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path is /tiers/{id}/vote-history
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 4 || parts[3] != "vote-history" {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+	tid, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid tier ID", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "from "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "to "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := database.DB.Select("id").First(&models.Tier{}, tid).Error; err != nil {
+		http.Error(w, "Tier not found", http.StatusNotFound)
+		return
+	}
+
+	query := database.DB.Where("tier_id = ?", tid)
+	if from != nil {
+		query = query.Where("snapshotted_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("snapshotted_at <= ?", *to)
+	}
+
+	var snapshots []models.VoteSnapshot
+	if err := query.Order("snapshotted_at ASC").Find(&snapshots).Error; err != nil {
+		log.WithError(err).Error("Failed to fetch vote history")
+		http.Error(w, "Failed to fetch vote history", http.StatusInternalServerError)
+		return
+	}
+
+	points := make([]VoteHistoryPoint, len(snapshots))
+	for i, s := range snapshots {
+		points[i] = VoteHistoryPoint{Upvotes: s.UpvoteCount, Downvotes: s.DownvoteCount, At: s.SnapshottedAt}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"data": points}); err != nil {
+		log.WithError(err).Error("Failed to encode vote history response")
+	}
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// DeleteScheduledAccounts erases every account whose deletion grace period
+// has passed, then repairs the tier counts their votes and comments fed
+func DeleteScheduledAccounts(ctx context.Context) error {
+	var userIDs []uint
+	if err := database.DB.WithContext(ctx).Unscoped().
+		Model(&models.User{}).
+		Where("deletion_scheduled_at <= ?", time.Now()).
+		Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return EraseUser(tx, userID)
+		}); err != nil {
+			return err
+		}
+		log.WithField("user_id", userID).Info("Deleted account")
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return RepairCounts(ctx)
+}
+
+// EraseUser permanently removes a user and their votes, comments, sessions,
+// notifications, collections, bookmarks and reactions. Their tiers are kept,
+// made private and left without an author. Tokens of remaining sessions are
+// blacklisted. Reaction counts of the comments they reacted to are recounted.
+func EraseUser(tx *gorm.DB, userID uint) error {
+	if err := tx.Unscoped().Model(&models.Tier{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"user_id": gorm.Expr("NULL"), "is_public": false}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO revoked_tokens (jti, user_id, expires_at, created_at)
+		SELECT jti, user_id, expires_at, ? FROM sessions WHERE user_id = ?
+		ON CONFLICT DO NOTHING`, time.Now(), userID).Error; err != nil {
+		return err
+	}
+
+	collections := tx.Model(&models.Collection{}).Select("id").Where("user_id = ?", userID)
+	if err := tx.Where("collection_id IN (?)", collections).Delete(&models.CollectionTier{}).Error; err != nil {
+		return err
+	}
+
+	// Comments the user reacted to, other than their own, need recounting
+	ownComments := tx.Unscoped().Model(&models.Comment{}).Select("id").Where("user_id = ?", userID)
+	var reactedComments []uint
+	if err := tx.Model(&models.CommentReaction{}).
+		Where("user_id = ? AND comment_id NOT IN (?)", userID, ownComments).
+		Distinct().Pluck("comment_id", &reactedComments).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("user_id = ? OR comment_id IN (?)", userID, ownComments).Delete(&models.CommentReaction{}).Error; err != nil {
+		return err
+	}
+
+	if len(reactedComments) > 0 {
+		if err := tx.Exec(`UPDATE comments SET reactions_summary = COALESCE((
+			SELECT jsonb_object_agg(emoji, total) FROM (
+				SELECT emoji, COUNT(*) AS total FROM comment_reactions
+				WHERE comment_reactions.comment_id = comments.id GROUP BY emoji
+			) counts), '{}'::jsonb)
+			WHERE id IN ?`, reactedComments).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, model := range []interface{}{
+		&models.Collection{},
+		&models.Session{},
+		&models.Notification{},
+		&models.Comment{},
+		&models.Vote{},
+		&models.Bookmark{},
+	} {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Unscoped().Delete(&models.User{}, userID).Error
+}
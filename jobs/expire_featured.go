@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"freestealer/cache"
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ExpireFeaturedTiers clears the featured flag of tiers whose featured_until
+// has passed. featured_until is kept as a record of the last promotion.
+func ExpireFeaturedTiers(ctx context.Context) error {
+	var ids []uint
+	if err := database.DB.WithContext(ctx).Model(&models.Tier{}).
+		Where("is_featured = ? AND featured_until < ?", true, time.Now()).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := database.DB.WithContext(ctx).Model(&models.Tier{}).
+		Where("id IN ?", ids).
+		UpdateColumn("is_featured", false).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		cache.Tiers.Invalidate(id)
+	}
+
+	log.WithField("count", len(ids)).Info("Expired featured tiers")
+	return nil
+}
@@ -0,0 +1,20 @@
+// Package jobs holds the recurring background jobs run by the scheduler
+package jobs
+
+import (
+	"time"
+
+	"freestealer/scheduler"
+	"freestealer/views"
+)
+
+// Register adds all background jobs to s
+func Register(s *scheduler.Scheduler) {
+	s.Register(scheduler.Job{Name: "repair-counts", Interval: time.Hour, Fn: RepairCounts})
+	s.Register(scheduler.Job{Name: "prune-revoked-tokens", Interval: time.Hour, Fn: PruneRevokedTokens})
+	s.Register(scheduler.Job{Name: "delete-accounts", Interval: time.Hour, Fn: DeleteScheduledAccounts})
+	s.Register(scheduler.Job{Name: "snapshot-votes", Interval: time.Hour, Fn: SnapshotVotes})
+	s.Register(scheduler.Job{Name: "expire-featured-tiers", Interval: 10 * time.Minute, Fn: ExpireFeaturedTiers})
+	s.Register(scheduler.Job{Name: "flush-view-counts", Interval: 30 * time.Second, Fn: views.Tiers.Flush})
+	s.Register(scheduler.Job{Name: "flush-click-counts", Interval: 30 * time.Second, Fn: views.Clicks.Flush})
+}
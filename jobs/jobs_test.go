@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	host := os.Getenv("TEST_DB_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("TEST_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+
+	user := os.Getenv("TEST_DB_USER")
+	if user == "" {
+		user = "postgres"
+	}
+
+	password := os.Getenv("TEST_DB_PASSWORD")
+	if password == "" {
+		password = "postgres"
+	}
+
+	dbname := os.Getenv("TEST_DB_NAME")
+	if dbname == "" {
+		dbname = "freestealer_test"
+	}
+
+	dsn := "host=" + host + " port=" + port + " user=" + user + " password=" + password + " dbname=" + dbname + " sslmode=disable"
+
+	var err error
+	database.DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test - PostgreSQL not available: %v", err)
+		return
+	}
+
+	database.DB.Exec("DROP SCHEMA IF EXISTS public CASCADE")
+	database.DB.Exec("CREATE SCHEMA public")
+
+	err = database.DB.AutoMigrate(&models.User{}, &models.Tier{}, &models.Vote{}, &models.Comment{}, &models.CommentReaction{}, &models.Notification{}, &models.Session{}, &models.RevokedToken{}, &models.Collection{}, &models.CollectionTier{}, &models.Platform{}, &models.VoteSnapshot{}, &models.Bookmark{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+}
+
+func TestRepairCounts(t *testing.T) {
+	setupTestDB(t)
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	database.DB.Create(&owner)
+	voter := models.User{Username: "voter", Email: "voter@example.com", GitHubID: "voter_gh"}
+	database.DB.Create(&voter)
+
+	tier := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Railway Free", UpvoteCount: 7, CommentCount: 3}
+	database.DB.Create(&tier)
+	database.DB.Create(&models.Vote{UserID: voter.ID, TierID: tier.ID, VoteType: 1})
+	database.DB.Create(&models.Vote{UserID: owner.ID, TierID: tier.ID, VoteType: -1})
+	database.DB.Create(&models.Comment{UserID: voter.ID, TierID: tier.ID, Content: "Nice"})
+	database.DB.Create(&models.Bookmark{UserID: voter.ID, TierID: tier.ID})
+
+	if err := RepairCounts(context.Background()); err != nil {
+		t.Fatalf("RepairCounts() error = %v", err)
+	}
+
+	var repaired models.Tier
+	database.DB.First(&repaired, tier.ID)
+	if repaired.UpvoteCount != 1 || repaired.DownvoteCount != 1 || repaired.CommentCount != 1 || repaired.BookmarksCount != 1 {
+		t.Errorf("Expected counts 1/1/1/1, got %d/%d/%d/%d", repaired.UpvoteCount, repaired.DownvoteCount, repaired.CommentCount, repaired.BookmarksCount)
+	}
+}
+
+func TestPruneRevokedTokens(t *testing.T) {
+	setupTestDB(t)
+
+	database.DB.Create(&models.RevokedToken{JTI: "expired", UserID: 1, ExpiresAt: time.Now().Add(-time.Hour)})
+	database.DB.Create(&models.RevokedToken{JTI: "active", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+
+	if err := PruneRevokedTokens(context.Background()); err != nil {
+		t.Fatalf("PruneRevokedTokens() error = %v", err)
+	}
+
+	var remaining []models.RevokedToken
+	database.DB.Find(&remaining)
+	if len(remaining) != 1 || remaining[0].JTI != "active" {
+		t.Errorf("Expected only the active token to remain, got %+v", remaining)
+	}
+}
+
+func TestDeleteScheduledAccounts(t *testing.T) {
+	setupTestDB(t)
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	leaver := models.User{Username: "leaver", Email: "leaver@example.com", DeletionScheduledAt: &past}
+	database.DB.Create(&leaver)
+	waiting := models.User{Username: "waiting", Email: "waiting@example.com", GitHubID: "waiting_gh", DeletionScheduledAt: &future}
+	database.DB.Create(&waiting)
+
+	ownTier := models.Tier{UserID: leaver.ID, Platform: "Railway", Name: "Leaver Tier", IsPublic: true}
+	database.DB.Create(&ownTier)
+	otherTier := models.Tier{UserID: waiting.ID, Platform: "Render", Name: "Other Tier", UpvoteCount: 1, CommentCount: 1}
+	database.DB.Create(&otherTier)
+	database.DB.Create(&models.Vote{UserID: leaver.ID, TierID: otherTier.ID, VoteType: 1})
+	database.DB.Create(&models.Comment{UserID: leaver.ID, TierID: otherTier.ID, Content: "Bye"})
+	database.DB.Create(&models.Session{UserID: leaver.ID, JTI: "leaver-jti", ExpiresAt: future})
+
+	if err := DeleteScheduledAccounts(context.Background()); err != nil {
+		t.Fatalf("DeleteScheduledAccounts() error = %v", err)
+	}
+
+	var users int64
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", leaver.ID).Count(&users)
+	if users != 0 {
+		t.Error("Expected the user to be hard-deleted")
+	}
+
+	var remaining int64
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", waiting.ID).Count(&remaining)
+	if remaining != 1 {
+		t.Error("Expected the user still in the grace period to be kept")
+	}
+
+	var votes, comments int64
+	database.DB.Unscoped().Model(&models.Vote{}).Where("user_id = ?", leaver.ID).Count(&votes)
+	database.DB.Unscoped().Model(&models.Comment{}).Where("user_id = ?", leaver.ID).Count(&comments)
+	if votes != 0 || comments != 0 {
+		t.Errorf("Expected votes and comments to be hard-deleted, got %d and %d", votes, comments)
+	}
+
+	var tier models.Tier
+	database.DB.First(&tier, ownTier.ID)
+	if tier.UserID != 0 || tier.IsPublic {
+		t.Errorf("Expected an authorless private tier, got user %d public %v", tier.UserID, tier.IsPublic)
+	}
+
+	var other models.Tier
+	database.DB.First(&other, otherTier.ID)
+	if other.UpvoteCount != 0 || other.CommentCount != 0 {
+		t.Errorf("Expected counts to be repaired, got %d upvotes and %d comments", other.UpvoteCount, other.CommentCount)
+	}
+
+	var revoked int64
+	database.DB.Model(&models.RevokedToken{}).Where("jti = ?", "leaver-jti").Count(&revoked)
+	if revoked != 1 {
+		t.Error("Expected the session token to be blacklisted")
+	}
+}
+
+func TestExpireFeaturedTiers(t *testing.T) {
+	setupTestDB(t)
+
+	owner := models.User{Username: "sponsor", Email: "sponsor@example.com"}
+	database.DB.Create(&owner)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	expired := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Expired", IsFeatured: true, FeaturedUntil: &past}
+	database.DB.Create(&expired)
+	current := models.Tier{UserID: owner.ID, Platform: "Render", Name: "Current", IsFeatured: true, FeaturedUntil: &future}
+	database.DB.Create(&current)
+
+	if err := ExpireFeaturedTiers(context.Background()); err != nil {
+		t.Fatalf("ExpireFeaturedTiers() error = %v", err)
+	}
+
+	var tiers []models.Tier
+	database.DB.Order("id").Find(&tiers)
+	if len(tiers) != 2 || tiers[0].IsFeatured || !tiers[1].IsFeatured {
+		t.Errorf("Expected only the expired tier to lose its flag, got %+v", tiers)
+	}
+}
+
+func TestSnapshotVotes(t *testing.T) {
+	setupTestDB(t)
+
+	owner := models.User{Username: "voted", Email: "voted@example.com"}
+	database.DB.Create(&owner)
+
+	voted := models.Tier{UserID: owner.ID, Platform: "Railway", Name: "Voted", UpvoteCount: 4, DownvoteCount: 1}
+	database.DB.Create(&voted)
+	database.DB.Create(&models.Tier{UserID: owner.ID, Platform: "Render", Name: "Unvoted"})
+
+	if err := SnapshotVotes(context.Background()); err != nil {
+		t.Fatalf("SnapshotVotes() error = %v", err)
+	}
+
+	var snapshots []models.VoteSnapshot
+	database.DB.Find(&snapshots)
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot, got %d", len(snapshots))
+	}
+	if snapshots[0].TierID != voted.ID || snapshots[0].UpvoteCount != 4 || snapshots[0].DownvoteCount != 1 {
+		t.Errorf("Unexpected snapshot %+v", snapshots[0])
+	}
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PruneRevokedTokens deletes blacklist entries for tokens that have expired
+// anyway and can no longer be presented
+func PruneRevokedTokens(ctx context.Context) error {
+	result := database.DB.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&models.RevokedToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.WithField("count", result.RowsAffected).Debug("Pruned expired revoked tokens")
+	return nil
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+
+	"freestealer/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// repairCountsQuery recomputes the denormalized tier counters from votes,
+// comments and bookmarks
+const repairCountsQuery = `
+UPDATE tiers SET
+	upvote_count = (SELECT COUNT(*) FROM votes WHERE votes.tier_id = tiers.id AND votes.vote_type = 1 AND votes.deleted_at IS NULL),
+	downvote_count = (SELECT COUNT(*) FROM votes WHERE votes.tier_id = tiers.id AND votes.vote_type = -1 AND votes.deleted_at IS NULL),
+	comment_count = (SELECT COUNT(*) FROM comments WHERE comments.tier_id = tiers.id AND comments.deleted_at IS NULL),
+	bookmarks_count = (SELECT COUNT(*) FROM bookmarks WHERE bookmarks.tier_id = tiers.id)
+WHERE upvote_count <> (SELECT COUNT(*) FROM votes WHERE votes.tier_id = tiers.id AND votes.vote_type = 1 AND votes.deleted_at IS NULL)
+	OR downvote_count <> (SELECT COUNT(*) FROM votes WHERE votes.tier_id = tiers.id AND votes.vote_type = -1 AND votes.deleted_at IS NULL)
+	OR comment_count <> (SELECT COUNT(*) FROM comments WHERE comments.tier_id = tiers.id AND comments.deleted_at IS NULL)
+	OR bookmarks_count <> (SELECT COUNT(*) FROM bookmarks WHERE bookmarks.tier_id = tiers.id)`
+
+// RepairCounts fixes tier vote, comment and bookmark counters that drifted from the
+// rows they summarize
+func RepairCounts(ctx context.Context) error {
+	result := database.DB.WithContext(ctx).Exec(repairCountsQuery)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.WithField("tiers", result.RowsAffected).Warn("Repaired drifted tier counts")
+	}
+	return nil
+}
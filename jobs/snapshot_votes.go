@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"freestealer/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapshotVotes records the current vote counts of every tier with at least
+// one vote, building the history served by GET /tiers/{id}/vote-history
+func SnapshotVotes(ctx context.Context) error {
+	result := database.DB.WithContext(ctx).Exec(`
+		INSERT INTO vote_snapshots (tier_id, upvote_count, downvote_count, snapshotted_at)
+		SELECT id, upvote_count, downvote_count, ?
+		FROM tiers
+		WHERE deleted_at IS NULL AND (upvote_count > 0 OR downvote_count > 0)`, time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.WithField("count", result.RowsAffected).Info("Recorded vote snapshots")
+	return nil
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"freestealer/auth"
+	"freestealer/cache"
 	"freestealer/database"
 	"freestealer/docs"
+	"freestealer/features"
+	"freestealer/jobs"
+	"freestealer/scheduler"
+	"freestealer/views"
 
 	"github.com/joho/godotenv"
 	log "github.com/sirupsen/logrus"
@@ -32,6 +42,9 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
+	rollback := flag.Int("rollback", 0, "Roll back the given number of most recent migrations and exit")
+	flag.Parse()
+
 	// Configure logrus
 	log.SetFormatter(&log.TextFormatter{
 		FullTimestamp: true,
@@ -45,6 +58,18 @@ func main() {
 		log.Warn("No .env file found")
 	}
 
+	// Roll back migrations instead of serving when requested
+	if *rollback > 0 {
+		if err := database.Connect(); err != nil {
+			log.WithError(err).Fatal("Failed to connect to database")
+		}
+		if err := database.Rollback(database.DB, *rollback); err != nil {
+			log.WithError(err).Fatal("Failed to roll back migrations")
+		}
+		log.WithField("steps", *rollback).Info("Migrations rolled back")
+		return
+	}
+
 	// Initialize database (PostgreSQL)
 	if err := database.InitDatabase(); err != nil {
 		log.WithError(err).Fatal("Failed to initialize database")
@@ -53,6 +78,12 @@ func main() {
 	// Initialize authentication
 	auth.InitAuth()
 
+	// Initialize the tier read cache
+	cache.InitTierCache()
+
+	// Read which optional endpoints are switched on
+	features.Init()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -69,20 +100,51 @@ func main() {
 	log.WithField("port", port).Info("Starting server")
 
 	// Setup all routes
-	SetupRoutes(port)
+	handler := SetupRoutes(port)
+
+	// Cancelled on SIGINT/SIGTERM to shut down the server and background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start recurring background jobs
+	jobScheduler := scheduler.New()
+	jobs.Register(jobScheduler)
+	jobScheduler.Start(ctx)
 
 	log.WithField("address", "http://localhost:"+port).Info("Server listening")
 	log.Info("Swagger UI available at http://localhost:" + port + "/swagger/index.html")
 
 	server := &http.Server{
 		Addr:         ":" + port,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	err := server.ListenAndServe()
-	if err != nil {
-		log.WithError(err).Fatal("Server failed to start")
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Fatal("Server failed to start")
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Error("Server shutdown failed")
+	}
+	jobScheduler.Wait()
+
+	// Write views and clicks counted since the last flush
+	if err := views.Tiers.Flush(context.Background()); err != nil {
+		log.WithError(err).Error("Failed to flush view counts")
+	}
+	if err := views.Clicks.Flush(context.Background()); err != nil {
+		log.WithError(err).Error("Failed to flush click counts")
 	}
+
+	log.Info("Server stopped")
 }
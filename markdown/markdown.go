@@ -0,0 +1,120 @@
+// Package markdown renders the safe Markdown subset supported in tier
+// descriptions: paragraphs, headings, bullet and numbered lists, bold,
+// italic, inline code and http(s) links.
+//
+// The input is HTML-escaped before any Markdown is interpreted, so raw HTML
+// in the source can never reach the output.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletPattern  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedPattern = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	boldPattern    = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	italicPattern  = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*|\b_(\S(?:[^_]*?\S)?)_\b`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)]+)\)`)
+)
+
+// Render converts Markdown source to sanitized HTML
+func Render(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + inline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			closeList()
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + inline(strings.TrimSpace(m[2])) + "</h" + level + ">\n")
+			continue
+		}
+
+		if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + inline(m[1]) + "</li>\n")
+			continue
+		}
+
+		if m := orderedPattern.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + inline(m[1]) + "</li>\n")
+			continue
+		}
+
+		closeList()
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+	flushParagraph()
+	closeList()
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// inline renders inline markup within a single block. Text between
+// backticks is emitted as code and is not interpreted further.
+func inline(s string) string {
+	segments := strings.Split(s, "`")
+	// An unmatched trailing backtick is kept as literal text
+	if len(segments)%2 == 0 {
+		last := len(segments) - 1
+		segments[last-1] += "`" + segments[last]
+		segments = segments[:last]
+	}
+
+	var out strings.Builder
+	for i, seg := range segments {
+		if i%2 == 1 {
+			out.WriteString("<code>" + html.EscapeString(seg) + "</code>")
+			continue
+		}
+		out.WriteString(emphasis(html.EscapeString(seg)))
+	}
+	return out.String()
+}
+
+// emphasis renders links, bold and italic text in already escaped input
+func emphasis(s string) string {
+	s = linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := linkPattern.FindStringSubmatch(m)
+		return `<a href="` + parts[2] + `" rel="nofollow noopener">` + parts[1] + `</a>`
+	})
+	s = boldPattern.ReplaceAllString(s, "<strong>$1$2</strong>")
+	return italicPattern.ReplaceAllString(s, "<em>$1$2</em>")
+}
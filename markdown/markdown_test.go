@@ -0,0 +1,35 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Empty", "", ""},
+		{"Paragraph", "Great for hobby projects", "<p>Great for hobby projects</p>"},
+		{"Bold and italic", "**Free** forever, *no* card", "<p><strong>Free</strong> forever, <em>no</em> card</p>"},
+		{"Inline code", "Run `railway up` to deploy", "<p>Run <code>railway up</code> to deploy</p>"},
+		{"Code is literal", "`**not bold**`", "<p><code>**not bold**</code></p>"},
+		{"Heading", "## Limits", "<h2>Limits</h2>"},
+		{"Bullet list", "- 512MB RAM\n- 1GB disk", "<ul>\n<li>512MB RAM</li>\n<li>1GB disk</li>\n</ul>"},
+		{"Ordered list", "1. Sign up\n2. Deploy", "<ol>\n<li>Sign up</li>\n<li>Deploy</li>\n</ol>"},
+		{"Paragraph then list", "Includes:\n- CPU", "<p>Includes:</p>\n<ul>\n<li>CPU</li>\n</ul>"},
+		{"Link", "[Pricing](https://railway.app/pricing)", `<p><a href="https://railway.app/pricing" rel="nofollow noopener">Pricing</a></p>`},
+		{"Unsafe link scheme", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>"},
+		{"Raw HTML escaped", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"Snake case untouched", "use free_tier_limit", "<p>use free_tier_limit</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.input); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
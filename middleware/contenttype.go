@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// RequireJSON answers 415 Unsupported Media Type to POST, PUT and PATCH
+// requests whose body is not declared as application/json, optionally with
+// charset=utf-8. Other methods and requests without a body pass through, so
+// body-less actions such as POST /tiers/{id}/restore keep working.
+func RequireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !hasBody(r) || isJSONContentType(r.Header.Get("Content-Type")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"error":   "unsupported_media_type",
+			"message": "Request body must be sent as Content-Type: application/json",
+		}); err != nil {
+			Logger(r.Context()).WithError(err).Error("Failed to encode content type response")
+		}
+	})
+}
+
+// hasBody reports whether the request carries a body. ContentLength is -1
+// when the length is unknown, as with chunked uploads.
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// isJSONContentType reports whether contentType is application/json with no
+// charset or the utf-8 one
+func isJSONContentType(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return false
+	}
+	charset, ok := params["charset"]
+	return !ok || strings.EqualFold(charset, "utf-8")
+}
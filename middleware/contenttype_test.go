@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireJSON(t *testing.T) {
+	handler := RequireJSON(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		expected    int
+	}{
+		{"JSON POST", http.MethodPost, "application/json", `{}`, http.StatusOK},
+		{"JSON with utf-8 charset", http.MethodPut, "application/json; charset=utf-8", `{}`, http.StatusOK},
+		{"Charset is case-insensitive", http.MethodPatch, "application/json; charset=UTF-8", `{}`, http.StatusOK},
+		{"Plain text POST", http.MethodPost, "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"Form PUT", http.MethodPut, "application/x-www-form-urlencoded", `a=b`, http.StatusUnsupportedMediaType},
+		{"Missing content type", http.MethodPatch, "", `{}`, http.StatusUnsupportedMediaType},
+		{"Other charset", http.MethodPost, "application/json; charset=latin1", `{}`, http.StatusUnsupportedMediaType},
+		{"POST without a body", http.MethodPost, "", ``, http.StatusOK},
+		{"GET passes through", http.MethodGet, "text/plain", `{}`, http.StatusOK},
+		{"DELETE passes through", http.MethodDelete, "", `{}`, http.StatusOK},
+		{"HEAD passes through", http.MethodHead, "", ``, http.StatusOK},
+		{"OPTIONS passes through", http.MethodOptions, "text/plain", `x`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/tiers", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if tt.expected == http.StatusUnsupportedMediaType {
+				var body map[string]string
+				json.NewDecoder(w.Body).Decode(&body)
+				if body["error"] != "unsupported_media_type" {
+					t.Errorf("Unexpected error body: %v", body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// GetClientIP returns the originating client IP of a request. It prefers the
+// first address in X-Forwarded-For, then X-Real-IP, then the remote peer
+// address, with any port stripped.
+func GetClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := stripPort(strings.TrimSpace(first)); ip != "" {
+			return ip
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return stripPort(realIP)
+	}
+
+	return stripPort(r.RemoteAddr)
+}
+
+// stripPort removes a trailing port from an address if present
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return strings.Trim(addr, "[]")
+}
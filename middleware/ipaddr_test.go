@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwarded  string
+		realIP     string
+		remoteAddr string
+		want       string
+	}{
+		{"Remote address", "", "", "192.0.2.1:1234", "192.0.2.1"},
+		{"Remote address without port", "", "", "192.0.2.1", "192.0.2.1"},
+		{"IPv6 remote address", "", "", "[2001:db8::1]:1234", "2001:db8::1"},
+		{"X-Real-IP", "", "198.51.100.7", "10.0.0.1:1234", "198.51.100.7"},
+		{"X-Forwarded-For single", "203.0.113.5", "", "10.0.0.1:1234", "203.0.113.5"},
+		{"X-Forwarded-For list", "203.0.113.5, 70.41.3.18, 150.172.238.178", "", "10.0.0.1:1234", "203.0.113.5"},
+		{"X-Forwarded-For with port", "203.0.113.5:8080", "", "10.0.0.1:1234", "203.0.113.5"},
+		{"X-Forwarded-For wins over X-Real-IP", "203.0.113.5", "198.51.100.7", "10.0.0.1:1234", "203.0.113.5"},
+		{"Empty X-Forwarded-For entry", " , 70.41.3.18", "198.51.100.7", "10.0.0.1:1234", "198.51.100.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := GetClientIP(req); got != tt.want {
+				t.Errorf("GetClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestRequestLogger(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	tests := []struct {
+		name      string
+		status    int
+		wantLevel log.Level
+	}{
+		{"Success", http.StatusOK, log.InfoLevel},
+		{"Redirect", http.StatusFound, log.InfoLevel},
+		{"Client error", http.StatusNotFound, log.WarnLevel},
+		{"Server error", http.StatusInternalServerError, log.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+
+			var seenID string
+			handler := RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seenID = RequestID(r.Context())
+				w.WriteHeader(tt.status)
+				w.Write([]byte("hello"))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/tiers?page=2", nil)
+			req.Header.Set("User-Agent", "test-agent")
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			entry := hook.LastEntry()
+			if entry == nil {
+				t.Fatal("Expected a log entry")
+			}
+			if entry.Level != tt.wantLevel {
+				t.Errorf("Expected level %v, got %v", tt.wantLevel, entry.Level)
+			}
+			if entry.Data["status"] != tt.status || entry.Data["bytes"] != 5 {
+				t.Errorf("Expected status %d and 5 bytes, got %v and %v", tt.status, entry.Data["status"], entry.Data["bytes"])
+			}
+			if entry.Data["query"] != "page=2" || entry.Data["user_agent"] != "test-agent" {
+				t.Errorf("Unexpected request fields: %v", entry.Data)
+			}
+			if seenID == "" || entry.Data["request_id"] != seenID || w.Header().Get(RequestIDHeader) != seenID {
+				t.Errorf("Expected request ID %q in context, log and header", seenID)
+			}
+		})
+	}
+
+	t.Run("Reuses incoming request ID", func(t *testing.T) {
+		handler := RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(RequestIDHeader, "abc123")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if got := hook.LastEntry().Data["request_id"]; got != "abc123" {
+			t.Errorf("Expected request_id 'abc123', got %v", got)
+		}
+		if got := hook.LastEntry().Data["status"]; got != http.StatusOK {
+			t.Errorf("Expected implicit status 200, got %v", got)
+		}
+	})
+}
+
+func TestRequestLoggerHijack(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	done := make(chan struct{})
+	handler := RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+		buf.Flush()
+	}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	<-done
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204 from hijacked connection, got %d", resp.StatusCode)
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Data["status"] != http.StatusSwitchingProtocols {
+		t.Errorf("Expected a log entry with status 101, got %v", entry)
+	}
+}
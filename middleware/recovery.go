@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Recovery turns a panicking handler into a 500 response instead of a dropped
+// connection. The panic and its stack trace are logged with the request ID,
+// so Recovery belongs inside RequestLogger.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
+			Logger(r.Context()).WithFields(log.Fields{
+				"panic":  err,
+				"stack":  string(debug.Stack()),
+				"method": r.Method,
+				"path":   r.URL.Path,
+			}).Error("Handler panicked")
+
+			// Nothing can be sent once the handler started its response
+			if rec.status != 0 {
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			if err := json.NewEncoder(w).Encode(map[string]string{
+				"error":   "internal_server_error",
+				"message": "An unexpected error occurred",
+			}); err != nil {
+				Logger(r.Context()).WithError(err).Error("Failed to encode panic response")
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestRecovery(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	t.Run("Panic before writing", func(t *testing.T) {
+		hook.Reset()
+		handler := RequestLogger(Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tier *struct{ Name string }
+			_ = tier.Name
+		})))
+
+		req := httptest.NewRequest(http.MethodGet, "/tiers/1", nil)
+		req.Header.Set(RequestIDHeader, "panic123")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		var body map[string]string
+		json.NewDecoder(w.Body).Decode(&body)
+		if body["error"] != "internal_server_error" || body["message"] != "An unexpected error occurred" {
+			t.Errorf("Unexpected error body: %v", body)
+		}
+
+		var panicEntry *log.Entry
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "Handler panicked" {
+				panicEntry = entry
+			}
+		}
+		if panicEntry == nil {
+			t.Fatal("Expected the panic to be logged")
+		}
+		if panicEntry.Level != log.ErrorLevel || panicEntry.Data["request_id"] != "panic123" {
+			t.Errorf("Expected an error entry with the request ID, got %v %v", panicEntry.Level, panicEntry.Data)
+		}
+		if stack, _ := panicEntry.Data["stack"].(string); !strings.Contains(stack, "recovery_test.go") {
+			t.Errorf("Expected a stack trace through the handler, got %q", stack)
+		}
+		if got := hook.LastEntry().Data["status"]; got != http.StatusInternalServerError {
+			t.Errorf("Expected the request to be logged with status 500, got %v", got)
+		}
+	})
+
+	t.Run("Panic after writing", func(t *testing.T) {
+		handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte("partial"))
+			panic("late failure")
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != http.StatusAccepted || w.Body.String() != "partial" {
+			t.Errorf("Expected the started response untouched, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Abort handler is re-raised", func(t *testing.T) {
+		handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		defer func() {
+			if recover() != http.ErrAbortHandler {
+				t.Error("Expected http.ErrAbortHandler to propagate")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
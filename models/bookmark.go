@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Bookmark is a tier a user saved for later. Removing a bookmark deletes the
+// row, so a tier can be bookmarked again.
+type Bookmark struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index:idx_user_tier_bookmark,unique" json:"user_id"`
+	TierID    uint      `gorm:"not null;index:idx_user_tier_bookmark,unique;index" json:"tier_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// Collection represents a user-curated, named list of tiers
+type Collection struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	Name        string    `gorm:"not null;size:200" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	IsPublic    bool      `gorm:"default:true" json:"is_public"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relations
+	User    User             `gorm:"foreignKey:UserID" json:"-"`
+	Entries []CollectionTier `gorm:"foreignKey:CollectionID" json:"-"`
+
+	// Set when a single collection is fetched, ordered by position
+	Tiers []Tier `gorm:"-" json:"tiers,omitempty"`
+}
+
+// CollectionTier places a tier in a collection at a given position
+type CollectionTier struct {
+	CollectionID uint `gorm:"primaryKey" json:"collection_id"`
+	TierID       uint `gorm:"primaryKey;index" json:"tier_id"`
+	Position     int  `gorm:"not null;default:0" json:"position"`
+
+	// Relations
+	Tier Tier `gorm:"foreignKey:TierID" json:"-"`
+}
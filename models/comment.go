@@ -16,6 +16,9 @@ type Comment struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Reaction counts by emoji (denormalized from comment_reactions)
+	ReactionsSummary ReactionCounts `gorm:"type:jsonb;default:'{}'" json:"reactions_summary" swaggertype:"object,integer"`
+
 	// Relations
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Tier Tier `gorm:"foreignKey:TierID" json:"tier,omitempty"`
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CommentReaction is one user's emoji reaction to a comment
+type CommentReaction struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index:idx_user_comment_emoji,unique" json:"user_id"`
+	CommentID uint      `gorm:"not null;index:idx_user_comment_emoji,unique;index" json:"comment_id"`
+	Emoji     string    `gorm:"not null;size:10;index:idx_user_comment_emoji,unique" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionCounts maps an emoji to the number of users who reacted with it,
+// stored as a JSONB object
+type ReactionCounts map[string]int
+
+// Value encodes the counts as a JSON object for the database
+func (c ReactionCounts) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(map[string]int(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Scan decodes a JSON object read from the database
+func (c *ReactionCounts) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = ReactionCounts{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ReactionCounts", value)
+	}
+
+	counts := ReactionCounts{}
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return err
+	}
+	*c = counts
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// CountryList is a list of ISO 3166-1 alpha-2 country codes stored as a JSON
+// array in a text column. The empty string is an empty list. In API bodies it
+// is a plain JSON array, e.g. ["US", "CA"].
+type CountryList string
+
+// NewCountryList encodes codes as a CountryList, upper-casing each code.
+// An empty list is stored as "".
+func NewCountryList(codes []string) CountryList {
+	if len(codes) == 0 {
+		return ""
+	}
+	upper := make([]string, len(codes))
+	for i, code := range codes {
+		upper[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	encoded, _ := json.Marshal(upper)
+	return CountryList(encoded)
+}
+
+// Codes returns the country codes in the list, or nil if it is empty or malformed
+func (l CountryList) Codes() []string {
+	if l == "" {
+		return nil
+	}
+	var codes []string
+	if err := json.Unmarshal([]byte(l), &codes); err != nil {
+		return nil
+	}
+	return codes
+}
+
+// MarshalJSON writes the list as a JSON array
+func (l CountryList) MarshalJSON() ([]byte, error) {
+	codes := l.Codes()
+	if codes == nil {
+		codes = []string{}
+	}
+	return json.Marshal(codes)
+}
+
+// UnmarshalJSON reads a JSON array of country codes; null and [] clear the list
+func (l *CountryList) UnmarshalJSON(data []byte) error {
+	var codes []string
+	if err := json.Unmarshal(data, &codes); err != nil {
+		return err
+	}
+	*l = NewCountryList(codes)
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 	"time"
@@ -50,7 +51,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db.Exec("CREATE SCHEMA public")
 
 	// Run migrations
-	err = db.AutoMigrate(&User{}, &Tier{}, &Vote{}, &Comment{})
+	err = db.AutoMigrate(&User{}, &Tier{}, &Vote{}, &Comment{}, &CommentReaction{}, &Notification{}, &Session{}, &RevokedToken{}, &Collection{}, &CollectionTier{}, &Platform{}, &VoteSnapshot{}, &Bookmark{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -325,3 +326,58 @@ func TestSoftDelete(t *testing.T) {
 		}
 	})
 }
+
+func TestCountryList(t *testing.T) {
+	list := NewCountryList([]string{"us", " ca "})
+	if list != `["US","CA"]` {
+		t.Errorf("Expected upper-cased JSON array, got %q", list)
+	}
+
+	encoded, err := json.Marshal(struct {
+		Geo CountryList `json:"geo"`
+	}{})
+	if err != nil || string(encoded) != `{"geo":[]}` {
+		t.Errorf("Expected empty list to encode as [], got %s (%v)", encoded, err)
+	}
+
+	var decoded struct {
+		Geo CountryList `json:"geo"`
+	}
+	if err := json.Unmarshal([]byte(`{"geo": ["de", "FR"]}`), &decoded); err != nil {
+		t.Fatalf("Failed to decode country list: %v", err)
+	}
+	if codes := decoded.Geo.Codes(); len(codes) != 2 || codes[0] != "DE" || codes[1] != "FR" {
+		t.Errorf("Expected [DE FR], got %v", codes)
+	}
+
+	if err := json.Unmarshal([]byte(`{"geo": []}`), &decoded); err != nil || decoded.Geo != "" {
+		t.Errorf("Expected [] to clear the list, got %q (%v)", decoded.Geo, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"geo": "US"}`), &decoded); err == nil {
+		t.Error("Expected an error for a non-array value")
+	}
+}
+
+func TestReactionCounts(t *testing.T) {
+	var empty ReactionCounts
+	if v, err := empty.Value(); err != nil || v != "{}" {
+		t.Errorf("Expected nil counts to store as {}, got %v (%v)", v, err)
+	}
+
+	var counts ReactionCounts
+	if err := counts.Scan([]byte(`{"👍": 5, "🎉": 1}`)); err != nil {
+		t.Fatalf("Failed to scan reaction counts: %v", err)
+	}
+	if counts["👍"] != 5 || counts["🎉"] != 1 {
+		t.Errorf("Expected 👍=5 and 🎉=1, got %v", counts)
+	}
+
+	if err := counts.Scan(nil); err != nil || len(counts) != 0 {
+		t.Errorf("Expected NULL to scan as no reactions, got %v (%v)", counts, err)
+	}
+
+	if err := counts.Scan(42); err == nil {
+		t.Error("Expected an error scanning a non-JSON value")
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationTierUpvoted  = "tier_upvoted"
+	NotificationCommentAdded = "comment_added"
+	NotificationTierVerified = "tier_verified"
+)
+
+// Notification represents an in-app notification delivered to a user
+type Notification struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index:idx_notifications_user_read" json:"user_id"`
+	Type         string    `gorm:"not null;size:50" json:"type"`          // tier_upvoted, comment_added, tier_verified
+	ResourceID   uint      `gorm:"not null" json:"resource_id"`           // ID of the tier or comment
+	ResourceType string    `gorm:"not null;size:50" json:"resource_type"` // tier or comment
+	IsRead       bool      `gorm:"default:false;index:idx_notifications_user_read" json:"is_read"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents a login session backed by a pair of JWT tokens sharing one jti
+type Session struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	JTI        string    `gorm:"uniqueIndex;not null;size:64" json:"-"`
+	DeviceInfo string    `gorm:"size:255" json:"device_info"`
+	IPAddress  string    `gorm:"size:45" json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // last time tokens were issued for this session
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`
+
+	// Set when listing sessions for the caller
+	Current bool `gorm:"-" json:"current"`
+}
+
+// RevokedToken represents a blacklisted JWT that must no longer be accepted
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	JTI       string    `gorm:"uniqueIndex;not null;size:64" json:"jti"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"` // entries can be pruned after this time
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -6,14 +6,58 @@ import (
 	"gorm.io/gorm"
 )
 
+// Tier review statuses
+const (
+	TierStatusDraft    = "draft"
+	TierStatusPending  = "pending"
+	TierStatusApproved = "approved"
+	TierStatusRejected = "rejected"
+)
+
+// Tier pricing models
+const (
+	PricingFreeForever = "free-forever"
+	PricingFreeTrial   = "free-trial"
+	PricingFreemium    = "freemium"
+)
+
+// Tier service categories
+const (
+	CategoryCompute    = "compute"
+	CategoryDatabase   = "database"
+	CategoryStorage    = "storage"
+	CategoryCDN        = "cdn"
+	CategoryEmail      = "email"
+	CategoryQueue      = "queue"
+	CategoryCache      = "cache"
+	CategoryMonitoring = "monitoring"
+	CategoryOther      = "other"
+)
+
+// TierCategories lists every tier category in display order
+var TierCategories = []string{
+	CategoryCompute,
+	CategoryDatabase,
+	CategoryStorage,
+	CategoryCDN,
+	CategoryEmail,
+	CategoryQueue,
+	CategoryCache,
+	CategoryMonitoring,
+	CategoryOther,
+}
+
 // Tier represents a free tier hosting platform information
 type Tier struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
-	UserID      uint   `gorm:"not null;index" json:"user_id"`
-	Platform    string `gorm:"not null;size:100;index" json:"platform"` // e.g., Railway, Koyeb, Vercel
-	Name        string `gorm:"not null;size:200" json:"name"`
+	UserID      uint   `gorm:"index;uniqueIndex:idx_unique_user_platform_name,where:deleted_at IS NULL" json:"user_id"`                    // 0 (NULL) once the author's account is deleted
+	Platform    string `gorm:"not null;size:100;index;uniqueIndex:idx_unique_user_platform_name,where:deleted_at IS NULL" json:"platform"` // e.g., Railway, Koyeb, Vercel
+	Name        string `gorm:"not null;size:200;uniqueIndex:idx_unique_user_platform_name,where:deleted_at IS NULL" json:"name"`           // unique per user and platform among non-deleted tiers
 	Description string `gorm:"type:text" json:"description"`
 	IsPublic    bool   `gorm:"default:true;index" json:"is_public"`
+	Status      string `gorm:"size:20;default:approved;index" json:"status"` // draft, pending, approved, rejected
+
+	RejectionReason string `gorm:"size:255" json:"rejection_reason,omitempty"` // shown to the owner of a rejected tier
 
 	// Tier details
 	CPULimit       string `gorm:"size:50" json:"cpu_limit"`
@@ -21,12 +65,29 @@ type Tier struct {
 	StorageLimit   string `gorm:"size:50" json:"storage_limit"`
 	BandwidthLimit string `gorm:"size:50" json:"bandwidth_limit"`
 	MonthlyHours   string `gorm:"size:50" json:"monthly_hours"`
+	PricingModel   string `gorm:"size:20;default:'free-forever';index" json:"pricing_model" enums:"free-forever,free-trial,freemium"` // free-forever, free-trial, freemium
+	Category       string `gorm:"size:50;index" json:"category" enums:"compute,database,storage,cdn,email,queue,cache,monitoring,other"`
 	URL            string `gorm:"size:500" json:"url"`
+	ScreenshotURL  string `gorm:"size:500" json:"screenshot_url"`
+
+	// Availability
+	GeoRestrictions CountryList `gorm:"type:text" json:"geo_restrictions" swaggertype:"array,string"` // countries the tier is available in, empty if unrestricted
+
+	// Outbound traffic
+	EgressFree    bool    `gorm:"default:false;index" json:"egress_free"` // no charges for egress
+	EgressLimitGB float64 `json:"egress_limit_gb"`                        // monthly egress cap in GB, 0 if none is published
+
+	// Promotion: featured tiers are listed first until FeaturedUntil passes
+	IsFeatured    bool       `gorm:"default:false;index" json:"is_featured"`
+	FeaturedUntil *time.Time `json:"featured_until"`
 
 	// Stats (denormalized for performance)
-	UpvoteCount   int `gorm:"default:0;index" json:"upvote_count"`
-	DownvoteCount int `gorm:"default:0" json:"downvote_count"`
-	CommentCount  int `gorm:"default:0" json:"comment_count"`
+	UpvoteCount    int `gorm:"default:0;index" json:"upvote_count"`
+	DownvoteCount  int `gorm:"default:0" json:"downvote_count"`
+	CommentCount   int `gorm:"default:0" json:"comment_count"`
+	ViewCount      int `gorm:"default:0;index" json:"view_count"` // flushed in batches, may lag behind by up to 30 seconds
+	ClickCount     int `gorm:"default:0" json:"click_count"`      // visits through GET /tiers/{id}/visit, batched like ViewCount
+	BookmarksCount int `gorm:"default:0;index" json:"bookmarks_count"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
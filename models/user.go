@@ -6,12 +6,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator" // may review tier submissions
+	RoleAdmin     = "admin"
+)
+
+// ValidRole reports whether role is one of the user roles
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleModerator || role == RoleAdmin
+}
+
 // User represents a user in the system
 type User struct {
 	ID       uint   `gorm:"primaryKey" json:"id"`
 	Username string `gorm:"uniqueIndex;not null;size:50" json:"username"`
 	Email    string `gorm:"uniqueIndex;not null;size:100" json:"email"`
 	Password string `gorm:"size:255" json:"-"` // Hashed password, hidden from JSON
+	Role     string `gorm:"size:20;default:user;index" json:"role"`
+	Bio      string `gorm:"size:500" json:"bio,omitempty"`
 
 	// GitHub OAuth fields
 	GitHubID     string `gorm:"size:50" json:"github_id,omitempty"` // Unique index created manually in database.go
@@ -20,12 +34,26 @@ type User struct {
 	AccessToken  string `gorm:"size:500" json:"-"` // Hidden from JSON
 	RefreshToken string `gorm:"size:500" json:"-"` // Hidden from JSON
 
+	// GitHub profile, refreshed from the GitHub API on every GitHub login
+	Company     string `gorm:"size:100" json:"company,omitempty"`
+	Location    string `gorm:"size:100" json:"location,omitempty"`
+	Blog        string `gorm:"size:255" json:"blog,omitempty"`
+	PublicRepos int    `json:"public_repos,omitempty"`
+
+	LastLoginAt  *time.Time `json:"last_login_at"`
+	LastExportAt *time.Time `json:"last_export_at,omitempty"` // last GDPR data export, limits exports to one per day
+
+	// Set when the user asked to delete their account; the account is erased
+	// once this time passes unless they log in again first
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
-	Tiers    []Tier    `gorm:"foreignKey:UserID" json:"tiers,omitempty"`
-	Votes    []Vote    `gorm:"foreignKey:UserID" json:"votes,omitempty"`
-	Comments []Comment `gorm:"foreignKey:UserID" json:"comments,omitempty"`
+	Tiers       []Tier       `gorm:"foreignKey:UserID" json:"tiers,omitempty"`
+	Votes       []Vote       `gorm:"foreignKey:UserID" json:"votes,omitempty"`
+	Comments    []Comment    `gorm:"foreignKey:UserID" json:"comments,omitempty"`
+	Collections []Collection `gorm:"foreignKey:UserID" json:"collections,omitempty"`
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// VoteSnapshot records a tier's vote counts at one point in time, so the
+// evolution of its popularity can be charted
+type VoteSnapshot struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TierID        uint      `gorm:"not null;index:idx_vote_snapshots_tier_time" json:"tier_id"`
+	UpvoteCount   int       `gorm:"not null" json:"upvote_count"`
+	DownvoteCount int       `gorm:"not null" json:"downvote_count"`
+	SnapshottedAt time.Time `gorm:"not null;index:idx_vote_snapshots_tier_time" json:"snapshotted_at"`
+}
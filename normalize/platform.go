@@ -0,0 +1,66 @@
+package normalize
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// platformAliasesJSON maps lowercase platform spellings to their canonical name.
+// Add entries there to merge new variants without touching code.
+//
+//go:embed platform_aliases.json
+var platformAliasesJSON []byte
+
+var platformAliases = mustLoadAliases(platformAliasesJSON)
+
+// domainSuffixes are stripped from platform names such as "Railway.app"
+var domainSuffixes = []string{".app", ".io", ".com", ".dev", ".sh", ".tech", ".net", ".org"}
+
+// mustLoadAliases parses the embedded alias map, panicking on malformed JSON
+func mustLoadAliases(data []byte) map[string]string {
+	var aliases map[string]string
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		panic("normalize: invalid platform_aliases.json: " + err.Error())
+	}
+	return aliases
+}
+
+// NormalizePlatform returns the canonical spelling of a platform name so that
+// "railway", "Railway" and "Railway.app" are stored and filtered as one platform
+func NormalizePlatform(name string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	key = strings.TrimRight(key, ".,;:!?")
+	if key == "" {
+		return ""
+	}
+
+	if canonical, ok := platformAliases[key]; ok {
+		return canonical
+	}
+
+	for _, suffix := range domainSuffixes {
+		if trimmed := strings.TrimSuffix(key, suffix); trimmed != key && trimmed != "" {
+			key = trimmed
+			break
+		}
+	}
+
+	if canonical, ok := platformAliases[key]; ok {
+		return canonical
+	}
+
+	return titleCase(key)
+}
+
+// titleCase upper-cases the first letter of every space or hyphen separated word
+func titleCase(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == ' ' || runes[i-1] == '-' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,33 @@
+package normalize
+
+import (
+	"testing"
+)
+
+func TestNormalizePlatform(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"railway", "Railway"},
+		{"Railway", "Railway"},
+		{"  RAILWAY  ", "Railway"},
+		{"Railway.app", "Railway"},
+		{"railway.app.", "Railway"},
+		{"fly.io", "Fly.io"},
+		{"Digital   Ocean", "DigitalOcean"},
+		{"unknown host", "Unknown Host"},
+		{"acme.dev", "Acme"},
+		{"my-cloud", "My-Cloud"},
+		{"", ""},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizePlatform(tt.input); got != tt.want {
+				t.Errorf("NormalizePlatform(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
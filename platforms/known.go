@@ -0,0 +1,41 @@
+// Package platforms holds the curated list of well-known free-tier platforms
+package platforms
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"freestealer/models"
+)
+
+// knownJSON lists well-known platforms with canonical names, as produced by
+// normalize.NormalizePlatform. Add entries there to offer new platforms
+// without touching code.
+//
+//go:embed known.json
+var knownJSON []byte
+
+var known = mustLoadKnown(knownJSON)
+
+// mustLoadKnown parses the embedded platform list, panicking on malformed JSON
+func mustLoadKnown(data []byte) []models.Platform {
+	var platforms []models.Platform
+	if err := json.Unmarshal(data, &platforms); err != nil {
+		panic("platforms: invalid known.json: " + err.Error())
+	}
+	return platforms
+}
+
+// Known returns the well-known platforms, ready to be inserted as records
+func Known() []models.Platform {
+	return append([]models.Platform(nil), known...)
+}
+
+// Names returns the canonical names of the well-known platforms
+func Names() []string {
+	names := make([]string, len(known))
+	for i, platform := range known {
+		names[i] = platform.Name
+	}
+	return names
+}
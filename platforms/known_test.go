@@ -0,0 +1,28 @@
+package platforms
+
+import (
+	"testing"
+
+	"freestealer/normalize"
+)
+
+func TestKnownPlatformsAreCanonical(t *testing.T) {
+	platforms := Known()
+	if len(platforms) != 50 {
+		t.Errorf("Expected 50 known platforms, got %d", len(platforms))
+	}
+
+	seen := make(map[string]bool)
+	for _, platform := range platforms {
+		if got := normalize.NormalizePlatform(platform.Name); got != platform.Name {
+			t.Errorf("Known platform %q normalizes to %q", platform.Name, got)
+		}
+		if platform.URL == "" {
+			t.Errorf("Known platform %q has no URL", platform.Name)
+		}
+		if seen[platform.Name] {
+			t.Errorf("Duplicate known platform %q", platform.Name)
+		}
+		seen[platform.Name] = true
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"freestealer/auth"
+	"freestealer/features"
 	"freestealer/handlers"
+	"freestealer/middleware"
 
 	log "github.com/sirupsen/logrus"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -24,24 +26,62 @@ func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			"/auth/github",
 			"/auth/github/callback",
 			"/auth/refresh",
+			"/auth/introspect",
+			"/auth/logout",
+			"/sitemap",
+			"/features",
+			"/platforms/autocomplete",
 			"/swagger/",
 		}
 
 		// Check if path starts with any public path
 		for _, publicPath := range publicPaths {
 			if strings.HasPrefix(path, publicPath) {
-				next(w, r)
+				auth.OptionalJWTAuth(next)(w, r)
 				return
 			}
 		}
 
+		// Public read-only endpoints nested under protected resources
+		if strings.HasPrefix(path, "/users/") && strings.HasSuffix(path, "/stats") {
+			auth.OptionalJWTAuth(next)(w, r)
+			return
+		}
+
+		// Tier URL redirects are followed from browsers without a token
+		if r.Method == http.MethodGet && strings.HasPrefix(path, "/tiers/") && strings.HasSuffix(path, "/visit") {
+			auth.OptionalJWTAuth(next)(w, r)
+			return
+		}
+
+		// Reactions to a comment can be read without a token
+		if r.Method == http.MethodGet && strings.HasPrefix(path, "/comments/") && strings.HasSuffix(path, "/reactions") {
+			auth.OptionalJWTAuth(next)(w, r)
+			return
+		}
+
+		// Tiers can be read without a token (GET /tiers and GET /tiers/{id});
+		// writes and the other /tiers/{id}/... routes except /visit stay protected
+		if r.Method == http.MethodGet && (path == "/tiers" || (strings.HasPrefix(path, "/tiers/") && strings.Count(path, "/") == 2)) {
+			auth.OptionalJWTAuth(next)(w, r)
+			return
+		}
+
 		// For protected routes, require JWT token
 		auth.RequireJWTAuth(next)(w, r)
 	}
 }
 
-// SetupRoutes configures all HTTP routes for the application
-func SetupRoutes(port string) {
+// jsonAPI is authMiddleware for routes that read JSON bodies: POST, PUT and
+// PATCH requests whose body is not application/json get 415
+func jsonAPI(next http.HandlerFunc) http.HandlerFunc {
+	return authMiddleware(middleware.RequireJSON(next).ServeHTTP)
+}
+
+// SetupRoutes configures all HTTP routes for the application and returns the
+// handler to serve them with: every request is logged, and a panicking handler
+// answers 500 instead of dropping the connection
+func SetupRoutes(port string) http.Handler {
 	// Health check (public)
 	http.HandleFunc("/health", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		log.Debug("Health check called")
@@ -52,16 +92,22 @@ func SetupRoutes(port string) {
 	}))
 
 	// Authentication endpoints (public)
-	http.HandleFunc("/auth/register", authMiddleware(auth.RegisterHandler))
-	http.HandleFunc("/auth/login", authMiddleware(auth.LoginHandler))
+	http.HandleFunc("/auth/register", jsonAPI(auth.RegisterHandler))
+	http.HandleFunc("/auth/login", jsonAPI(auth.LoginHandler))
 	http.HandleFunc("/auth/github", authMiddleware(auth.BeginAuthHandler))
 	http.HandleFunc("/auth/github/callback", authMiddleware(auth.CallbackHandler))
 	http.HandleFunc("/auth/logout", authMiddleware(auth.LogoutHandler))
 	http.HandleFunc("/auth/me", authMiddleware(auth.GetCurrentUser))
-	http.HandleFunc("/auth/refresh", authMiddleware(auth.RefreshTokenHandler))
+	http.HandleFunc("/auth/me/export", authMiddleware(auth.ExportDataHandler))
+	http.HandleFunc("/auth/me/delete", jsonAPI(auth.DeleteAccountHandler))
+	http.HandleFunc("/auth/refresh", jsonAPI(auth.RefreshTokenHandler))
+	http.HandleFunc("/auth/introspect", jsonAPI(auth.IntrospectHandler))
+	http.HandleFunc("/auth/change-password", jsonAPI(auth.ChangePasswordHandler))
+	http.HandleFunc("/auth/sessions", authMiddleware(auth.SessionsHandler))
+	http.HandleFunc("/auth/sessions/", authMiddleware(auth.SessionsHandler))
 
 	// User endpoints (protected)
-	http.HandleFunc("/users", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/users", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			handlers.GetUsers(w, r)
@@ -72,8 +118,34 @@ func SetupRoutes(port string) {
 		}
 	}))
 
+	http.HandleFunc("/users/", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stats") {
+			handlers.GetUserStats(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/avatar") {
+			handlers.UpdateUserAvatar(w, r)
+			return
+		}
+		// /users/{id} with no further segment
+		if strings.Count(r.URL.Path, "/") != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetUser(w, r)
+		case http.MethodPut:
+			handlers.UpdateUser(w, r)
+		case http.MethodDelete:
+			handlers.DeleteUser(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
 	// Tier endpoints (protected)
-	http.HandleFunc("/tiers", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/tiers", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			handlers.GetTiers(w, r)
@@ -84,7 +156,37 @@ func SetupRoutes(port string) {
 		}
 	}))
 
-	http.HandleFunc("/tiers/", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/tiers/", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/tiers/submit-url":
+			handlers.SubmitTierURL(w, r)
+			return
+		case r.URL.Path == "/tiers/batch":
+			handlers.CreateTiersBatch(w, r)
+			return
+		case r.URL.Path == "/tiers/compare":
+			handlers.CompareTiers(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/screenshot"):
+			handlers.UpdateTierScreenshot(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/clone"):
+			handlers.CloneTier(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/bookmark"):
+			handlers.ToggleBookmark(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/comments"):
+			handlers.GetTierComments(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/vote-history"):
+			handlers.GetVoteHistory(w, r)
+			return
+		case strings.HasSuffix(r.URL.Path, "/visit"):
+			handlers.VisitTier(w, r)
+			return
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			handlers.GetTier(w, r)
@@ -97,11 +199,73 @@ func SetupRoutes(port string) {
 		}
 	}))
 
+	// Collection endpoints (protected)
+	http.HandleFunc("/collections", jsonAPI(handlers.CreateCollection))
+	http.HandleFunc("/collections/", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/tiers/"):
+			handlers.RemoveCollectionTier(w, r)
+		case strings.HasSuffix(r.URL.Path, "/tiers"):
+			handlers.AddCollectionTier(w, r)
+		default:
+			handlers.GetCollection(w, r)
+		}
+	}))
+
+	// Category endpoints (protected)
+	http.HandleFunc("/categories", authMiddleware(handlers.GetCategories))
+
+	// Platform endpoints (protected, autocomplete is public)
+	http.HandleFunc("/platforms", authMiddleware(handlers.GetPlatforms))
+	http.HandleFunc("/platforms/", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/platforms/stats":
+			handlers.GetTopPlatformStats(w, r)
+		case r.URL.Path == "/platforms/autocomplete":
+			handlers.GetPlatformAutocomplete(w, r)
+		case strings.HasSuffix(r.URL.Path, "/stats"):
+			handlers.GetPlatformStats(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	// Notification endpoints (protected)
+	http.HandleFunc("/notifications", authMiddleware(handlers.GetNotifications))
+	http.HandleFunc("/notifications/mark-read", jsonAPI(handlers.MarkNotificationsRead))
+
+	// Leaderboard endpoint (protected)
+	http.HandleFunc("/leaderboard", authMiddleware(handlers.GetLeaderboard))
+
+	// Admin endpoints (protected, admin role required)
+	http.HandleFunc("/admin/tiers", authMiddleware(handlers.GetAdminTiers))
+	http.HandleFunc("/admin/users", authMiddleware(handlers.GetAdminUsers))
+	http.HandleFunc("/admin/platforms/merge", jsonAPI(handlers.MergePlatforms))
+	http.HandleFunc("/admin/platforms/duplicates", authMiddleware(handlers.GetPlatformDuplicates))
+	http.HandleFunc("/admin/tiers/", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/admin/tiers/bulk-status":
+			handlers.BulkSetTierStatus(w, r)
+		case strings.HasSuffix(r.URL.Path, "/approve"):
+			handlers.ApproveTier(w, r)
+		case strings.HasSuffix(r.URL.Path, "/reject"):
+			handlers.RejectTier(w, r)
+		case strings.HasSuffix(r.URL.Path, "/restore"):
+			handlers.RestoreTier(w, r)
+		case strings.HasSuffix(r.URL.Path, "/purge"):
+			handlers.PurgeTier(w, r)
+		case strings.HasSuffix(r.URL.Path, "/feature"):
+			handlers.FeatureTier(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
 	// Vote endpoint (protected)
-	http.HandleFunc("/votes", authMiddleware(handlers.VoteTier))
+	http.HandleFunc("/votes", jsonAPI(handlers.VoteTier))
 
 	// Comment endpoints (protected)
-	http.HandleFunc("/comments", authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/comments", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			handlers.GetComments(w, r)
@@ -112,10 +276,49 @@ func SetupRoutes(port string) {
 		}
 	}))
 
-	http.HandleFunc("/comments/", authMiddleware(handlers.DeleteComment))
+	http.HandleFunc("/comments/", jsonAPI(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/reactions") {
+			switch r.Method {
+			case http.MethodGet:
+				handlers.GetCommentReactions(w, r)
+			case http.MethodPost:
+				handlers.ToggleCommentReaction(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+		handlers.DeleteComment(w, r)
+	}))
+
+	// Enabled optional features (public)
+	http.HandleFunc("/features", authMiddleware(handlers.GetFeatures))
+
+	// Live vote counts over WebSocket
+	if features.Current.EnableWebSockets {
+		http.HandleFunc("/ws/tiers/", authMiddleware(handlers.StreamTierVotes))
+	}
+
+	// Read-only GraphQL queries
+	if features.Current.EnableGraphQL {
+		http.HandleFunc("/graphql", authMiddleware(handlers.GraphQL))
+	}
+
+	// Sitemap (public); pages of a large sitemap live at /sitemap-{n}.xml,
+	// which the mux can only match through the root pattern
+	http.HandleFunc("/sitemap.xml", authMiddleware(handlers.GetSitemap))
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/sitemap-") && strings.HasSuffix(r.URL.Path, ".xml") {
+			authMiddleware(handlers.GetSitemapPage)(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	})
 
 	// Swagger documentation (public)
 	http.HandleFunc("/swagger/", authMiddleware(httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	)))
+
+	return middleware.RequestLogger(middleware.Recovery(http.DefaultServeMux))
 }
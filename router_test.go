@@ -0,0 +1,160 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"freestealer/auth"
+	"freestealer/features"
+)
+
+func TestAuthMiddlewarePublicTierReads(t *testing.T) {
+	handler := authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{http.MethodGet, "/tiers", http.StatusOK},
+		{http.MethodGet, "/tiers/1", http.StatusOK},
+		{http.MethodGet, "/tiers/compare", http.StatusOK},
+		{http.MethodPost, "/tiers", http.StatusUnauthorized},
+		{http.MethodPut, "/tiers/1", http.StatusUnauthorized},
+		{http.MethodPatch, "/tiers/1", http.StatusUnauthorized},
+		{http.MethodDelete, "/tiers/1", http.StatusUnauthorized},
+		{http.MethodGet, "/tiers/1/screenshot", http.StatusUnauthorized},
+		{http.MethodGet, "/users", http.StatusUnauthorized},
+		{http.MethodPut, "/users/1", http.StatusUnauthorized},
+		{http.MethodDelete, "/users/1", http.StatusUnauthorized},
+		{http.MethodGet, "/tiers/1/visit", http.StatusOK},
+		{http.MethodGet, "/comments/1/reactions", http.StatusOK},
+		{http.MethodPost, "/comments/1/reactions", http.StatusUnauthorized},
+		{http.MethodGet, "/features", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("%s %s without a token: expected status %d, got %d", tt.method, tt.path, tt.expected, w.Code)
+		}
+	}
+}
+
+func TestAuthMiddlewareIgnoresSpoofedIdentity(t *testing.T) {
+	var userID uint
+	var authenticated bool
+	var role string
+	handler := authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		userID, authenticated = auth.UserIDFromContext(r.Context())
+		role = auth.UserRoleFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/tiers", "/tiers/1", "/users/1/stats", "/health"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-User-ID", "1")
+		req.Header.Set("X-User-Role", "admin")
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, w.Code)
+		}
+		if authenticated || role != "" {
+			t.Errorf("GET %s: client identity headers authenticated the request (user %d, role %q)", path, userID, role)
+		}
+	}
+}
+
+func TestSetupRoutesRegistersRoutes(t *testing.T) {
+	features.Current = features.FeatureFlags{EnableGraphQL: true, EnableWebSockets: true}
+	defer func() { features.Current = features.FeatureFlags{} }()
+
+	// SetupRoutes registers on the default mux, so it can only run once per test binary
+	server := httptest.NewServer(SetupRoutes("0"))
+	defer server.Close()
+
+	// Requests chosen to be answered before any database access: public
+	// routes that need no data, malformed bodies, or protected routes
+	// without a token
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/health", ""},
+		{http.MethodGet, "/features", ""},
+		{http.MethodPost, "/auth/register", "{"},
+		{http.MethodPost, "/auth/login", "{"},
+		{http.MethodPost, "/auth/refresh", "{"},
+		{http.MethodGet, "/auth/me", ""},
+		{http.MethodGet, "/auth/me/export", ""},
+		{http.MethodPost, "/auth/me/delete", "{}"},
+		{http.MethodPost, "/auth/change-password", "{}"},
+		{http.MethodGet, "/auth/sessions", ""},
+		{http.MethodGet, "/users", ""},
+		{http.MethodPut, "/users/1", "{}"},
+		{http.MethodPatch, "/users/1/avatar", "{}"},
+		{http.MethodPost, "/tiers", "{}"},
+		{http.MethodPost, "/tiers/batch", "[]"},
+		{http.MethodGet, "/tiers/compare?ids=x", ""},
+		{http.MethodPut, "/tiers/1", "{}"},
+		{http.MethodPost, "/tiers/1/clone", ""},
+		{http.MethodPost, "/tiers/1/bookmark", ""},
+		{http.MethodPost, "/collections", "{}"},
+		{http.MethodGet, "/collections/1", ""},
+		{http.MethodGet, "/categories", ""},
+		{http.MethodGet, "/platforms", ""},
+		{http.MethodGet, "/platforms/stats", ""},
+		{http.MethodGet, "/notifications", ""},
+		{http.MethodGet, "/leaderboard", ""},
+		{http.MethodGet, "/admin/tiers", ""},
+		{http.MethodPost, "/admin/tiers/1/approve", ""},
+		{http.MethodPost, "/votes", "{}"},
+		{http.MethodPost, "/comments", "{}"},
+		{http.MethodDelete, "/comments/1", ""},
+		{http.MethodPost, "/graphql", "{}"},
+		{http.MethodGet, "/ws/tiers/1", ""},
+	}
+
+	for _, tt := range tests {
+		var body io.Reader
+		if tt.body != "" {
+			body = strings.NewReader(tt.body)
+		}
+		req, err := http.NewRequest(tt.method, server.URL+tt.path, body)
+		if err != nil {
+			t.Fatalf("Failed to build %s %s: %v", tt.method, tt.path, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			t.Errorf("%s %s: expected a registered route, got 404", tt.method, tt.path)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/no-such-route")
+	if err != nil {
+		t.Fatalf("GET /no-such-route: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /no-such-route: expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,44 @@
+package sanitize
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrTooMuchMarkup is returned when most of a value consisted of HTML
+var ErrTooMuchMarkup = errors.New("value contains too much HTML markup")
+
+// maxStrippedRatio is the largest share of a value that may be removed as markup
+const maxStrippedRatio = 0.5
+
+var (
+	// scriptPattern matches script and style elements including their content
+	scriptPattern = regexp.MustCompile(`(?is)<script[^>]*>.*?</script\s*>|<style[^>]*>.*?</style\s*>`)
+	// tagPattern matches any remaining HTML tag or comment
+	tagPattern = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z!][^>]*>`)
+)
+
+// StripTags removes all HTML tags from s, dropping the content of script and style elements.
+// Stripping is repeated until the value stops changing so that nested markup such as
+// "<<b>script>" cannot re-form a tag once the inner one is removed.
+func StripTags(s string) string {
+	for {
+		stripped := scriptPattern.ReplaceAllString(s, "")
+		stripped = tagPattern.ReplaceAllString(stripped, "")
+		if stripped == s {
+			return s
+		}
+		s = stripped
+	}
+}
+
+// Text sanitizes a text-only field by stripping all HTML tags. It returns
+// ErrTooMuchMarkup if more than half of the original value was removed.
+func Text(input string) (string, error) {
+	cleaned := StripTags(input)
+	if input != "" && float64(len(input)-len(cleaned))/float64(len(input)) > maxStrippedRatio {
+		return "", ErrTooMuchMarkup
+	}
+	return strings.TrimSpace(cleaned), nil
+}
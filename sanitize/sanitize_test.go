@@ -0,0 +1,77 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain text", "Railway Free Tier", "Railway Free Tier"},
+		{"Inline tags", "<b>Railway</b> Free", "Railway Free"},
+		{"Script element", "Free<script>alert(1)</script>", "Free"},
+		{"Style element", "Free<style>body{}</style>", "Free"},
+		{"Comment", "Free<!-- hidden -->", "Free"},
+		{"Comparison kept", "CPU < 1 vCPU", "CPU < 1 vCPU"},
+		{"Attribute handler", `<img src=x onerror="alert(1)">Free`, "Free"},
+		{"Nested script", "Free<<b>script>alert(1)<</b>/script>", "Free"},
+		{"Nested img", "Free<<i>img src=x onerror=alert(1)>", "Free"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripTags(tt.input); got != tt.want {
+				t.Errorf("StripTags(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Run("Clean value", func(t *testing.T) {
+		got, err := Text("  512MB  ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != "512MB" {
+			t.Errorf("Expected '512MB', got %q", got)
+		}
+	})
+
+	t.Run("Small amount of markup", func(t *testing.T) {
+		got, err := Text("Great for <em>hobby</em> projects and side experiments")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != "Great for hobby projects and side experiments" {
+			t.Errorf("Unexpected result %q", got)
+		}
+	})
+
+	t.Run("Mostly markup", func(t *testing.T) {
+		if _, err := Text("<script>document.location='http://evil'</script>x"); err != ErrTooMuchMarkup {
+			t.Errorf("Expected ErrTooMuchMarkup, got %v", err)
+		}
+	})
+
+	t.Run("Nested markup", func(t *testing.T) {
+		got, err := Text("A generous free tier for hobby projects <<i>img src=x onerror=alert(1)>")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.ContainsAny(got, "<>") {
+			t.Errorf("Expected no markup to survive, got %q", got)
+		}
+	})
+
+	t.Run("Empty value", func(t *testing.T) {
+		got, err := Text("")
+		if err != nil || got != "" {
+			t.Errorf("Expected empty result without error, got %q, %v", got, err)
+		}
+	})
+}
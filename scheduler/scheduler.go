@@ -0,0 +1,87 @@
+// Package scheduler runs recurring background jobs on fixed intervals
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Job is a unit of background work run every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Fn       func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs until its context is cancelled
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job. Jobs must be registered before Start is called.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every registered job on its own ticker until ctx is cancelled.
+// The first run happens one interval after Start.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			log.WithField("job", job.Name).Warn("Skipping job with non-positive interval")
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					run(ctx, job)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(job)
+
+		log.WithFields(log.Fields{
+			"job":      job.Name,
+			"interval": job.Interval,
+		}).Info("Scheduled job")
+	}
+}
+
+// Wait blocks until all job goroutines have returned after ctx is cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// run executes one job and logs how long it took
+func run(ctx context.Context, job Job) {
+	start := time.Now()
+	err := job.Fn(ctx)
+
+	entry := log.WithFields(log.Fields{
+		"job":      job.Name,
+		"duration": time.Since(start),
+	})
+	if err != nil {
+		entry.WithError(err).Error("Job failed")
+		return
+	}
+	entry.Info("Job completed")
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsJobsUntilCancelled(t *testing.T) {
+	var runs, failures atomic.Int32
+
+	s := New()
+	s.Register(Job{
+		Name:     "counter",
+		Interval: 10 * time.Millisecond,
+		Fn: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+	s.Register(Job{
+		Name:     "failing",
+		Interval: 10 * time.Millisecond,
+		Fn: func(ctx context.Context) error {
+			failures.Add(1)
+			return errors.New("boom")
+		},
+	})
+	s.Register(Job{
+		Name:     "disabled",
+		Interval: 0,
+		Fn: func(ctx context.Context) error {
+			t.Error("Job with zero interval should not run")
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	time.Sleep(55 * time.Millisecond)
+	cancel()
+	s.Wait()
+
+	if runs.Load() < 2 {
+		t.Errorf("Expected counter job to run at least twice, ran %d times", runs.Load())
+	}
+	if failures.Load() < 2 {
+		t.Errorf("Expected a failing job to keep running, ran %d times", failures.Load())
+	}
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Error("Expected no runs after the context was cancelled")
+	}
+}
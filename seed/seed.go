@@ -0,0 +1,21 @@
+// Package seed holds reference data used to pre-populate a fresh database
+package seed
+
+import (
+	"freestealer/platforms"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SeedPlatforms inserts the embedded known platforms, skipping names that
+// already exist. It returns the number of platforms inserted.
+func SeedPlatforms(db *gorm.DB) (int64, error) {
+	known := platforms.Known()
+
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&known)
+	return result.RowsAffected, result.Error
+}
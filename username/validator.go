@@ -0,0 +1,77 @@
+// Package username validates usernames chosen at registration
+package username
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinLength is the shortest allowed username
+	MinLength = 3
+	// MaxLength is the longest allowed username, matching the users.username column
+	MaxLength = 50
+)
+
+// reservedUsernamesTXT lists names reserved for staff and system use.
+//
+//go:embed reserved_usernames.txt
+var reservedUsernamesTXT string
+
+// profanityTXT lists words that may not appear in a username.
+//
+//go:embed profanity.txt
+var profanityTXT string
+
+var (
+	reserved  = loadWordList(reservedUsernamesTXT)
+	profanity = loadWordList(profanityTXT)
+)
+
+// allowedPattern matches usernames made of letters, digits, underscores and hyphens
+var allowedPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// loadWordList parses a newline separated list, skipping blank lines and # comments
+func loadWordList(data string) map[string]bool {
+	words := make(map[string]bool)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[line] = true
+	}
+	return words
+}
+
+// ValidateUsername checks length, allowed characters, reserved names and
+// profanity. Profane words are matched as the whole name or as one of its
+// underscore or hyphen separated parts, so ordinary names containing them
+// by accident are still accepted.
+func ValidateUsername(s string) error {
+	if n := utf8.RuneCountInString(s); n < MinLength || n > MaxLength {
+		return fmt.Errorf("username must be between %d and %d characters", MinLength, MaxLength)
+	}
+
+	if !allowedPattern.MatchString(s) {
+		return errors.New("username may only contain letters, digits, underscores and hyphens")
+	}
+
+	name := strings.ToLower(s)
+	if reserved[name] {
+		return fmt.Errorf("username %q is reserved", s)
+	}
+
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	for _, part := range append(parts, name) {
+		if profanity[part] {
+			return errors.New("username contains inappropriate language")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package username
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"Valid", "jane_doe-42", false},
+		{"Minimum length", "abc", false},
+		{"Maximum length", strings.Repeat("a", MaxLength), false},
+		{"Too short", "ab", true},
+		{"Too long", strings.Repeat("a", MaxLength+1), true},
+		{"Space", "jane doe", true},
+		{"Punctuation", "jane.doe", true},
+		{"Non-ASCII letters", "jöhn", true},
+		{"Reserved", "admin", true},
+		{"Reserved any case", "Root", true},
+		{"Reserved word inside a name", "admin_jane", false},
+		{"Profane", "shit", true},
+		{"Profane part", "big-Shit_42", true},
+		{"Profane word inside a longer word", "classic", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUsername(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUsername(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package views batches tier view and click counts in memory so popular
+// tiers do not take a database write per request
+package views
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"freestealer/database"
+	"freestealer/models"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Counter accumulates views per tier until they are flushed
+type Counter struct {
+	// column is the tiers column the counts are added to
+	column string
+	// pending maps tier IDs to *int64 view counts not yet written
+	pending sync.Map
+}
+
+// Tiers is the shared counter for tier views
+var Tiers = &Counter{column: "view_count"}
+
+// Clicks is the shared counter for visits to tiers' official URLs
+var Clicks = &Counter{column: "click_count"}
+
+// Record counts one view of a tier
+func (c *Counter) Record(tierID uint) {
+	v, ok := c.pending.Load(tierID)
+	if !ok {
+		v, _ = c.pending.LoadOrStore(tierID, new(int64))
+	}
+	atomic.AddInt64(v.(*int64), 1)
+}
+
+// Pending returns the number of views of a tier not yet flushed
+func (c *Counter) Pending(tierID uint) int64 {
+	v, ok := c.pending.Load(tierID)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt64(v.(*int64))
+}
+
+// Flush adds the accumulated views to each tier's counter column. Counts that
+// fail to be written are kept for the next flush.
+func (c *Counter) Flush(ctx context.Context) error {
+	var firstErr error
+	flushed := 0
+
+	c.pending.Range(func(key, value interface{}) bool {
+		count := value.(*int64)
+		n := atomic.SwapInt64(count, 0)
+		if n == 0 {
+			return true
+		}
+
+		tierID := key.(uint)
+		err := database.DB.WithContext(ctx).Model(&models.Tier{}).
+			Where("id = ?", tierID).
+			UpdateColumn(c.column, gorm.Expr(c.column+" + ?", n)).Error
+		if err != nil {
+			atomic.AddInt64(count, n)
+			if firstErr == nil {
+				firstErr = err
+			}
+			return true
+		}
+
+		flushed++
+		return true
+	})
+
+	if flushed > 0 {
+		log.WithFields(log.Fields{"column": c.column, "tiers": flushed}).Debug("Flushed tier counts")
+	}
+	return firstErr
+}
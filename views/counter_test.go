@@ -0,0 +1,31 @@
+package views
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounterRecord(t *testing.T) {
+	c := &Counter{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Record(1)
+		}()
+	}
+	wg.Wait()
+	c.Record(2)
+
+	if got := c.Pending(1); got != 100 {
+		t.Errorf("Expected 100 pending views for tier 1, got %d", got)
+	}
+	if got := c.Pending(2); got != 1 {
+		t.Errorf("Expected 1 pending view for tier 2, got %d", got)
+	}
+	if got := c.Pending(3); got != 0 {
+		t.Errorf("Expected no pending views for tier 3, got %d", got)
+	}
+}